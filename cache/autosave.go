@@ -0,0 +1,93 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：autosave.go
+ * 内容摘要：定时将缓存保存到文件。
+ * 其他说明：WithAutoSave 启用后 NewCache 启动 saveLoop，每隔一个周期调用 SaveMemToFile；
+ *           保存失败(例如磁盘暂时不可写)时周期加倍，不超过上限，避免以原频率反复写入故障磁盘；
+ *           下一次保存成功后周期恢复为初始值。失败由 SaveMemToFile 记录到日志。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"time"
+)
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：定时保存缓存到文件
+ * 输入参数：停止信号管道：stop chan struct{}
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，周期由 nextSaveInterval 决定
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) saveLoop(stop chan struct{}) {
+	interval := thisCache.autoSaveBase
+	timer := time.NewTimer(interval)
+	for {
+		select {
+		case <-timer.C:
+			err := thisCache.SaveMemToFile(thisCache.autoSaveFile)
+			interval = thisCache.nextSaveInterval(interval, err)
+			timer.Reset(interval)
+		case <-stop:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：计算下一次保存的周期
+ * 输入参数：本次周期：interval time.Duration, 本次保存的错误：err error
+ * 输出参数：无
+ * 返 回 值：下一次保存的周期
+ * 其他说明：该函数为 Cache 类方法，失败时加倍且不超过 autoSaveMax，成功时恢复为 autoSaveBase
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) nextSaveInterval(interval time.Duration, err error) time.Duration {
+	if err == nil {
+		return thisCache.autoSaveBase
+	}
+	interval *= 2
+	if interval > thisCache.autoSaveMax {
+		interval = thisCache.autoSaveMax
+	}
+	thisCache.logger.Debugf("cache auto save failed, retry in %v", interval)
+	return interval
+}
+
+/***************************************************************************************
+ * 功能描述：停止定时保存
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，未启用 WithAutoSave 时直接返回；可重复调用；
+ *           不会中断正在进行的保存
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) StopAutoSave() {
+	if thisCache.autoSaveStop == nil {
+		return
+	}
+	thisCache.autoSaveOnce.Do(func() {
+		close(thisCache.autoSaveStop)
+	})
+}
@@ -0,0 +1,80 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：autosave_test.go
+ * 内容摘要：定时保存测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：测试定时保存的退避：失败时周期加倍且不超过上限，成功后恢复为初始周期
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestNextSaveInterval(t *testing.T) {
+	cacher, _ := NewCache(0, 0, WithAutoSave("", time.Second, 5*time.Second))
+	failed := errors.New("disk full")
+	interval := time.Second
+	for _, want := range []time.Duration{2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		if interval = cacher.nextSaveInterval(interval, failed); interval != want {
+			t.Fatalf("interval after failure = %v, want %v", interval, want)
+		}
+	}
+	if interval = cacher.nextSaveInterval(interval, nil); interval != time.Second {
+		t.Errorf("interval after success = %v, want 1s", interval)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 WithAutoSave：目录不可写时按退避周期重试，目录恢复后保存成功
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：保存到不存在的目录模拟磁盘故障，从日志中读取重试周期
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestAutoSave(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "missing")
+	file := filepath.Join(dir, "cache.gob")
+	logger := &recordLogger{}
+	cacher, _ := NewCache(0, 0, WithLogger(logger), WithAutoSave(file, 10*time.Millisecond, 40*time.Millisecond))
+	defer cacher.StopAutoSave()
+	cacher.Set("a", 1, NoExpiration)
+
+	time.Sleep(150 * time.Millisecond)
+	logger.expect(t, "debug: cache auto save failed, retry in 20ms")
+	logger.expect(t, "debug: cache auto save failed, retry in 40ms")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if _, err := os.Stat(file); err == nil {
+			return
+		}
+	}
+	t.Error("auto save did not write the file after the directory was created")
+}
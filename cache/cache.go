@@ -20,14 +20,22 @@ package cache
  ****************************************************************************************/
 // 包
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
 	"encoding/gob"
 	"errors"
 	"fmt"
+	"hash"
 	"hash/crc32"
 	"io"
 	"log"
+	"math/rand"
 	"os"
+	"path/filepath"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -37,6 +45,10 @@ import (
 type Item struct { // 缓存中存储的数据项结构
 	Object     interface{} // 缓存中存储的数据项
 	Expiration int64       // 该数据项生存的时间
+	LastAccess int64       // 该数据项最近一次被访问的时间
+	Version    uint64      // 该数据项的版本号，每次写入递增
+	Created    int64       // 该数据项被写入的时间，覆盖写入时重置
+	Hits       uint64      // 该数据项被访问的次数，记录访问时间时累加，覆盖写入时清零
 }
 
 type Cache struct { // 缓存系统结构
@@ -44,9 +56,82 @@ type Cache struct { // 缓存系统结构
 	items             map[string]Item // 用于存储缓存数据项
 	mux               sync.RWMutex    // 读写锁
 	gcInterval        time.Duration   // 过期数据项清理周期
-	stopGc            chan bool       // 是否停止缓存回收清理
+	stopGc            chan bool       // 是否停止缓存回收清理，由 gcMux 保护
+	loader            LoaderFunc      // 未命中时加载数据项的函数
+	negativeTTL       time.Duration   // 加载结果为不存在时，墓碑数据项的生命周期
+	idleTTL           time.Duration   // 数据项闲置多久后过期，0 表示不启用
+	codec             Codec           // Save/Load 使用的编解码器
+	gcMinInterval     time.Duration   // 自适应清理周期的下限，0 表示不启用自适应
+	gcMaxInterval     time.Duration   // 自适应清理周期的上限
+	gcMux             sync.Mutex      // 保护 gcInterval、gcPaused、gcRunning、gcSince 的锁
+	gcPaused          bool            // 是否暂停缓存回收清理
+	gcRunning         bool            // gcLoop 是否正在运行
+	gcSince           time.Time       // gcLoop 最近一次启动、恢复或修改周期的时间
+	gcReset           chan bool       // 通知 gcLoop 重新读取清理周期
+	gcNoJitter        bool            // 是否关闭 gcLoop 首次清理的随机延迟
+	gcLazy            bool            // 是否推迟到首次写入时才启动 gcLoop
+	gcOnce            sync.Once       // 保证推迟的 gcLoop 只在首次写入时启动一次
+	onEvicted         evictFunc       // 数据项被移出缓存时的回调
+	maxValueBytes     int             // 单个数据项值的最大字节数，0 表示不限制
+	gobSizeValues     bool            // 是否按 gob 编码长度限制非 []byte/string 类型的值
+	logger            Logger          // 日志，默认不输出
+	initialCapacity   int             // items 的初始容量提示
+	trackAccess       atomic.Bool     // 未启用 idleTTL 时是否也记录访问时间，设置 LRU 配额时可能在运行中启用
+	copier            CopyFunc        // 读取时复制数据项的值的函数，nil 表示不复制
+	version           uint64          // 最近一次写入分配的版本号，由 mux 保护
+	backoffBase       time.Duration   // loader 失败后的初始退避时间，0 表示不启用
+	backoffMax        time.Duration   // loader 失败后的最大退避时间
+	count             atomic.Int64    // items 中的数据项数量，在写锁内随 items 一起更新
+	callMux           sync.Mutex      // 保护 calls、loads 与 batchLoads 的锁
+	calls             loadCalls       // GetOrLoad、GetOrSetFunc 正在进行的加载，用于合并同一 key 的并发加载
+	loads             loadCalls       // Get 调用 loader 正在进行的加载
+	batchLoads        loadCalls       // GetOrLoadMulti 正在进行的加载
+	peak              int64           // 自上次重建 items 以来数据项数量的峰值，由 mux 保护
+	compactRatio      float64         // 数据项数量低于峰值的该比例时 gcLoop 重建 items，0 表示不启用
+	indexes           indexSet        // 二级索引，由 mux 保护
+	subscribers       []*Subscription // 变更事件的订阅者，由 mux 保护，修改时整体替换
+	pending           []Event         // 写锁期间记录的变更事件，由 unlock 发送
+	walPath           string          // 增量持久化日志的路径，为空表示不启用
+	wal               *writeLog       // 增量持久化日志，由 mux 保护
+	shardHasher       hashFunc        // SetKey 使用的哈希函数
+	hasher            hashFunc        // Put 按内容寻址使用的哈希函数
+	sizer             SizeFunc        // 估算数据项的值大小的函数，nil 表示使用默认估算
+	preExpiry         PreExpiryFunc   // 数据项即将过期时的刷新函数，nil 表示不刷新
+	preExpiryLead     time.Duration   // 在过期之前多久刷新
+	noRecover         bool            // 是否关闭回调函数 panic 的恢复
+	keyValidator      KeyValidator    // 校验与规范化键名的函数，nil 表示只拒绝空键名
+	autoSaveFile      string          // 定时保存的文件，为空表示不启用
+	autoSaveBase      time.Duration   // 定时保存的初始周期
+	autoSaveMax       time.Duration   // 保存失败后退避的最大周期
+	autoSaveStop      chan struct{}   // 停止 saveLoop 的管道
+	autoSaveOnce      sync.Once       // 保证 autoSaveStop 只关闭一次
+	deleteOnRead      bool            // 读取到已过期的数据项时是否立即删除
+	closed            atomic.Bool     // 是否已调用 Close
+	scanMux           sync.Mutex      // 保护 scanSnap 的锁
+	scanSnap          []scanEntry     // Scan 使用的按哈希排序的键名快照
+	panics            atomic.Int64    // 被恢复的回调函数 panic 次数
+	statMux           sync.Mutex      // 保护 gcStats、lastGcRun 的锁
+	gcStats           GCStats         // 过期数据项清理的统计
+	lastGcRun         time.Time       // 最近一次清理(包括 onEvicted 回调)完成的时间
+	valueCodec        Codec           // 存储数据项的值使用的编解码器，nil 表示直接存储
+	maxItems          int             // 数据项数量上限，0 表示不限制
+	evictBatch        int             // 超过上限时一次移出的数据项数量
+	overflow          []keyAndValue   // 写锁期间因超过上限被移出、待调用 onEvicted 的数据项
+	quotas            []*nsQuota      // 命名空间的配额，由 mux 保护
+	cow               *cowState       // 写时复制的快照，nil 表示未启用 WithCopyOnWrite
+	warming           atomic.Bool     // WarmFromFile 的预热是否正在进行
+	lockWait          time.Duration   // Set 等获取写锁的超时时间，0 表示不限制
+	writers           chan struct{}   // 写者令牌，启用 WithLockTimeout 时持有写锁须先取得，nil 表示未启用
+	lockStalls        atomic.Int64    // 获取写锁超时的次数
+	migrator          ItemMigrator    // 迁移解码失败的数据项的函数，nil 表示不迁移
+	observer          AccessObserver  // 观察 Get 命中情况的函数，nil 表示不观察
+	expireHooks       expireHooks     // 单个数据项的过期回调，由 mux 保护
+	frozen            atomic.Bool     // 是否冻结过期，见 FreezeExpiration
+	policy            EvictionPolicy  // 容量淘汰策略
 }
 
+type hashFunc func() hash.Hash // 创建哈希的函数
+
 type KeyValue struct { //计算hash
 	key    string // hash key
 	cacher *Cache // Cache
@@ -61,29 +146,36 @@ var (
 	ErrKeyInvalid  = errors.New("key invalid.")
 	ErrFileInvalid = errors.New("file name invalid.")
 	ErrNewCache    = errors.New("new cache fatal.")
+	ErrKeyNotFound = errors.New("key not found.")
+	ErrValueTooBig = errors.New("value too big.")
+	ErrVersion     = errors.New("version mismatch.")
+)
+
+var ( // 以 fmt.Errorf("...: %w", ...) 包装返回，调用者使用 errors.Is 判断
+	ErrKeyExists    = errors.New("key exists.")    // Add 时数据项已存在
+	ErrCacheClosed  = errors.New("cache closed.")  // 缓存已 Close
+	ErrTypeMismatch = errors.New("type mismatch.") // 自增的值不是数值类型
+	ErrLockTimeout  = errors.New("lock timeout.")  // 在 WithLockTimeout 内未获取到写锁
 )
 
+var renameFile = os.Rename // 重命名文件，测试时替换以模拟失败
+
 /***************************************************************************************/
 
 /***************************************************************************************
  * 功能描述：创建一个缓存
- * 输入参数：是否会过期标志：defaultExpiration, 过期周期标志：gcInterval
+ * 输入参数：是否会过期标志：defaultExpiration, 过期周期标志：gcInterval, 可选配置：opts ...Option
  * 输出参数：无
  * 返 回 值：一个新的缓存
- * 其他说明：无
+ * 其他说明：gcInterval 为 0 且未启用自适应清理时，不启动 gcLoop；启用 WithLazyGC 时推迟到首次写入
  *
  * 修改日期      版本号      修改人      修改内容
  * ------------------------------------------------------------------------------------
  * 20180724      v1.0        xj      创建
  * ************************************************************************************/
-func NewCache(defaultExpiration, gcInterval time.Duration) (*Cache, error) {
+func NewCache(defaultExpiration, gcInterval time.Duration, opts ...Option) (*Cache, error) {
 	var err error
-	if defaultExpiration < -1 {
-		defaultExpiration, err = time.ParseDuration("0.5h")
-		if err != nil {
-			log.Fatal(err)
-		}
-	}
+	defaultExpiration = validDefaultExpiration(defaultExpiration)
 	if gcInterval < 0 {
 		gcInterval, err = time.ParseDuration("5s")
 		if err != nil {
@@ -95,11 +187,85 @@ func NewCache(defaultExpiration, gcInterval time.Duration) (*Cache, error) {
 		defaultExpiration: defaultExpiration,
 		gcInterval:        gcInterval,
 		items:             map[string]Item{},
+		codec:             GobCodec{},
+		logger:            nopLogger{},
+		gcReset:           make(chan bool, 1),
+		shardHasher:       func() hash.Hash { return crc32.NewIEEE() },
+		hasher:            sha256.New,
+		evictBatch:        1,
+	}
+	for _, opt := range opts {
+		opt(newCache)
+	}
+	if newCache.evictBatch > newCache.maxItems && newCache.maxItems > 0 {
+		newCache.evictBatch = newCache.maxItems
+	}
+	if newCache.maxItems > 0 && newCache.policy == LRU { // LRU 需要 Get 命中时更新 LastAccess
+		newCache.trackAccess.Store(true)
+	}
+	if newCache.initialCapacity > 0 {
+		newCache.items = make(map[string]Item, newCache.initialCapacity)
+	}
+	if newCache.cow != nil {
+		newCache.cow.dirty = true
+		newCache.publishItems()
+	}
+	if len(newCache.walPath) > 0 {
+		if newCache.wal, err = openWriteLog(newCache.walPath, newCache.codec); err != nil {
+			return nil, err
+		}
+	}
+	if !newCache.gcLazy {
+		newCache.startGc() // 启动缓存项过期回收清理 goroutine
+	}
+	if len(newCache.autoSaveFile) > 0 && newCache.autoSaveBase > 0 {
+		newCache.autoSaveStop = make(chan struct{})
+		go newCache.saveLoop(newCache.autoSaveStop)
 	}
-	go newCache.gcLoop() // 启动缓存项过期回收清理 goroutine
 	return newCache, nil
 }
 
+/***************************************************************************************
+ * 功能描述：校验默认过期时间
+ * 输入参数：默认过期时间：defaultExpiration time.Duration
+ * 输出参数：无
+ * 返 回 值：校验后的默认过期时间
+ * 其他说明：小于 NoExpiration 时使用 0.5h
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func validDefaultExpiration(defaultExpiration time.Duration) time.Duration {
+	if defaultExpiration < NoExpiration {
+		var err error
+		defaultExpiration, err = time.ParseDuration("0.5h")
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	return defaultExpiration
+}
+
+/***************************************************************************************
+ * 功能描述：运行时修改默认过期时间
+ * 输入参数：默认过期时间：defaultExpiration time.Duration
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，只影响之后以 DefaultExpiration 写入的数据项，
+ *           已存储数据项的过期时间不变；校验规则与 NewCache 相同
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) SetDefaultExpiration(defaultExpiration time.Duration) {
+	defaultExpiration = validDefaultExpiration(defaultExpiration)
+	thisCache.lock()
+	defer thisCache.unlock()
+	thisCache.defaultExpiration = defaultExpiration
+}
+
 /***************************************************************************************
  * 功能描述：判断数据项是否已经过期
  * 输入参数：无
@@ -115,7 +281,29 @@ func (thisItem Item) Expired() bool {
 	if thisItem.Expiration == 0 {
 		return false
 	}
-	return time.Now().UnixNano() > thisItem.Expiration // 使用Unix时间戳，单位纳秒，若当前时间大于过期时间，则判断为过期
+	return nanotime() > thisItem.Expiration // 使用Unix时间戳，单位纳秒，若当前时间大于过期时间，则判断为过期
+}
+
+/***************************************************************************************
+ * 功能描述：判断数据项在该缓存中是否已经过期，包括闲置过期
+ * 输入参数：数据项：item Item, 当前时间：now int64
+ * 输出参数：无
+ * 返 回 值：过期为true
+ * 其他说明：该函数为 Cache 类方法，超过绝对过期时间，或启用 idleTTL 时闲置超过 idleTTL，均判断为过期；
+ *           FreezeExpiration 冻结期间总为 false
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) expired(item Item, now int64) bool {
+	if thisCache.frozen.Load() {
+		return false
+	}
+	if item.Expiration > 0 && now > item.Expiration {
+		return true
+	}
+	return thisCache.idleTTL > 0 && item.LastAccess > 0 && now-item.LastAccess > int64(thisCache.idleTTL)
 }
 
 /***************************************************************************************
@@ -123,54 +311,97 @@ func (thisItem Item) Expired() bool {
  * 输入参数：key string 用户输入的key
  * 输出参数：hashKey hash计算后的key
  * 返 回 值：hashKey 和 err
- * 其他说明：该函数为 Cache 类方法
+ * 其他说明：该函数为 Cache 类方法，默认使用 CRC32(IEEE)，可通过 WithShardHasher 替换，
+ *           取哈希值的前 4 个字节(大端)计算分片
  *
  * 修改日期      版本号      修改人      修改内容
  * ------------------------------------------------------------------------------------
  * 20180728      v1.0        xj          创建
  * ************************************************************************************/
 func (thisCache *Cache) SetKey(key string) (hashKey string, err error) {
-	thisCache.mux.Lock()
-	thisCache.mux.Unlock()
+	thisCache.lock()
+	thisCache.unlock()
 
-	if len(key) == 0 {
-		err = ErrKeyInvalid
+	if key, err = thisCache.checkKey(key); err != nil {
 		return "ErrKey", err
 	}
-	crcTable := crc32.MakeTable(crc32.IEEE)
-	hashval := crc32.Checksum([]byte(key), crcTable)
+	hasher := thisCache.shardHasher()
+	hasher.Write([]byte(key))
+	hashval := binary.BigEndian.Uint32(hasher.Sum(nil))
 	index := int(hashval) % 10 // 非分布式，随意取一个值:10
-	hashKey = string(index)
+	hashKey = string(rune(index))
 	return hashKey, nil
 }
 
 /***************************************************************************************
  * 功能描述：过期缓存数据项回收清理
- * 输入参数：无
+ * 输入参数：停止信号管道：stopGc chan bool, 创建定时器的函数：newTimer func(time.Duration) gcTimer
  * 输出参数：无
  * 返 回 值：无
- * 其他说明：该函数为 Cache 类方法
+ * 其他说明：该函数为 Cache 类方法，使用可重置的 timer，每次清理后由 nextGcInterval 决定下一次的周期；
+ *           首次清理在 (0, interval] 内随机延迟，见 firstGcDelay
  *
  * 修改日期      版本号      修改人      修改内容
  * ------------------------------------------------------------------------------------
  * 20180724      v1.0        xj      创建
  * ************************************************************************************/
-func (thisCache *Cache) gcLoop() {
-	ticker := time.NewTicker(thisCache.gcInterval) // 创建一个ticker时钟，通过指定的参数:gcInterval时间间隔
-	// 周期性的从ticker.C管道中发送数据过来。
+func (thisCache *Cache) gcLoop(stopGc chan bool, newTimer func(time.Duration) gcTimer) {
+	interval, _ := thisCache.nextGcInterval(0, 0, 0)
+	timer := newTimer(thisCache.firstGcDelay(interval)) // 创建一个timer时钟，到期后执行清理并重置
 	for {
 		select {
-		case <-ticker.C:
-			thisCache.DeleteExpired() // 周期性的执行删除过期缓存数据项
-		case <-thisCache.stopGc: // 为保证gcLoop能正常结束，监听stopGc管道
-			ticker.Stop()
+		case <-timer.C():
+			scanned, reaped, _ := thisCache.deleteExpired() // 周期性的执行删除过期缓存数据项，超时时跳过本轮
+			thisCache.maybeCompact()
+			thisCache.maybeCompactLog()
+			thisCache.refreshExpiring()
+			var paused bool
+			interval, paused = thisCache.nextGcInterval(interval, scanned, reaped)
+			if !paused {
+				timer.Reset(interval)
+			}
+		case <-thisCache.gcReset: // 清理周期被修改，或暂停/恢复清理
+			if !timer.Stop() {
+				select {
+				case <-timer.C():
+				default:
+				}
+			}
+			var paused bool
+			interval, paused = thisCache.nextGcInterval(0, 0, 0)
+			if !paused {
+				timer.Reset(interval)
+			}
+		case <-stopGc: // 为保证gcLoop能正常结束，监听stopGc管道
+			timer.Stop()
 			return
 		}
 	}
 }
 
 /***************************************************************************************
- * 功能描述：查看Cache状态
+ * 功能描述：计算 gcLoop 首次清理的延迟
+ * 输入参数：清理周期：interval time.Duration
+ * 输出参数：无
+ * 返 回 值：首次清理的延迟
+ * 其他说明：该函数为 Cache 类方法。同一进程中以相同清理周期创建的多个缓存若同时开始计时，
+ *           之后每次清理都会在同一时刻发生，造成周期性的 CPU 峰值；因此首次清理在 (0, interval]
+ *           内随机延迟，使各缓存的清理时刻错开，且首次清理不晚于未加延迟时。
+ *           WithoutGCJitter 关闭随机延迟，首次清理固定在 interval 之后
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) firstGcDelay(interval time.Duration) time.Duration {
+	if thisCache.gcNoJitter || interval <= 0 {
+		return interval
+	}
+	return time.Duration(rand.Int63n(int64(interval))) + 1
+}
+
+/***************************************************************************************
+ * 功能描述：启动 gcLoop，已在运行、已暂停或未设置清理周期时不启动
  * 输入参数：无
  * 输出参数：无
  * 返 回 值：无
@@ -178,332 +409,1664 @@ func (thisCache *Cache) gcLoop() {
  *
  * 修改日期      版本号      修改人      修改内容
  * ------------------------------------------------------------------------------------
- * 20180727      v1.0        xj      创建
+ * 20261016      v1.0        xj      创建
  * ************************************************************************************/
-func (thisCache *Cache) GetCacheStat() {
-	thisCache.mux.RLock()
-	defer thisCache.mux.RUnlock()
-	fmt.Printf("defaultExpiration: %v\tgcInterval: %v\tItem: %v\n", thisCache.defaultExpiration, thisCache.gcInterval, thisCache.items)
+func (thisCache *Cache) startGc() {
+	thisCache.gcMux.Lock()
+	defer thisCache.gcMux.Unlock()
+	if thisCache.gcRunning || thisCache.gcPaused {
+		return
+	}
+	if thisCache.gcInterval > 0 || thisCache.gcMinInterval > 0 {
+		thisCache.gcRunning = true
+		thisCache.gcSince = time.Now()
+		thisCache.stopGc = make(chan bool)
+		go thisCache.gcLoop(thisCache.stopGc, newGcTimer) // 在当前 goroutine 读取 newGcTimer，测试替换时不与 gcLoop 竞争
+	}
 }
 
 /***************************************************************************************
- * 功能描述：通过键值删除缓存数据项
- * 输入参数：键值 key string
+ * 功能描述：通知 gcLoop 重新读取清理周期
+ * 输入参数：无
  * 输出参数：无
  * 返 回 值：无
- * 其他说明：该函数为 Cache 类方法
+ * 其他说明：该函数为 Cache 类方法，gcReset 带一个缓冲，已有未处理的通知时不再重复发送
  *
  * 修改日期      版本号      修改人      修改内容
  * ------------------------------------------------------------------------------------
- * 20180724      v1.0        xj      创建
+ * 20261016      v1.0        xj      创建
  * ************************************************************************************/
-func (thisCache *Cache) delete(key string) error {
-	if len(key) == 0 {
-		err := ErrKeyInvalid
-		return err
+func (thisCache *Cache) resetGc() {
+	select {
+	case thisCache.gcReset <- true:
+	default:
 	}
-	delete(thisCache.items, key)
-	return nil
 }
 
 /***************************************************************************************
- * 功能描述：通过键名删除一个数据项，可导出
- * 输入参数：数据项键名：key string
+ * 功能描述：运行时修改过期数据项清理周期
+ * 输入参数：清理周期：dur time.Duration
  * 输出参数：无
- * 返 回 值：无
- * 其他说明：该函数为 Cache 类方法
+ * 返 回 值：无 error， 则为 nil
+ * 其他说明：该函数为 Cache 类方法，dur 必须大于 0；新周期从调用时刻开始计时，
+ *           gcLoop 未运行时(gcInterval 为 0 或已 StopGc)会启动 gcLoop；启用自适应时作为新的初始周期
  *
  * 修改日期      版本号      修改人      修改内容
  * ------------------------------------------------------------------------------------
- * 20180724      v1.0        xj      创建
+ * 20261016      v1.0        xj      创建
  * ************************************************************************************/
-func (thisCache *Cache) Delete(key string) {
-	thisCache.mux.Lock()
-	thisCache.delete(key)
-	thisCache.mux.Unlock()
+func (thisCache *Cache) SetGCInterval(dur time.Duration) error {
+	if dur <= 0 {
+		return fmt.Errorf("gc interval %v must be positive.", dur)
+	}
+	thisCache.gcMux.Lock()
+	thisCache.gcInterval = dur
+	thisCache.gcSince = time.Now()
+	thisCache.gcMux.Unlock()
+
+	thisCache.resetGc()
+	thisCache.startGc()
+	return nil
 }
 
 /***************************************************************************************
- * 功能描述：删除过期的缓存数据项
+ * 功能描述：暂停过期数据项清理
  * 输入参数：无
  * 输出参数：无
  * 返 回 值：无
- * 其他说明：该函数为 Cache 类方法
+ * 其他说明：该函数为 Cache 类方法，暂停期间 gcLoop 不再执行清理，但过期数据项对 Get 仍不可见
  *
  * 修改日期      版本号      修改人      修改内容
  * ------------------------------------------------------------------------------------
- * 20180724      v1.0        xj      创建
+ * 20261016      v1.0        xj      创建
  * ************************************************************************************/
-func (thisCache *Cache) DeleteExpired() {
-	now := time.Now().UnixNano()
-	thisCache.mux.Lock()
-	defer thisCache.mux.Unlock()
-
-	for key, val := range thisCache.items { // 遍历所有数据项，删除过期数据项
-		if val.Expiration > 0 && now > val.Expiration {
-			thisCache.delete(key)
-		}
-	}
+func (thisCache *Cache) PauseGC() {
+	thisCache.gcMux.Lock()
+	thisCache.gcPaused = true
+	thisCache.gcMux.Unlock()
+	thisCache.resetGc()
 }
 
 /***************************************************************************************
- * 功能描述：设置缓存数据项，若数据项存在则覆盖，无锁操作
- * 输入参数：数据项键名：key string, 数据项键值：value interface{}, 数据项生命周期：dur time.Duration
+ * 功能描述：恢复过期数据项清理
+ * 输入参数：无
  * 输出参数：无
  * 返 回 值：无
- * 其他说明：该函数为 Cache 类方法
+ * 其他说明：该函数为 Cache 类方法，恢复后从调用时刻开始重新计时
  *
  * 修改日期      版本号      修改人      修改内容
  * ------------------------------------------------------------------------------------
- * 20180724      v1.0        xj      创建
+ * 20261016      v1.0        xj      创建
  * ************************************************************************************/
-func (thisCache *Cache) set(key string, value interface{}, dur time.Duration) error {
-	if len(key) == 0 {
-		err := ErrKeyInvalid
-		return err
-	}
-	var expir int64
-	if dur == DefaultExpiration {
-		dur = thisCache.defaultExpiration
-	}
-	if dur > 0 {
-		expir = time.Now().Add(dur).UnixNano()
-	}
-
-	thisCache.items[key] = Item{
-		Object:     value,
-		Expiration: expir,
-	}
-	return nil
+func (thisCache *Cache) ResumeGC() {
+	thisCache.gcMux.Lock()
+	thisCache.gcPaused = false
+	thisCache.gcSince = time.Now()
+	thisCache.gcMux.Unlock()
+	thisCache.resetGc()
+	thisCache.startGc()
 }
 
 /***************************************************************************************
- * 功能描述：设置缓存数据项，若数据项存在则覆盖,导出函数
- * 输入参数：数据项键名：key string, 数据项键值：value interface{}, 数据项生命周期：dur time.Duration
+ * 功能描述：冻结或恢复数据项的过期
+ * 输入参数：是否冻结：frozen bool
  * 输出参数：无
  * 返 回 值：无
- * 其他说明：该函数为 Cache 类方法
+ * 其他说明：该函数为 Cache 类方法，用于调试时防止数据项在排查过程中消失；冻结期间所有数据项
+ *           (包括闲置过期)都不会被判断为过期，Get 仍可读取，gcLoop、Compact 也不会删除它们；
+ *           数据项保存的过期时间不变，恢复后冻结期间已到期的数据项立即视为过期。
+ *           冻结期间 Expirations 对已到期的数据项返回负的剩余生命周期；不影响 Item.Expired
  *
  * 修改日期      版本号      修改人      修改内容
  * ------------------------------------------------------------------------------------
- * 20180724      v1.0        xj      创建
+ * 20261016      v1.0        xj      创建
  * ************************************************************************************/
-func (thisCache *Cache) Set(key string, value interface{}, dur time.Duration) error {
-	if len(key) == 0 {
-		err := ErrKeyInvalid
-		return err
-	}
-	var expir int64
-	if dur == DefaultExpiration {
-		dur = thisCache.defaultExpiration
-	}
-	if dur > 0 {
-		expir = time.Now().Add(dur).UnixNano()
-	}
-	thisCache.mux.Lock()
-	defer thisCache.mux.Unlock()
-
-	thisCache.items[key] = Item{
-		Object:     value,
-		Expiration: expir,
-	}
-	return nil
+func (thisCache *Cache) FreezeExpiration(frozen bool) {
+	thisCache.frozen.Store(frozen)
 }
 
 /***************************************************************************************
- * 功能描述：获取数据项，若找到数据项，还需要判断数据项是否已经过期，无锁
- * 输入参数：数据项键名：key string
+ * 功能描述：计算下一次清理的周期
+ * 输入参数：本次周期：interval time.Duration, 本次扫描数量：scanned int, 本次清理数量：reaped int
  * 输出参数：无
- * 返 回 值：具体数据项的值以及是否找到(bool)
- * 其他说明：该函数为 Cache 类方法
+ * 返 回 值：下一次清理的周期，以及是否已暂停清理
+ * 其他说明：该函数为 Cache 类方法。未启用自适应时使用 gcInterval；启用自适应时，interval 为 0(首次清理
+ *           或周期被重置)则从 gcInterval 开始，否则过期比例超过 1/4 则周期减半，低于 1/20 则周期加倍，
+ *           结果限制在 [min, max] 内
  *
  * 修改日期      版本号      修改人      修改内容
  * ------------------------------------------------------------------------------------
- * 20180724      v1.0        xj      创建
+ * 20261016      v1.0        xj      创建
  * ************************************************************************************/
-func (thisCache *Cache) get(key string) (interface{}, bool, error) {
-	if len(key) == 0 {
-		err := ErrKeyInvalid
-		return nil, false, err
+func (thisCache *Cache) nextGcInterval(interval time.Duration, scanned, reaped int) (time.Duration, bool) {
+	thisCache.gcMux.Lock()
+	defer thisCache.gcMux.Unlock()
+	if thisCache.gcMinInterval <= 0 {
+		return thisCache.gcInterval, thisCache.gcPaused
 	}
-	item, found := thisCache.items[key]
-	if !found {
-		return nil, false, nil
+	switch {
+	case interval == 0: // 首次清理或周期被重置，尚无清理结果，从 gcInterval 开始
+		interval = thisCache.gcInterval
+	case scanned > 0 && reaped*4 > scanned:
+		interval /= 2
+	case scanned == 0 || reaped*20 < scanned:
+		interval *= 2
 	}
-	if item.Expired() {
-		return nil, false, nil
+	if interval < thisCache.gcMinInterval {
+		interval = thisCache.gcMinInterval
 	}
-	return item.Object, found, nil
+	if interval > thisCache.gcMaxInterval {
+		interval = thisCache.gcMaxInterval
+	}
+	return interval, thisCache.gcPaused
 }
 
 /***************************************************************************************
- * 功能描述：获取数据项，若找到数据项，还需要判断数据项是否已经过期
- * 输入参数：数据项键名：key string
+ * 功能描述：查看Cache状态
+ * 输入参数：无
  * 输出参数：无
- * 返 回 值：具体数据项的值以及是否找到(bool)
+ * 返 回 值：无
  * 其他说明：该函数为 Cache 类方法
  *
  * 修改日期      版本号      修改人      修改内容
  * ------------------------------------------------------------------------------------
- * 20180724      v1.0        xj      创建
+ * 20180727      v1.0        xj      创建
  * ************************************************************************************/
-func (thisCache *Cache) Get(key string) (interface{}, bool, error) {
+func (thisCache *Cache) GetCacheStat() {
 	thisCache.mux.RLock()
 	defer thisCache.mux.RUnlock()
+	fmt.Printf("defaultExpiration: %v\tgcInterval: %v\tItem: %v\n", thisCache.defaultExpiration, thisCache.gcInterval, thisCache.items)
+}
 
+/***************************************************************************************
+ * 功能描述：通过键值删除缓存数据项
+ * 输入参数：键值 key string
+ * 输出参数：无
+ * 返 回 值：被删除数据项的值，是否需要调用 onEvicted(bool)，以及 error
+ * 其他说明：该函数为 Cache 类方法，删除墓碑数据项或不存在的数据项时不需要调用 onEvicted
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20180724      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) delete(key string) (interface{}, bool, error) {
 	if len(key) == 0 {
 		err := ErrKeyInvalid
 		return nil, false, err
 	}
-
 	item, found := thisCache.items[key]
 	if !found {
 		return nil, false, nil
 	}
-	if item.Expired() {
+	delete(thisCache.items, key)
+	thisCache.markDirty()
+	thisCache.takeExpireHook(key)
+	thisCache.count.Add(-1)
+	thisCache.unindexItem(key)
+	thisCache.removeQuota(key)
+	thisCache.logDelete(key)
+	if item.negative() {
 		return nil, false, nil
 	}
+	thisCache.notify(EventDeleted, key, item.Object)
 	return item.Object, true, nil
 }
 
 /***************************************************************************************
- * 功能描述：添加数据项，若已存在，返回错误
- * 输入参数：数据项键名：key string, 数据项键值：value interface{}, 数据项生命周期：dur time.Duration
+ * 功能描述：通过键名删除一个数据项，可导出
+ * 输入参数：数据项键名：key string
  * 输出参数：无
- * 返 回 值：无 error， 则为 nil
- * 其他说明：该函数为 Cache 类方法
+ * 返 回 值：无 error， 则为 nil；启用 WithLockTimeout 且获取写锁超时时返回 ErrLockTimeout
+ * 其他说明：该函数为 Cache 类方法，键名无效时不做修改并返回 ErrKeyInvalid
  *
  * 修改日期      版本号      修改人      修改内容
  * ------------------------------------------------------------------------------------
  * 20180724      v1.0        xj      创建
  * ************************************************************************************/
-func (thisCache *Cache) Add(key string, val interface{}, dur time.Duration) error {
-	thisCache.mux.Lock()
-	if len(key) == 0 {
-		err := ErrKeyInvalid
+func (thisCache *Cache) Delete(key string) error {
+	key, err := thisCache.checkKey(key)
+	if err != nil {
 		return err
 	}
-	_, found, _ := thisCache.get(key)
-	if found {
-		thisCache.mux.Unlock()
-		return fmt.Errorf("Item %s already exists", key)
+	if err = thisCache.lockWithTimeout(); err != nil {
+		return err
+	}
+	value, evicted, _ := thisCache.delete(key)
+	onEvicted := thisCache.onEvicted
+	thisCache.unlock()
+	if evicted && onEvicted != nil {
+		onEvicted(key, value, Deleted)
 	}
-	thisCache.set(key, val, dur)
-	thisCache.mux.Unlock()
 	return nil
 }
 
 /***************************************************************************************
- * 功能描述：替换一个存在的数据项
- * 输入参数：数据项键名：key string, 数据项键值：value interface{}, 数据项生命周期：dur time.Duration
+ * 功能描述：删除所有键名以 prefix 开头的数据项
+ * 输入参数：键名前缀：prefix string
  * 输出参数：无
- * 返 回 值：无 error， 则为 nil
- * 其他说明：该函数为 Cache 类方法
+ * 返 回 值：int 被删除的数据项数量(不包括墓碑数据项)
+ * 其他说明：该函数为 Cache 类方法，在一次写锁内删除，同时维护二级索引与命名空间配额；
+ *           释放锁后对未过期的数据项调用 onEvicted(Deleted)，对已过期但尚未清理的调用 onEvicted(Expired)；
+ *           需要遍历全部数据项，prefix 为空时删除全部数据项；prefix 按 WithKeyValidator 规范化
  *
  * 修改日期      版本号      修改人      修改内容
  * ------------------------------------------------------------------------------------
- * 20180725      v1.0        xj      创建
+ * 20261016      v1.0        xj      创建
  * ************************************************************************************/
-func (thisCache *Cache) Replace(key string, val interface{}, dur time.Duration) error {
-	thisCache.mux.Lock()
-	if len(key) == 0 {
-		err := ErrKeyInvalid
-		return err
+func (thisCache *Cache) DeletePrefix(prefix string) int {
+	prefix = thisCache.normalizePrefix(prefix)
+	var deleted, expired []keyAndValue
+	if thisCache.lockWithTimeout() != nil {
+		return 0
 	}
-	_, found, _ := thisCache.get(key)
-	if !found {
-		thisCache.mux.Unlock()
-		return fmt.Errorf("item %v doesn't exist.", key)
+	now := nanotime()
+	for key, val := range thisCache.items {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		value, evicted, _ := thisCache.delete(key)
+		if !evicted {
+			continue
+		}
+		if thisCache.expired(val, now) {
+			expired = append(expired, keyAndValue{key, value})
+		} else {
+			deleted = append(deleted, keyAndValue{key, value})
+		}
 	}
-	thisCache.set(key, val, dur)
-	thisCache.mux.Unlock()
-	return nil
+	onEvicted := thisCache.onEvicted
+	thisCache.unlock()
+
+	fireEvicted(onEvicted, deleted, Deleted)
+	fireEvicted(onEvicted, expired, Expired)
+	return len(deleted) + len(expired)
 }
 
 /***************************************************************************************
- * 功能描述：将缓存数据项写入到io.Writer中
- * 输入参数：wrt io.Writer
+ * 功能描述：删除过期的缓存数据项
+ * 输入参数：无
  * 输出参数：无
- * 返 回 值：无 error， 则为 nil
+ * 返 回 值：无 error， 则为 nil；启用 WithLockTimeout 且获取写锁超时时返回 ErrLockTimeout
  * 其他说明：该函数为 Cache 类方法
  *
  * 修改日期      版本号      修改人      修改内容
  * ------------------------------------------------------------------------------------
- * 20180725      v1.0        xj      创建
+ * 20180724      v1.0        xj      创建
  * ************************************************************************************/
-func (thisCache *Cache) Save(wrt io.Writer) (err error) {
-	encode := gob.NewEncoder(wrt) // 序列化操作，进行编码操作
-	defer func() {
-		if e := recover(); e != nil {
-			err = fmt.Errorf("Error registring item type with Gob lib.")
-			return
-		}
-	}()
-
-	thisCache.mux.RLock()
-	defer thisCache.mux.RUnlock()
-
-	for _, val := range thisCache.items {
-		gob.Register(val.Object) // 因为item的值为interface{}，所以需要注册
-	}
-	err = encode.Encode(&thisCache.items)
-	return
+func (thisCache *Cache) DeleteExpired() error {
+	_, _, err := thisCache.deleteExpired()
+	return err
 }
 
 /***************************************************************************************
- * 功能描述：将缓存数据项从内存中保存到文件中
- * 输入参数：file string 要打开的文件名
+ * 功能描述：删除过期的缓存数据项，并统计扫描与删除的数量
+ * 输入参数：无
  * 输出参数：无
- * 返 回 值：无 error， 则为 nil
- * 其他说明：该函数为 Cache 类方法
+ * 返 回 值：扫描的数据项数量，删除的数据项数量，获取写锁超时时返回 ErrLockTimeout
+ * 其他说明：该函数为 Cache 类方法，同时记录 GCStats
  *
  * 修改日期      版本号      修改人      修改内容
  * ------------------------------------------------------------------------------------
- * 20180725      v1.0        xj      创建
+ * 20261016      v1.0        xj      创建
  * ************************************************************************************/
-func (thisCache *Cache) SaveMemToFile(file string) error {
-	if len(file) == 0 {
-		err := ErrFileInvalid
-		return err
-	}
-	fp, err := os.Create(file)
-	if err != nil {
-		return err
+func (thisCache *Cache) deleteExpired() (scanned, reaped int, err error) {
+	var evictedItems []keyAndValue
+	var hooks []expiredHook
+	if err = thisCache.lockWithTimeout(); err != nil {
+		return 0, 0, err
 	}
-	if err = thisCache.Save(fp); err != nil {
-		fp.Close()
-		return err
+	start := time.Now()
+	now := nanotime()
+	onEvicted := thisCache.onEvicted
+	for key, val := range thisCache.items { // 遍历所有数据项，删除过期数据项
+		scanned++
+		if thisCache.expired(val, now) {
+			if hook := thisCache.takeExpireHook(key); hook != nil && !val.negative() {
+				hooks = append(hooks, expiredHook{key, val.Object, hook})
+			}
+			value, evicted, _ := thisCache.delete(key)
+			if evicted && onEvicted != nil {
+				evictedItems = append(evictedItems, keyAndValue{key, value})
+			}
+			reaped++
+		}
 	}
-	return fp.Close()
+	elapsed := time.Since(start)
+	thisCache.unlock()
+
+	thisCache.statMux.Lock()
+	thisCache.gcStats.record(start, elapsed, scanned, reaped)
+	thisCache.statMux.Unlock()
+	thisCache.logger.Debugf("cache gc: scanned %d items, reaped %d expired items", scanned, reaped)
+	fireEvicted(onEvicted, evictedItems, Expired)
+	thisCache.fireExpireHooks(hooks)
+	thisCache.statMux.Lock()
+	thisCache.lastGcRun = time.Now()
+	thisCache.statMux.Unlock()
+	return scanned, reaped, nil
 }
 
 /***************************************************************************************
- * 功能描述：从io.Reader中读取数据项
- * 输入参数：rd io.Reader
+ * 功能描述：列出已经过期但尚未被清理的数据项键名，不删除数据项
+ * 输入参数：无
  * 输出参数：无
- * 返 回 值：无 error， 则为 nil
- * 其他说明：该函数为 Cache 类方法
+ * 返 回 值：[]string 过期数据项的键名
+ * 其他说明：该函数为 Cache 类方法，结果为调用时刻的快照，可用于观察两次 gcLoop 之间的清理滞后
  *
  * 修改日期      版本号      修改人      修改内容
  * ------------------------------------------------------------------------------------
- * 20180725      v1.0        xj      创建
+ * 20261016      v1.0        xj      创建
  * ************************************************************************************/
-func (thisCache *Cache) Load(rd io.Reader) error {
-	decode := gob.NewDecoder(rd) // 解码，反序列化
-	items := map[string]Item{}
-	err := decode.Decode(&items)
-	if err != nil {
-		return err
-	}
-	thisCache.mux.Lock()
-	defer thisCache.mux.Unlock()
-
+func (thisCache *Cache) ExpiredKeys() []string {
+	now := nanotime()
+	thisCache.mux.RLock()
+	defer thisCache.mux.RUnlock()
+
+	keys := []string{}
+	for key, val := range thisCache.items {
+		if thisCache.expired(val, now) && !val.negative() {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+/***************************************************************************************
+ * 功能描述：列出将在 d 之内过期的数据项键名
+ * 输入参数：时间窗口：d time.Duration
+ * 输出参数：无
+ * 返 回 值：[]string 过期时间在 [now, now+d] 之间的数据项键名，顺序不确定
+ * 其他说明：该函数为 Cache 类方法，只需读锁；不包含永不过期、已过期以及墓碑数据项，
+ *           只按绝对过期时间判断，不考虑闲置过期；可用于在过期之前主动刷新热点数据项
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) ExpiringWithin(d time.Duration) []string {
+	now := nanotime()
+	deadline := now + int64(d)
+	thisCache.mux.RLock()
+	defer thisCache.mux.RUnlock()
+
+	keys := []string{}
+	for key, val := range thisCache.items {
+		if val.Expiration > 0 && val.Expiration <= deadline && !thisCache.expired(val, now) && !val.negative() {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+/***************************************************************************************
+ * 功能描述：获取所有未过期数据项的剩余生命周期
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：map[string]time.Duration 键名到剩余生命周期，永不过期的数据项为 NoExpiration(-1)
+ * 其他说明：该函数为 Cache 类方法，在一次读锁内计算，结果是同一时刻的一致快照，返回的 map 为副本；
+ *           不包含已过期与墓碑数据项，只按绝对过期时间计算，不考虑闲置过期
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) Expirations() map[string]time.Duration {
+	now := nanotime()
+	thisCache.mux.RLock()
+	defer thisCache.mux.RUnlock()
+
+	ttls := make(map[string]time.Duration, len(thisCache.items))
+	for key, val := range thisCache.items {
+		if thisCache.expired(val, now) || val.negative() {
+			continue
+		}
+		if val.Expiration > 0 {
+			ttls[key] = time.Duration(val.Expiration - now)
+		} else {
+			ttls[key] = NoExpiration
+		}
+	}
+	return ttls
+}
+
+/***************************************************************************************
+ * 功能描述：随机抽取最多 n 个未过期的数据项键名
+ * 输入参数：数量：n int
+ * 输出参数：无
+ * 返 回 值：[]string 抽取的键名，无序
+ * 其他说明：该函数为 Cache 类方法，只需读锁，利用 map 遍历起点随机的特性，取到 n 个即停止，
+ *           不需要复制全部键名；抽样是近似的，同一次返回的键名在 map 内往往相邻，不保证均匀分布
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) SampleKeys(n int) []string {
+	if n <= 0 {
+		return []string{}
+	}
+	now := nanotime()
+	thisCache.mux.RLock()
+	defer thisCache.mux.RUnlock()
+
+	keys := make([]string, 0, n)
+	for key, val := range thisCache.items {
+		if len(keys) >= n {
+			break
+		}
+		if !thisCache.expired(val, now) && !val.negative() {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+/***************************************************************************************
+ * 功能描述：随机获取一个未过期的数据项
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：数据项键名、数据项的值，以及是否找到(bool)，缓存中没有未过期的数据项时为 false
+ * 其他说明：该函数为 Cache 类方法，只需读锁，取 map 遍历的第一个未过期数据项；
+ *           分布取决于运行时 map 遍历起点的随机化，不保证均匀，相邻的数据项被选中的概率可能不同；
+ *           不记录访问时间，值的处理与 Get 相同(WithCopyOnGet 复制、WithSerializedValues 解码)
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) GetRandom() (key string, value interface{}, ok bool) {
+	now := nanotime()
+	thisCache.mux.RLock()
+	for k, val := range thisCache.items {
+		if !thisCache.expired(val, now) && !val.negative() {
+			key, value, ok = k, val.Object, true
+			break
+		}
+	}
+	thisCache.mux.RUnlock()
+
+	if !ok {
+		return "", nil, false
+	}
+	value, err := thisCache.readValue(value)
+	if err != nil {
+		return "", nil, false
+	}
+	return key, value, true
+}
+
+/***************************************************************************************
+ * 功能描述：设置缓存数据项，若数据项存在则覆盖，无锁操作
+ * 输入参数：数据项键名：key string, 数据项键值：value interface{}, 数据项生命周期：dur time.Duration
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20180724      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) set(key string, value interface{}, dur time.Duration) error {
+	if len(key) == 0 {
+		err := ErrKeyInvalid
+		return err
+	}
+	value, err := thisCache.encodeValue(value)
+	if err != nil {
+		thisCache.logger.Errorf("cache encode %s: %v", key, err)
+		return err
+	}
+	thisCache.takeExpireHook(key) // 覆盖写入时丢弃原数据项的过期回调
+	now := nanotime()
+	thisCache.putItem(key, Item{
+		Object:     value,
+		Expiration: thisCache.expiration(dur),
+		LastAccess: now,
+		Version:    thisCache.nextVersion(),
+		Created:    now,
+	})
+	return nil
+}
+
+/***************************************************************************************
+ * 功能描述：写入数据项并维护数据项数量，调用时须持有写锁
+ * 输入参数：数据项键名：key string, 数据项：item Item
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，覆盖已有数据项时数量不变；同时更新二级索引与增量持久化日志；
+ *           启用 WithLazyGC 时首次写入启动 gcLoop；超过 WithMaxItems 的上限时按批移出最久未访问的数据项
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) putItem(key string, item Item) {
+	if thisCache.gcLazy {
+		thisCache.gcOnce.Do(thisCache.startGc)
+	}
+	if item.Created == 0 {
+		item.Created = nanotime()
+	}
+	_, found := thisCache.items[key]
+	if !found {
+		if count := thisCache.count.Add(1); count > thisCache.peak {
+			thisCache.peak = count
+		}
+	}
+	thisCache.items[key] = item
+	thisCache.markDirty()
+	thisCache.indexItem(key, item)
+	thisCache.logSet(key, item)
+	if !item.negative() {
+		thisCache.notify(EventSet, key, item.Object)
+	}
+	if !found && len(thisCache.quotas) > 0 {
+		thisCache.addQuota(key)
+	}
+	if thisCache.maxItems > 0 && len(thisCache.items) > thisCache.maxItems {
+		thisCache.evictOverflow(key)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：分配一个新的版本号，调用时须持有写锁
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：新的版本号
+ * 其他说明：该函数为 Cache 类方法，版本号在整个缓存内递增，删除后重新写入的数据项也不会复用旧版本号
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) nextVersion() uint64 {
+	thisCache.version++
+	return thisCache.version
+}
+
+/***************************************************************************************
+ * 功能描述：根据数据项生命周期计算过期时间戳
+ * 输入参数：数据项生命周期：dur time.Duration
+ * 输出参数：无
+ * 返 回 值：过期时间(Unix时间戳，单位纳秒)，0 表示永不过期
+ * 其他说明：该函数为 Cache 类方法，DefaultExpiration 使用缓存的默认过期时间，调用时须持有锁；
+ *           以 nanotime() 为起点，与过期判断使用同一时钟，见 clock.go
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) expiration(dur time.Duration) int64 {
+	if dur == DefaultExpiration {
+		dur = thisCache.defaultExpiration
+	}
+	if dur > 0 {
+		return nanotime() + int64(dur)
+	}
+	return 0
+}
+
+/***************************************************************************************
+ * 功能描述：检查数据项的值是否超过 maxValueBytes
+ * 输入参数：数据项键值：value interface{}
+ * 输出参数：无
+ * 返 回 值：超过时返回 ErrValueTooBig
+ * 其他说明：该函数为 Cache 类方法，[]byte 与 string 按长度计算；其它类型默认不限制，
+ *           启用 gobSizeValues 时按 gob 编码后的长度计算，无法编码的值同样返回错误；
+ *           在加锁之前调用，避免编码时阻塞其它读写
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) checkValueSize(value interface{}) error {
+	if thisCache.maxValueBytes <= 0 {
+		return nil
+	}
+	switch value.(type) {
+	case []byte, string:
+	default:
+		if !thisCache.gobSizeValues {
+			return nil
+		}
+	}
+	size, err := valueSize(value)
+	if err != nil {
+		return err
+	}
+	if size > thisCache.maxValueBytes {
+		return ErrValueTooBig
+	}
+	return nil
+}
+
+/***************************************************************************************
+ * 功能描述：计算数据项的值的字节数
+ * 输入参数：数据项键值：value interface{}
+ * 输出参数：无
+ * 返 回 值：字节数，无法编码时返回 error
+ * 其他说明：[]byte 与 string 按长度计算，其它类型按 gob 编码后的长度计算
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func valueSize(value interface{}) (size int, err error) {
+	switch v := value.(type) {
+	case []byte:
+		return len(v), nil
+	case string:
+		return len(v), nil
+	}
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("Error registring item type with Gob lib.")
+		}
+	}()
+	var buf bytes.Buffer
+	if err = (GobCodec{}).NewEncoder(&buf).Encode(&Item{Object: value}); err != nil {
+		return 0, err
+	}
+	return buf.Len(), nil
+}
+
+/***************************************************************************************
+ * 功能描述：设置缓存数据项，若数据项存在则覆盖,导出函数
+ * 输入参数：数据项键名：key string, 数据项键值：value interface{}, 数据项生命周期：dur time.Duration
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20180724      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) Set(key string, value interface{}, dur time.Duration) error {
+	if thisCache.closed.Load() {
+		return ErrCacheClosed
+	}
+	key, err := thisCache.checkKey(key)
+	if err != nil {
+		return err
+	}
+	if err := thisCache.checkValueSize(value); err != nil {
+		return err
+	}
+	if err := thisCache.lockWithTimeout(); err != nil {
+		return err
+	}
+	var evicted []keyAndValue
+	var reason Reason
+	var onEvicted evictFunc
+	func() {
+		defer thisCache.unlock() // 关闭恢复时回调的 panic 也会释放写锁
+		evicted, reason = thisCache.overwrite(key, value, dur)
+		onEvicted = thisCache.onEvicted
+	}()
+
+	fireEvicted(onEvicted, evicted, reason)
+	return nil
+}
+
+/***************************************************************************************
+ * 功能描述：获取数据项，若找到数据项，还需要判断数据项是否已经过期，无锁
+ * 输入参数：数据项键名：key string
+ * 输出参数：无
+ * 返 回 值：具体数据项的值以及是否找到(bool)
+ * 其他说明：该函数为 Cache 类方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20180724      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) get(key string) (interface{}, bool, error) {
+	if len(key) == 0 {
+		err := ErrKeyInvalid
+		return nil, false, err
+	}
+	item, found := thisCache.items[key]
+	if !found {
+		return nil, false, nil
+	}
+	if thisCache.expired(item, nanotime()) || item.negative() {
+		return nil, false, nil
+	}
+	return item.Object, found, nil
+}
+
+/***************************************************************************************
+ * 功能描述：获取数据项，若找到数据项，还需要判断数据项是否已经过期
+ * 输入参数：数据项键名：key string
+ * 输出参数：无
+ * 返 回 值：具体数据项的值以及是否找到(bool)
+ * 其他说明：该函数为 Cache 类方法，设置了 loader 时未命中会调用 loader 加载；
+ *           启用 WithCopyOnGet 时返回值的副本；设置了 WithAccessObserver 时在调用 loader 之前报告是否命中
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20180724      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) Get(key string) (interface{}, bool, error) {
+	key, err := thisCache.checkKey(key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	item, found := thisCache.access(key)
+	if thisCache.observer != nil {
+		thisCache.observe(key, found && !item.negative())
+	}
+	if found {
+		if _, failed := item.Object.(loadError); failed && thisCache.loader != nil {
+			return thisCache.load(key) // 错误墓碑，由 load 判断是否仍在退避期内
+		}
+		if item.negative() { // 负缓存命中，不再调用 loader
+			return nil, false, nil
+		}
+		value, err := thisCache.readValue(item.Object)
+		return value, err == nil, err
+	}
+	if thisCache.loader == nil {
+		return nil, false, nil
+	}
+	value, found, err := thisCache.load(key)
+	if !found {
+		return value, found, err
+	}
+	value, err = thisCache.copyValue(value)
+	return value, err == nil, err
+}
+
+/***************************************************************************************
+ * 功能描述：获取数据项，未找到时返回错误而不是 bool
+ * 输入参数：数据项键名：key string
+ * 输出参数：无
+ * 返 回 值：具体数据项的值；不存在或已过期时返回包装了 ErrKeyNotFound 的错误，可用 errors.Is 判断
+ * 其他说明：该函数为 Cache 类方法，行为与 Get 相同(包括调用 loader)，便于与其它返回 error 的调用串联
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) GetOrError(key string) (interface{}, error) {
+	value, found, err := thisCache.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("item %v: %w", key, ErrKeyNotFound)
+	}
+	return value, nil
+}
+
+/***************************************************************************************
+ * 功能描述：判断未过期的数据项是否存在，不返回数据项的值
+ * 输入参数：数据项键名：key string
+ * 输出参数：无
+ * 返 回 值：存在且未过期为true
+ * 其他说明：该函数为 Cache 类方法，只需读锁；不调用 loader，也不记录访问时间
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) Has(key string) bool {
+	key, err := thisCache.checkKey(key)
+	if err != nil {
+		return false
+	}
+	thisCache.mux.RLock()
+	defer thisCache.mux.RUnlock()
+	_, found, _ := thisCache.get(key)
+	return found
+}
+
+/***************************************************************************************
+ * 功能描述：获取未过期的数据项已存在的时长
+ * 输入参数：数据项键名：key string
+ * 输出参数：无
+ * 返 回 值：自数据项写入以来的时长，found 为 true 表示存在且未过期
+ * 其他说明：该函数为 Cache 类方法，只需读锁；与 TTL 无关，Set、Replace 等覆盖写入重新计时，
+ *           Touch 只刷新过期时间，不重新计时；墓碑数据项视为不存在
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) Age(key string) (time.Duration, bool) {
+	key, err := thisCache.checkKey(key)
+	if err != nil {
+		return 0, false
+	}
+	now := nanotime()
+	thisCache.mux.RLock()
+	item, found := thisCache.items[key]
+	thisCache.mux.RUnlock()
+	if !found || thisCache.expired(item, now) || item.negative() {
+		return 0, false
+	}
+	return time.Duration(now - item.Created), true
+}
+
+/***************************************************************************************
+ * 功能描述：刷新数据项的过期时间，无锁操作
+ * 输入参数：数据项键名：key string, 数据项生命周期：dur time.Duration
+ * 输出参数：无
+ * 返 回 值：刷新后的数据项以及是否找到(bool)
+ * 其他说明：该函数为 Cache 类方法，数据项不存在或已过期时不做修改；
+ *           dur 为 DefaultExpiration 时使用默认过期时间，为 NoExpiration 时永不过期
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) touch(key string, dur time.Duration) (Item, bool) {
+	now := nanotime()
+	item, found := thisCache.items[key]
+	if !found || thisCache.expired(item, now) || item.negative() {
+		return item, false
+	}
+	item.Expiration = thisCache.expiration(dur)
+	item.LastAccess = now
+	thisCache.items[key] = item
+	thisCache.markDirty()
+	thisCache.logSet(key, item)
+	return item, true
+}
+
+/***************************************************************************************
+ * 功能描述：刷新数据项的过期时间
+ * 输入参数：数据项键名：key string, 数据项生命周期：dur time.Duration
+ * 输出参数：无
+ * 返 回 值：数据项存在且未过期并已刷新为true
+ * 其他说明：该函数为 Cache 类方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) Touch(key string, dur time.Duration) bool {
+	if thisCache.closed.Load() {
+		return false
+	}
+	key, err := thisCache.checkKey(key)
+	if err != nil {
+		return false
+	}
+	if thisCache.lockWithTimeout() != nil {
+		return false
+	}
+	defer thisCache.unlock()
+	_, found := thisCache.touch(key, dur)
+	return found
+}
+
+/***************************************************************************************
+ * 功能描述：获取数据项并刷新其过期时间(类似 memcached 的 GAT 命令)
+ * 输入参数：数据项键名：key string, 数据项生命周期：dur time.Duration
+ * 输出参数：无
+ * 返 回 值：具体数据项的值以及是否找到(bool)
+ * 其他说明：该函数为 Cache 类方法，查找与刷新在同一个写锁内完成，不会与 gcLoop 竞争；
+ *           数据项不存在或已过期时返回 false 且不做修改，不调用 loader；
+ *           启用 WithCopyOnGet 且复制失败时返回 false
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) GetAndTouch(key string, dur time.Duration) (interface{}, bool) {
+	if thisCache.closed.Load() {
+		return nil, false
+	}
+	key, err := thisCache.checkKey(key)
+	if err != nil {
+		return nil, false
+	}
+	if thisCache.lockWithTimeout() != nil {
+		return nil, false
+	}
+	item, found := thisCache.touch(key, dur)
+	thisCache.unlock()
+	if !found {
+		return nil, false
+	}
+	value, err := thisCache.readValue(item.Object)
+	return value, err == nil
+}
+
+/***************************************************************************************
+ * 功能描述：查找未过期的数据项，启用 idleTTL 或 trackAccess 时记录访问时间
+ * 输入参数：数据项键名：key string
+ * 输出参数：无
+ * 返 回 值：数据项以及是否找到(bool)
+ * 其他说明：该函数为 Cache 类方法，未启用 idleTTL 与 trackAccess 时只需读锁；
+ *           启用 WithDeleteOnExpiredRead 时删除读取到的已过期数据项
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) access(key string) (Item, bool) {
+	item, state := thisCache.accessState(key)
+	return item, state == StateHit
+}
+
+/***************************************************************************************
+ * 功能描述：查找数据项并在同一次读取中判断其状态，命中时记录访问时间
+ * 输入参数：数据项键名：key string
+ * 输出参数：无
+ * 返 回 值：数据项，以及状态 StateHit、StateExpired 或 StateMissing；StateExpired 时返回过期的数据项
+ * 其他说明：该函数为 Cache 类方法，查找与判断在同一个锁内完成，未启用 idleTTL 与 trackAccess 时只需读锁，
+ *           获取写锁超时时同样只读取，不记录访问时间；
+ *           启用 WithDeleteOnExpiredRead 时在释放锁后删除读取到的已过期数据项
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) accessState(key string) (Item, State) {
+	now := nanotime()
+	if (thisCache.idleTTL <= 0 && !thisCache.trackAccess.Load()) || thisCache.lockWithTimeout() != nil { // 获取写锁超时时不记录访问时间
+		thisCache.mux.RLock()
+		item, found := thisCache.items[key]
+		thisCache.mux.RUnlock()
+		if !found {
+			return item, StateMissing
+		}
+		if thisCache.expired(item, now) {
+			thisCache.reapExpired(key, now)
+			return item, StateExpired
+		}
+		return item, StateHit
+	}
+
+	item, found := thisCache.items[key]
+	if !found {
+		thisCache.unlock()
+		return item, StateMissing
+	}
+	if thisCache.expired(item, now) {
+		thisCache.unlock()
+		thisCache.reapExpired(key, now)
+		return item, StateExpired
+	}
+	item.LastAccess = now
+	item.Hits++
+	thisCache.items[key] = item
+	thisCache.unlock()
+	return item, StateHit
+}
+
+/***************************************************************************************
+ * 功能描述：启用 WithDeleteOnExpiredRead 时删除读取到的已过期数据项
+ * 输入参数：数据项键名：key string, 读取时刻：now int64
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，调用时不得持有锁；获取写锁后重新检查，
+ *           期间已被重新写入的数据项不删除；删除后以 Expired 调用 onEvicted
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) reapExpired(key string, now int64) {
+	if !thisCache.deleteOnRead {
+		return
+	}
+	if thisCache.lockWithTimeout() != nil { // 由 gcLoop 清理
+		return
+	}
+	item, found := thisCache.items[key]
+	if !found || !thisCache.expired(item, now) {
+		thisCache.unlock()
+		return
+	}
+	hook := thisCache.takeExpireHook(key)
+	value, evicted, _ := thisCache.delete(key)
+	onEvicted := thisCache.onEvicted
+	thisCache.unlock()
+	if evicted && onEvicted != nil {
+		onEvicted(key, value, Expired)
+	}
+	if evicted && hook != nil {
+		thisCache.fireExpireHooks([]expiredHook{{key, value, hook}})
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：添加数据项，若已存在，返回错误
+ * 输入参数：数据项键名：key string, 数据项键值：value interface{}, 数据项生命周期：dur time.Duration
+ * 输出参数：无
+ * 返 回 值：无 error， 则为 nil；已存在未过期的数据项时返回包装了 ErrKeyExists 的错误
+ * 其他说明：该函数为 Cache 类方法，已过期但尚未被 gcLoop 清理的数据项与墓碑数据项视为不存在，
+ *           Add 成功并覆盖它，被覆盖的过期值在释放锁后以 Expired 调用 onEvicted；
+ *           查找与写入在同一个写锁内完成，并发 Add 同一 key 时只有一个成功
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20180724      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) Add(key string, val interface{}, dur time.Duration) error {
+	if thisCache.closed.Load() {
+		return ErrCacheClosed
+	}
+	key, err := thisCache.checkKey(key)
+	if err != nil {
+		return err
+	}
+	if err := thisCache.checkValueSize(val); err != nil {
+		return err
+	}
+	if err := thisCache.lockWithTimeout(); err != nil {
+		return err
+	}
+	_, found, _ := thisCache.get(key) // get 过滤已过期的数据项
+	if found {
+		thisCache.unlock()
+		return fmt.Errorf("item %s: %w", key, ErrKeyExists)
+	}
+	evicted, reason := thisCache.overwrite(key, val, dur)
+	onEvicted := thisCache.onEvicted
+	thisCache.unlock()
+
+	fireEvicted(onEvicted, evicted, reason)
+	return nil
+}
+
+/***************************************************************************************
+ * 功能描述：若数据项存在则返回其值，否则存入给定的值，类似 sync.Map.LoadOrStore
+ * 输入参数：数据项键名：key string, 数据项键值：value interface{}, 数据项生命周期：dur time.Duration
+ * 输出参数：无
+ * 返 回 值：actual 实际的值，loaded 为 true 表示返回的是已存在的值
+ * 其他说明：该函数为 Cache 类方法，查找与存入在同一个写锁内完成；已过期的数据项视为不存在；
+ *           key 为空或值超过 maxValueBytes 时不存入，返回 (nil, false)
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) LoadOrStore(key string, value interface{}, dur time.Duration) (actual interface{}, loaded bool) {
+	if thisCache.closed.Load() {
+		return nil, false
+	}
+	key, err := thisCache.checkKey(key)
+	if err != nil || thisCache.checkValueSize(value) != nil {
+		return nil, false
+	}
+	if thisCache.lockWithTimeout() != nil {
+		return nil, false
+	}
+	if existing, found, _ := thisCache.get(key); found {
+		thisCache.unlock()
+		existing, _ = thisCache.decodeValue(existing)
+		return existing, true
+	}
+	evicted, reason := thisCache.overwrite(key, value, dur)
+	onEvicted := thisCache.onEvicted
+	thisCache.unlock()
+
+	fireEvicted(onEvicted, evicted, reason)
+	return value, false
+}
+
+/***************************************************************************************
+ * 功能描述：替换一个存在的数据项
+ * 输入参数：数据项键名：key string, 数据项键值：value interface{}, 数据项生命周期：dur time.Duration
+ * 输出参数：无
+ * 返 回 值：无 error， 则为 nil
+ * 其他说明：该函数为 Cache 类方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20180725      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) Replace(key string, val interface{}, dur time.Duration) error {
+	if thisCache.closed.Load() {
+		return ErrCacheClosed
+	}
+	key, err := thisCache.checkKey(key)
+	if err != nil {
+		return err
+	}
+	if err := thisCache.checkValueSize(val); err != nil {
+		return err
+	}
+	if err := thisCache.lockWithTimeout(); err != nil {
+		return err
+	}
+	_, found, _ := thisCache.get(key)
+	if !found {
+		thisCache.unlock()
+		return fmt.Errorf("item %v: %w", key, ErrKeyNotFound)
+	}
+	evicted, reason := thisCache.overwrite(key, val, dur)
+	onEvicted := thisCache.onEvicted
+	thisCache.unlock()
+
+	fireEvicted(onEvicted, evicted, reason)
+	return nil
+}
+
+/***************************************************************************************
+ * 功能描述：存入数据项并返回之前的值(getset)
+ * 输入参数：数据项键名：key string, 数据项键值：value interface{}, 数据项生命周期：dur time.Duration
+ * 输出参数：无
+ * 返 回 值：previous 之前未过期的值，existed 为 true 表示之前存在未过期的值
+ * 其他说明：该函数为 Cache 类方法，查找与存入在同一个写锁内完成，dur 的处理与 Set 相同；
+ *           被覆盖的值在释放锁后以 Replaced 调用 onEvicted；key 为空或值超过 maxValueBytes 时不存入
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) Swap(key string, value interface{}, dur time.Duration) (previous interface{}, existed bool) {
+	if thisCache.closed.Load() {
+		return nil, false
+	}
+	key, err := thisCache.checkKey(key)
+	if err != nil || thisCache.checkValueSize(value) != nil {
+		return nil, false
+	}
+	if thisCache.lockWithTimeout() != nil {
+		return nil, false
+	}
+	previous, existed, _ = thisCache.get(key)
+	evicted, reason := thisCache.overwrite(key, value, dur)
+	onEvicted := thisCache.onEvicted
+	thisCache.unlock()
+
+	fireEvicted(onEvicted, evicted, reason)
+	if existed {
+		previous, _ = thisCache.decodeValue(previous)
+	}
+	return previous, existed
+}
+
+/***************************************************************************************
+ * 功能描述：替换已有数据项的值，保留其过期时间(类似 Redis 的 SET ... KEEPTTL)
+ * 输入参数：数据项键名：key string, 数据项键值：value interface{}
+ * 输出参数：无
+ * 返 回 值：数据项存在且未过期并已替换为 true
+ * 其他说明：该函数为 Cache 类方法，与 Replace 不同，不重新计算过期时间；
+ *           数据项不存在、已过期、key 为空或值超过 maxValueBytes 时不做修改；
+ *           被替换的值在释放锁后以 Replaced 调用 onEvicted
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) SetKeepTTL(key string, value interface{}) bool {
+	if thisCache.closed.Load() {
+		return false
+	}
+	key, err := thisCache.checkKey(key)
+	if err != nil || thisCache.checkValueSize(value) != nil {
+		return false
+	}
+	if thisCache.lockWithTimeout() != nil {
+		return false
+	}
+	item, found := thisCache.items[key]
+	if !found || thisCache.expired(item, nanotime()) || item.negative() {
+		thisCache.unlock()
+		return false
+	}
+	previous := item.Object
+	value, err = thisCache.encodeValue(value)
+	if err != nil {
+		thisCache.unlock()
+		thisCache.logger.Errorf("cache encode %s: %v", key, err)
+		return false
+	}
+	item.Object = value
+	item.Version = thisCache.nextVersion()
+	thisCache.putItem(key, item)
+	onEvicted := thisCache.onEvicted
+	thisCache.unlock()
+
+	fireEvicted(onEvicted, []keyAndValue{{key, previous}}, Replaced)
+	return true
+}
+
+/***************************************************************************************
+ * 功能描述：在写锁内以 fn 的返回值更新数据项
+ * 输入参数：数据项键名：key string,
+ *           更新函数：fn func(old interface{}, found bool) (value interface{}, ttl time.Duration, keep bool)
+ * 输出参数：无
+ * 返 回 值：更新后数据项存在为 true；keep 为 false、key 为空、新值超过 maxValueBytes 或 fn panic 时为 false
+ * 其他说明：该函数为 Cache 类方法，读取、计算与写入在同一个写锁内完成，可替代读-改-写；
+ *           old 为当前未过期的值，found 为 false 表示不存在；keep 为 true 时以 ttl 存入 value，
+ *           为 false 时删除数据项。fn 在持有写锁时调用，不得调用本缓存的任何方法；
+ *           被覆盖或删除的值在释放锁后调用 onEvicted
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) Update(key string, fn func(old interface{}, found bool) (value interface{}, ttl time.Duration, keep bool)) bool {
+	if thisCache.closed.Load() {
+		return false
+	}
+	key, err := thisCache.checkKey(key)
+	if err != nil {
+		return false
+	}
+	var evicted []keyAndValue
+	var reason Reason
+	var stored bool
+	if thisCache.lockWithTimeout() != nil {
+		return false
+	}
+	onEvicted := thisCache.onEvicted
+	func() {
+		defer thisCache.unlock() // fn panic 时同样释放锁
+		old, found, _ := thisCache.get(key)
+		if found {
+			old, _ = thisCache.decodeValue(old)
+		}
+		var value interface{}
+		var ttl time.Duration
+		var keep bool
+		if thisCache.protect("update", key, func() error {
+			value, ttl, keep = fn(old, found)
+			return nil
+		}) != nil {
+			return
+		}
+		if !keep {
+			if deleted, ok, _ := thisCache.delete(key); ok {
+				evicted, reason = []keyAndValue{{key, deleted}}, Deleted
+				if !found { // 删除的是已过期但尚未清理的值
+					reason = Expired
+				}
+			}
+			return
+		}
+		if thisCache.checkValueSize(value) != nil {
+			return
+		}
+		evicted, reason = thisCache.overwrite(key, value, ttl)
+		stored = true
+	}()
+
+	fireEvicted(onEvicted, evicted, reason)
+	return stored
+}
+
+/***************************************************************************************
+ * 功能描述：将缓存数据项写入到io.Writer中
+ * 输入参数：wrt io.Writer
+ * 输出参数：无
+ * 返 回 值：无 error， 则为 nil
+ * 其他说明：该函数为 Cache 类方法，使用 WithCodec 设置的编解码器，默认为 GobCodec；
+ *           数据项逐个编码，格式见 snapshot.go；无法编码的数据项被跳过并记录日志，不影响其余数据项，
+ *           需要得到跳过的键名时使用 SavePartial；为兼容已有调用者保持只返回 error
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20180725      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) Save(wrt io.Writer) (err error) {
+	_, err = thisCache.saveItems(wrt, nil)
+	return err
+}
+
+/***************************************************************************************
+ * 功能描述：将缓存数据项写入到io.Writer中，返回无法编码而跳过的键名
+ * 输入参数：wrt io.Writer
+ * 输出参数：无
+ * 返 回 值：failed 无法编码而未写出的键名；无 error 则为 nil，写入失败时返回该错误
+ * 其他说明：该函数为 Cache 类方法，与 Save 相同，由 Load 读取；
+ *           编码失败或 panic 的数据项被跳过并记录在 failed 中，其余数据项照常写出
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) SavePartial(wrt io.Writer) (failed []string, err error) {
+	return thisCache.saveItems(wrt, nil)
+}
+
+/***************************************************************************************
+ * 功能描述：将满足条件的未过期数据项写入到io.Writer中
+ * 输入参数：wrt io.Writer, 过滤条件：pred func(key string, item Item) bool
+ * 输出参数：无
+ * 返 回 值：无 error， 则为 nil
+ * 其他说明：该函数为 Cache 类方法，只保存 pred 返回 true 的数据项，已过期的数据项总是被排除；
+ *           输出格式与 Save 相同，可以用 Load 读取；与 Save 一样跳过无法编码的数据项
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) SaveFunc(wrt io.Writer, pred func(key string, item Item) bool) error {
+	now := nanotime()
+	_, err := thisCache.saveItems(wrt, func(key string, item Item) bool {
+		return !thisCache.expired(item, now) && pred(key, item)
+	})
+	return err
+}
+
+/***************************************************************************************
+ * 功能描述：将缓存数据项从内存中保存到文件中
+ * 输入参数：file string 要打开的文件名
+ * 输出参数：无
+ * 返 回 值：无 error， 则为 nil
+ * 其他说明：该函数为 Cache 类方法，先写入同目录下的临时文件，成功后再重命名替换 file；
+ *           与 Save 一样跳过无法编码的数据项，写入失败时删除临时文件，原文件保持不变，
+ *           任何情况下文件描述符都会被关闭
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20180725      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) SaveMemToFile(file string) (err error) {
+	if len(file) == 0 {
+		err := ErrFileInvalid
+		return err
+	}
+	temp, err := thisCache.saveTemp(file)
+	if err != nil {
+		return err
+	}
+	if err = renameFile(temp, file); err != nil {
+		os.Remove(temp)
+		thisCache.logger.Errorf("cache save to %s: %v", file, err)
+		return err
+	}
+	return nil
+}
+
+/***************************************************************************************
+ * 功能描述：将缓存数据项保存到文件中，并保留之前的 keep 份快照
+ * 输入参数：文件名：basePath string, 保留的历史快照数量：keep int
+ * 输出参数：无
+ * 返 回 值：无 error， 则为 nil
+ * 其他说明：该函数为 Cache 类方法，最新的快照为 basePath，之前的快照依次为 basePath.1(最近)、
+ *           basePath.2 … basePath.keep，超出 keep 的最旧快照被删除；keep <= 0 时与 SaveMemToFile 相同。
+ *           先写入临时文件并 fsync，成功后才轮转历史快照，最后将临时文件重命名为 basePath；
+ *           最旧的快照先改名暂存，全部成功后才删除。轮转或最后的重命名失败时按相反顺序撤销已完成的重命名，
+ *           basePath 与历史快照恢复原状；轮转过程中某个文件不存在时跳过。
+ *           轮转不是原子的，不应对同一 basePath 并发调用
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) SaveMemToFileRotating(basePath string, keep int) (err error) {
+	if len(basePath) == 0 {
+		err := ErrFileInvalid
+		return err
+	}
+	temp, err := thisCache.saveTemp(basePath)
+	if err != nil {
+		return err
+	}
+	if keep <= 0 {
+		if err = renameFile(temp, basePath); err != nil {
+			os.Remove(temp)
+			thisCache.logger.Errorf("cache save to %s: %v", basePath, err)
+		}
+		return err
+	}
+
+	var done [][2]string // 已完成的重命名，失败时按相反顺序撤销
+	rename := func(from, to string) error {
+		err := renameFile(from, to)
+		if err == nil {
+			done = append(done, [2]string{from, to})
+		} else if os.IsNotExist(err) {
+			err = nil
+		}
+		return err
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		for i := len(done) - 1; i >= 0; i-- {
+			if e := renameFile(done[i][1], done[i][0]); e != nil {
+				thisCache.logger.Errorf("cache save to %s: restore %s: %v", basePath, done[i][0], e)
+			}
+		}
+		os.Remove(temp)
+		thisCache.logger.Errorf("cache save to %s: %v", basePath, err)
+	}()
+
+	dropped := temp + ".old" // 最旧的快照，成功后删除
+	if err = rename(fmt.Sprintf("%s.%d", basePath, keep), dropped); err != nil {
+		return err
+	}
+	for i := keep - 1; i >= 0; i-- {
+		from := basePath
+		if i > 0 {
+			from = fmt.Sprintf("%s.%d", basePath, i)
+		}
+		if err = rename(from, fmt.Sprintf("%s.%d", basePath, i+1)); err != nil {
+			return err
+		}
+	}
+	if err = renameFile(temp, basePath); err != nil {
+		return err
+	}
+	os.Remove(dropped)
+	return nil
+}
+
+/***************************************************************************************
+ * 功能描述：将缓存数据项保存到与 file 同目录的临时文件中
+ * 输入参数：目标文件名：file string
+ * 输出参数：无
+ * 返 回 值：临时文件名，无 error 则为 nil
+ * 其他说明：该函数为 Cache 类方法，无法编码的数据项由 Save 跳过，写入失败时删除临时文件，
+ *           任何情况下文件描述符都会被关闭；成功时由调用者重命名临时文件
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) saveTemp(file string) (temp string, err error) {
+	fp, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".tmp*")
+	if err != nil {
+		thisCache.logger.Errorf("cache save to %s: %v", file, err)
+		return "", err
+	}
+	defer func() {
+		if err != nil {
+			fp.Close()
+			os.Remove(fp.Name())
+		}
+	}()
+	if err = thisCache.Save(fp); err != nil {
+		return "", err
+	}
+	if err = fp.Sync(); err != nil {
+		thisCache.logger.Errorf("cache save to %s: %v", file, err)
+		return "", err
+	}
+	if err = fp.Close(); err != nil {
+		thisCache.logger.Errorf("cache save to %s: %v", file, err)
+		return "", err
+	}
+	return fp.Name(), nil
+}
+
+/***************************************************************************************
+ * 功能描述：从io.Reader中读取数据项
+ * 输入参数：rd io.Reader
+ * 输出参数：无
+ * 返 回 值：无 error， 则为 nil
+ * 其他说明：该函数为 Cache 类方法，使用 WithCodec 设置的编解码器，默认为 GobCodec；
+ *           读取的数据项覆盖缓存中同名的数据项(包括未过期的)，缓存中其它数据项保持不变；
+ *           读取的数据项已过期时跳过；解码失败的数据项交给 WithItemMigrator 设置的迁移函数，迁移函数返回 false 时跳过该数据项
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20180725      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) Load(rd io.Reader) error {
+	items, err := thisCache.readSnapshot(rd) // 解码，反序列化
+	if err != nil {
+		thisCache.logger.Errorf("cache load: %v", err)
+		return err
+	}
+	if err = thisCache.lockWithTimeout(); err != nil {
+		return err
+	}
+	defer thisCache.unlock()
+
+	now := nanotime()
+	for key, val := range items {
+		if thisCache.expired(val, now) { // 读取的数据项已过期，不加载
+			continue
+		}
+		val.Version = thisCache.nextVersion()
+		thisCache.putItem(key, val)
+	}
+	return nil
+}
+
+/***************************************************************************************
+ * 功能描述：从io.Reader中读取数据项，与缓存中已有的数据项冲突时由 resolve 决定保留哪一个
+ * 输入参数：rd io.Reader, 冲突处理函数：resolve func(key string, existing, incoming Item) Item
+ * 输出参数：无
+ * 返 回 值：无 error， 则为 nil
+ * 其他说明：该函数为 Cache 类方法，使用 WithCodec 设置的编解码器；
+ *           缓存中不存在或已过期的键名直接存入读取的数据项，读取的数据项已过期时跳过；
+ *           两边都存在时调用 resolve，返回 existing 时不做修改，否则存入返回的数据项
+ *           (包括已过期的 incoming)。incoming 的 Version 为 0，存入时重新分配版本号；
+ *           resolve 在持有写锁时调用，不得调用本缓存的任何方法，panic 时保留 existing；
+ *           启用 WithSerializedValues 时 resolve 收到解码后的值，返回的数据项重新编码后存入，
+ *           任一方解码失败或编码失败时保留 existing；与 Load 一样不调用 onEvicted，也一样调用迁移函数
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) LoadMerge(rd io.Reader, resolve func(key string, existing, incoming Item) Item) error {
+	items, err := thisCache.readSnapshot(rd) // 解码，反序列化
+	if err != nil {
+		thisCache.logger.Errorf("cache load: %v", err)
+		return err
+	}
+	if err = thisCache.lockWithTimeout(); err != nil {
+		return err
+	}
+	defer thisCache.unlock()
+
+	now := nanotime()
 	for key, val := range items {
+		val.Version = 0
 		theItem, found := thisCache.items[key]
-		if found && !theItem.Expired() {
-			thisCache.items[key] = val
+		if !found || thisCache.expired(theItem, now) || theItem.negative() {
+			if !thisCache.expired(val, now) {
+				val.Version = thisCache.nextVersion()
+				thisCache.putItem(key, val)
+			}
+			continue
+		}
+		existing, err := thisCache.plainItem(theItem)
+		if err != nil {
+			continue
+		}
+		incoming, err := thisCache.plainItem(val)
+		if err != nil {
+			continue
+		}
+		winner := existing
+		thisCache.protect("resolve", key, func() error {
+			winner = resolve(key, existing, incoming)
+			return nil
+		})
+		if winner.Version == theItem.Version { // 保留 existing
+			continue
+		}
+		if winner, err = thisCache.storedItem(winner); err != nil {
+			thisCache.logger.Errorf("cache encode %s: %v", key, err)
+			continue
+		}
+		winner.Version = thisCache.nextVersion()
+		thisCache.putItem(key, winner)
+	}
+	return nil
+}
+
+/***************************************************************************************
+ * 功能描述：将单个数据项(值与过期时间)序列化
+ * 输入参数：数据项键名：key string
+ * 输出参数：无
+ * 返 回 值：序列化后的数据，是否找到(bool)，无 error 则为 nil
+ * 其他说明：该函数为 Cache 类方法，使用 gob 编码，与 Save 一样需要注册数据项的值类型
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) MarshalItem(key string) (data []byte, found bool, err error) {
+	if key, err = thisCache.checkKey(key); err != nil {
+		return nil, false, err
+	}
+	thisCache.mux.RLock()
+	item, found := thisCache.items[key]
+	thisCache.mux.RUnlock()
+	if !found || thisCache.expired(item, nanotime()) || item.negative() {
+		return nil, false, nil
+	}
+
+	defer func() {
+		if e := recover(); e != nil {
+			data, found, err = nil, false, fmt.Errorf("Error registring item type with Gob lib.")
+			return
+		}
+	}()
+	var buf bytes.Buffer
+	if err = (GobCodec{}).NewEncoder(&buf).Encode(&item); err != nil {
+		return nil, false, err
+	}
+	return buf.Bytes(), true, nil
+}
+
+/***************************************************************************************
+ * 功能描述：将 MarshalItem 序列化的数据项反序列化并存入缓存
+ * 输入参数：数据项键名：key string, 序列化后的数据：data []byte
+ * 输出参数：无
+ * 返 回 值：无 error， 则为 nil
+ * 其他说明：该函数为 Cache 类方法，数据项已经过期时不存入缓存；若数据项存在则覆盖；
+ *           解码失败时交给 WithItemMigrator 设置的迁移函数，迁移函数返回 false 时不存入并返回 nil
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) UnmarshalItem(key string, data []byte) error {
+	if thisCache.closed.Load() {
+		return ErrCacheClosed
+	}
+	key, err := thisCache.checkKey(key)
+	if err != nil {
+		return err
+	}
+	var item Item
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&item); err != nil {
+		var ok bool
+		if item, ok, err = thisCache.migrateItem(key, data, err); err != nil || !ok {
+			return err
 		}
 	}
+	if item.Expired() {
+		return nil
+	}
+	item.LastAccess = nanotime()
+	if err := thisCache.lockWithTimeout(); err != nil {
+		return err
+	}
+	evicted, reason := thisCache.displaced(key)
+	item.Version = thisCache.nextVersion()
+	thisCache.putItem(key, item)
+	onEvicted := thisCache.onEvicted
+	thisCache.unlock()
+
+	fireEvicted(onEvicted, evicted, reason)
 	return nil
 }
 
@@ -525,6 +2088,7 @@ func (thisCache *Cache) LoadFileToMem(file string) error {
 	}
 	fp, err := os.Open(file)
 	if err != nil {
+		thisCache.logger.Errorf("cache load from %s: %v", file, err)
 		return err
 	}
 	if err = thisCache.Load(fp); err != nil {
@@ -534,38 +2098,292 @@ func (thisCache *Cache) LoadFileToMem(file string) error {
 	return fp.Close()
 }
 
+/***************************************************************************************
+ * 功能描述：获取缓存数据项的内存快照
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：map[string]Item 所有数据项的副本
+ * 其他说明：该函数为 Cache 类方法，保留数据项的绝对过期时间(包括已过期但尚未清理的数据项)，
+ *           返回的 map 与缓存相互独立，但数据项的值为浅拷贝；
+ *           启用 WithSerializedValues 时在释放锁后解码，解码失败的数据项被跳过
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) Snapshot() map[string]Item {
+	thisCache.mux.RLock()
+	snap := make(map[string]Item, len(thisCache.items))
+	for key, val := range thisCache.items {
+		if val.negative() {
+			continue
+		}
+		snap[key] = val
+	}
+	thisCache.mux.RUnlock()
+
+	if thisCache.valueCodec != nil {
+		for key, val := range snap {
+			if val, err := thisCache.plainItem(val); err == nil {
+				snap[key] = val
+			} else {
+				delete(snap, key)
+			}
+		}
+	}
+	return snap
+}
+
+/***************************************************************************************
+ * 功能描述：用内存快照恢复缓存
+ * 输入参数：快照：snap map[string]Item, 是否丢弃已过期数据项：skipExpired bool
+ * 输出参数：无
+ * 返 回 值：无 error， 则为 nil；获取写锁超时返回 ErrLockTimeout，缓存不做修改
+ * 其他说明：该函数为 Cache 类方法，缓存的全部数据项被替换为快照中的数据项，用于回滚；
+ *           恢复后缓存与 snap 相互独立；启用 WithSerializedValues 时编码后存入，编码失败的数据项被跳过
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) Restore(snap map[string]Item, skipExpired bool) error {
+	items := make(map[string]Item, len(snap))
+	for key, val := range snap {
+		if skipExpired && val.Expired() {
+			continue
+		}
+		val, err := thisCache.storedItem(val)
+		if err != nil {
+			thisCache.logger.Errorf("cache encode %s: %v", key, err)
+			continue
+		}
+		items[key] = val
+	}
+	if err := thisCache.lockWithTimeout(); err != nil {
+		return err
+	}
+	defer thisCache.unlock()
+	for key, val := range items { // 重新分配版本号，回滚后旧的版本号不再有效
+		val.Version = thisCache.nextVersion()
+		items[key] = val
+	}
+	thisCache.items = items
+	thisCache.count.Store(int64(len(items)))
+	thisCache.peak = int64(len(items))
+	thisCache.reindex()
+	thisCache.recountQuotas()
+	thisCache.markDirty()
+	thisCache.expireHooks = nil
+	thisCache.logFlush()
+	thisCache.notify(EventFlushed, "", nil)
+	for key, val := range items {
+		thisCache.logSet(key, val)
+	}
+	return nil
+}
+
 /***************************************************************************************
  * 功能描述：统计当前缓存数据项的数量
  * 输入参数：无
  * 输出参数：无
  * 返 回 值：int 缓存数量
- * 其他说明：该函数为 Cache 类方法
+ * 其他说明：该函数为 Cache 类方法，不加锁，读取随 items 一起维护的计数
  *
  * 修改日期      版本号      修改人      修改内容
  * ------------------------------------------------------------------------------------
  * 20180725      v1.0        xj      创建
  * ************************************************************************************/
 func (thisCache *Cache) Count() int {
+	return int(thisCache.count.Load())
+}
+
+/***************************************************************************************
+ * 功能描述：统计未过期的缓存数据项数量
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：int 未过期的数据项数量
+ * 其他说明：该函数为 Cache 类方法，需要遍历所有数据项；Count 返回的是包括未清理过期数据项的 map 大小
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) CountLive() int {
+	live, _ := thisCache.countByExpiration()
+	return live
+}
+
+/***************************************************************************************
+ * 功能描述：统计已过期但尚未被清理的缓存数据项数量
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：int 已过期的数据项数量
+ * 其他说明：该函数为 Cache 类方法，可用于衡量 gcLoop 的清理滞后
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) CountExpired() int {
+	_, expired := thisCache.countByExpiration()
+	return expired
+}
+
+/***************************************************************************************
+ * 功能描述：分别统计未过期与已过期的缓存数据项数量
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：未过期的数量，已过期的数量
+ * 其他说明：该函数为 Cache 类方法，墓碑数据项不计入
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) countByExpiration() (live, expired int) {
+	now := nanotime()
 	thisCache.mux.RLock()
 	defer thisCache.mux.RUnlock()
-	return len(thisCache.items)
+
+	for _, val := range thisCache.items {
+		if val.negative() {
+			continue
+		}
+		if thisCache.expired(val, now) {
+			expired++
+		} else {
+			live++
+		}
+	}
+	return live, expired
 }
 
 /***************************************************************************************
  * 功能描述：清空缓存
  * 输入参数：无
  * 输出参数：无
- * 返 回 值：无
- * 其他说明：该函数为 Cache 类方法
+ * 返 回 值：int 被清空的数据项数量
+ * 其他说明：该函数为 Cache 类方法，释放锁后对每个被清空的数据项调用 onEvicted(Flushed)；
+ *           未设置回调时直接替换 map；新 map 按 initialCapacity 预分配
  *
  * 修改日期      版本号      修改人      修改内容
  * ------------------------------------------------------------------------------------
  * 20180725      v1.0        xj      创建
  * ************************************************************************************/
-func (thisCache *Cache) Flush() {
-	thisCache.mux.Lock()
-	defer thisCache.mux.Unlock()
-	thisCache.items = map[string]Item{}
+func (thisCache *Cache) Flush() int {
+	if thisCache.lockWithTimeout() != nil {
+		return 0
+	}
+	items := thisCache.items
+	onEvicted := thisCache.onEvicted
+	thisCache.items = make(map[string]Item, thisCache.initialCapacity)
+	thisCache.count.Store(0)
+	thisCache.peak = 0
+	thisCache.reindex()
+	thisCache.recountQuotas()
+	thisCache.markDirty()
+	thisCache.expireHooks = nil
+	thisCache.logFlush()
+	thisCache.notify(EventFlushed, "", nil)
+	thisCache.unlock()
+
+	thisCache.logger.Debugf("cache flush: evicted %d items", len(items))
+	if onEvicted == nil {
+		return len(items)
+	}
+	evictedItems := make([]keyAndValue, 0, len(items))
+	for key, val := range items {
+		if !val.negative() {
+			evictedItems = append(evictedItems, keyAndValue{key, val.Object})
+		}
+	}
+	fireEvicted(onEvicted, evictedItems, Flushed)
+	return len(items)
+}
+
+/***************************************************************************************
+ * 功能描述：清空缓存，保留 map 已分配的容量
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：int 被清空的数据项数量
+ * 其他说明：该函数为 Cache 类方法，与 Flush 不同，逐个 delete 数据项而不替换 map，已分配的 bucket 被复用；
+ *           清空比 Flush 慢(与数据项数量成正比且在写锁内)，但之后重新填充时不需要重新分配，
+ *           适用于周期性清空后再填满的缓存；map 的容量不会缩小，数据项数量大幅下降后应改用 Flush。
+ *           释放锁后对每个被清空的数据项调用 onEvicted(Flushed)
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) FlushKeep() int {
+	var evictedItems []keyAndValue
+	if thisCache.lockWithTimeout() != nil {
+		return 0
+	}
+	onEvicted := thisCache.onEvicted
+	flushed := len(thisCache.items)
+	for key, val := range thisCache.items {
+		if onEvicted != nil && !val.negative() {
+			evictedItems = append(evictedItems, keyAndValue{key, val.Object})
+		}
+		delete(thisCache.items, key)
+	}
+	thisCache.count.Store(0)
+	thisCache.peak = 0
+	thisCache.reindex()
+	thisCache.recountQuotas()
+	thisCache.markDirty()
+	thisCache.expireHooks = nil
+	thisCache.logFlush()
+	thisCache.notify(EventFlushed, "", nil)
+	thisCache.unlock()
+
+	thisCache.logger.Debugf("cache flush: evicted %d items", flushed)
+	if onEvicted != nil {
+		fireEvicted(onEvicted, evictedItems, Flushed)
+	}
+	return flushed
+}
+
+/***************************************************************************************
+ * 功能描述：取出所有未过期的数据项并清空缓存
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：map[string]Item 清空前所有未过期的数据项
+ * 其他说明：该函数为 Cache 类方法，在一次写锁内完成 Snapshot 与 Flush，用于滚动重启时移交数据；
+ *           数据项被移交而不是淘汰，不调用 onEvicted；启用 WithSerializedValues 时返回解码后的值，解码失败的被跳过
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) Drain() map[string]Item {
+	now := nanotime()
+	if thisCache.lockWithTimeout() != nil {
+		return nil
+	}
+	items := thisCache.items
+	thisCache.items = make(map[string]Item, thisCache.initialCapacity)
+	thisCache.count.Store(0)
+	thisCache.peak = 0
+	thisCache.reindex()
+	thisCache.recountQuotas()
+	thisCache.markDirty()
+	thisCache.expireHooks = nil
+	thisCache.logFlush()
+	thisCache.notify(EventFlushed, "", nil)
+	thisCache.unlock()
+
+	drained := make(map[string]Item, len(items))
+	for key, val := range items {
+		if val.negative() || thisCache.expired(val, now) {
+			continue
+		}
+		if val, err := thisCache.plainItem(val); err == nil {
+			drained[key] = val
+		}
+	}
+	return drained
 }
 
 /***************************************************************************************
@@ -573,12 +2391,42 @@ func (thisCache *Cache) Flush() {
  * 输入参数：无
  * 输出参数：无
  * 返 回 值：无
- * 其他说明：该函数为 Cache 类方法
+ * 其他说明：该函数为 Cache 类方法，gcLoop 未运行时直接返回；启用 WithLazyGC 且尚未写入时，
+ *           之后的写入也不再启动 gcLoop
  *
  * 修改日期      版本号      修改人      修改内容
  * ------------------------------------------------------------------------------------
  * 20180725      v1.0        xj      创建
  * ************************************************************************************/
 func (thisCache *Cache) StopGc() {
-	thisCache.stopGc <- true
+	thisCache.gcOnce.Do(func() {}) // 尚未启动的 gcLoop 不再随首次写入启动
+	thisCache.gcMux.Lock()
+	defer thisCache.gcMux.Unlock()
+	if !thisCache.gcRunning {
+		return
+	}
+	close(thisCache.stopGc) // 关闭而非发送，避免在持有 gcMux 时阻塞
+	thisCache.gcRunning = false
+}
+
+/***************************************************************************************
+ * 功能描述：关闭缓存
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：关闭增量持久化日志的错误，无 error 则为 nil
+ * 其他说明：该函数为 Cache 类方法，停止 gcLoop 与定时保存并关闭增量持久化日志；
+ *           关闭后所有写入数据项的方法都不再写入：返回 error 的方法(Set、Add、Replace、Increment、
+ *           SetWithCAS、Import、Put、UnmarshalItem、GetOrCompute、GetOrLoad、GetOrLoadMulti 等)返回 ErrCacheClosed，
+ *           其它方法(Swap、LoadOrStore、SetKeepTTL、Update、Touch、GetAndTouch、Counter.Add 等)返回零值或 false；
+ *           读取与删除仍可使用；可重复调用
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) Close() error {
+	thisCache.closed.Store(true)
+	thisCache.StopGc()
+	thisCache.StopAutoSave()
+	return thisCache.CloseLog()
 }
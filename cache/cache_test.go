@@ -0,0 +1,860 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：cache_test.go
+ * 内容摘要：缓存基本操作测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：测试闲置过期：超过闲置时长未被访问的数据项过期，Get 命中会延长闲置期限
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：前移 clockWall 模拟时间流逝
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestIdleExpiration(t *testing.T) {
+	saved := clockWall
+	defer func() { clockWall = saved }()
+
+	cacher, _ := NewCache(0, 0, WithIdleExpiration(time.Minute))
+	cacher.Set("a", 1, 0)
+	cacher.Set("b", 2, 0)
+	clockWall += int64(40 * time.Second)
+	if _, found, _ := cacher.Get("a"); !found {
+		t.Fatal("a expired before idle timeout")
+	}
+	clockWall += int64(40 * time.Second)
+	if _, found, _ := cacher.Get("a"); !found {
+		t.Error("a expired although read 40s ago")
+	}
+	if _, found, _ := cacher.Get("b"); found {
+		t.Error("b found after 80s idle")
+	}
+	cacher.DeleteExpired()
+	if got := cacher.Count(); got != 1 {
+		t.Errorf("Count after DeleteExpired = %d, want 1", got)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 WithAccessTracking 在 Get 命中时记录访问时间，未启用时不记录
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestAccessTracking(t *testing.T) {
+	saved := clockWall
+	defer func() { clockWall = saved }()
+
+	for _, tracking := range []bool{true, false} {
+		var opts []Option
+		if tracking {
+			opts = append(opts, WithAccessTracking())
+		}
+		cacher, _ := NewCache(0, 0, opts...)
+		cacher.Set("a", 1, 0)
+		written := cacher.Snapshot()["a"].LastAccess
+		clockWall += int64(time.Second)
+		cacher.Get("a")
+		read := cacher.Snapshot()["a"].LastAccess
+		if tracking && read-written < int64(time.Second) {
+			t.Errorf("tracking: LastAccess advanced %v, want at least 1s", time.Duration(read-written))
+		}
+		if !tracking && read != written {
+			t.Errorf("no tracking: LastAccess advanced %v, want unchanged", time.Duration(read-written))
+		}
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 MarshalItem/UnmarshalItem 往返保留值与过期时间
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：不存在的数据项返回未找到，已过期的数据不存入，无法解码的数据返回错误
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestMarshalItem(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	cacher.Set("a", "one", time.Hour)
+	cacher.Set("short", 1, 10*time.Millisecond)
+
+	data, found, err := cacher.MarshalItem("a")
+	if err != nil || !found {
+		t.Fatalf("MarshalItem(a) = %v, %v", found, err)
+	}
+	if _, found, _ = cacher.MarshalItem("missing"); found {
+		t.Error("MarshalItem(missing) found")
+	}
+	short, _, _ := cacher.MarshalItem("short")
+
+	other, _ := NewCache(0, 0)
+	if err = other.UnmarshalItem("b", data); err != nil {
+		t.Fatal(err)
+	}
+	if value, found, _ := other.Get("b"); !found || value != "one" {
+		t.Errorf("b = %v, %v, want one", value, found)
+	}
+	if ttl := other.Expirations()["b"]; ttl <= 59*time.Minute || ttl > time.Hour {
+		t.Errorf("b ttl = %v, want about 1h", ttl)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if err = other.UnmarshalItem("short", short); err != nil || other.Has("short") {
+		t.Errorf("expired item: err %v, stored %v, want nil, false", err, other.Has("short"))
+	}
+	if err = other.UnmarshalItem("bad", []byte("not gob")); err == nil {
+		t.Error("UnmarshalItem(bad) = nil, want a decode error")
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 ExpiredKeys 列出已过期但未清理的数据项且不删除它们
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：清理周期为 1 小时，测试期间 gcLoop 不会运行
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestExpiredKeys(t *testing.T) {
+	cacher, _ := NewCache(0, time.Hour)
+	defer cacher.StopGc()
+	cacher.Set("a", 1, 10*time.Millisecond)
+	cacher.Set("b", 2, 10*time.Millisecond)
+	cacher.Set("live", 3, time.Hour)
+	if keys := cacher.ExpiredKeys(); len(keys) != 0 {
+		t.Fatalf("ExpiredKeys before expiry = %v, want none", keys)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	keys := cacher.ExpiredKeys()
+	sort.Strings(keys)
+	if fmt.Sprint(keys) != "[a b]" {
+		t.Errorf("ExpiredKeys = %v, want [a b]", keys)
+	}
+	if got := cacher.Count(); got != 3 {
+		t.Errorf("Count = %d, want 3 (ExpiredKeys must not delete)", got)
+	}
+	cacher.DeleteExpired()
+	if keys = cacher.ExpiredKeys(); len(keys) != 0 {
+		t.Errorf("ExpiredKeys after DeleteExpired = %v, want none", keys)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试自适应清理周期：过期比例高时缩短、低时延长，并限制在 [min, max] 内
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：首次与重置后的周期为 gcInterval，未启用自适应时总为 gcInterval
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestAdaptiveGC(t *testing.T) {
+	cacher, _ := NewCache(0, 0) // 不启动 gcLoop，直接调用 nextGcInterval
+	WithAdaptiveGC(10*time.Second, 4*time.Minute)(cacher)
+	cacher.gcInterval = time.Minute
+	tests := []struct {
+		interval        time.Duration
+		scanned, reaped int
+		want            time.Duration
+	}{
+		{0, 0, 0, time.Minute},
+		{time.Minute, 100, 50, 30 * time.Second},
+		{time.Minute, 100, 10, time.Minute},
+		{time.Minute, 100, 1, 2 * time.Minute},
+		{time.Minute, 0, 0, 2 * time.Minute},
+		{20 * time.Second, 100, 90, 10 * time.Second},
+		{4 * time.Minute, 100, 0, 4 * time.Minute},
+	}
+	for _, test := range tests {
+		if got, _ := cacher.nextGcInterval(test.interval, test.scanned, test.reaped); got != test.want {
+			t.Errorf("nextGcInterval(%v, %d, %d) = %v, want %v", test.interval, test.scanned, test.reaped, got, test.want)
+		}
+	}
+
+	fixed, _ := NewCache(0, 0)
+	fixed.gcInterval = time.Minute
+	if got, _ := fixed.nextGcInterval(time.Minute, 100, 100); got != time.Minute {
+		t.Errorf("non-adaptive nextGcInterval = %v, want 1m", got)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 WithMaxValueBytes 拒绝过大的 []byte 与 string，其它类型只在 WithGobValueSize 时按编码长度限制
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：被拒绝的值不写入缓存，也不覆盖已有的值
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestMaxValueBytes(t *testing.T) {
+	cacher, _ := NewCache(0, 0, WithMaxValueBytes(8))
+	if err := cacher.Set("ok", "12345678", 0); err != nil {
+		t.Fatalf("Set at the limit: %v", err)
+	}
+	if err := cacher.Set("ok", "123456789", 0); !errors.Is(err, ErrValueTooBig) {
+		t.Errorf("Set string over the limit = %v, want ErrValueTooBig", err)
+	}
+	if value, _, _ := cacher.Get("ok"); value != "12345678" {
+		t.Errorf("ok = %v, want the original value", value)
+	}
+	if err := cacher.Add("bytes", make([]byte, 9), 0); !errors.Is(err, ErrValueTooBig) || cacher.Has("bytes") {
+		t.Errorf("Add []byte over the limit = %v, stored %v", err, cacher.Has("bytes"))
+	}
+	if err := cacher.Replace("ok", strings.Repeat("x", 9), 0); !errors.Is(err, ErrValueTooBig) {
+		t.Errorf("Replace over the limit = %v, want ErrValueTooBig", err)
+	}
+	if err := cacher.Set("slice", make([]int, 100), 0); err != nil {
+		t.Errorf("Set []int without WithGobValueSize = %v, want nil", err)
+	}
+
+	gobSized, _ := NewCache(0, 0, WithMaxValueBytes(8), WithGobValueSize())
+	if err := gobSized.Set("slice", make([]int, 100), 0); !errors.Is(err, ErrValueTooBig) {
+		t.Errorf("Set []int with WithGobValueSize = %v, want ErrValueTooBig", err)
+	}
+	unlimited, _ := NewCache(0, 0, WithMaxValueBytes(0))
+	if err := unlimited.Set("big", make([]byte, 1<<20), 0); err != nil {
+		t.Errorf("Set without a limit = %v, want nil", err)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 CountLive 与 CountExpired 区分未过期与已过期未清理的数据项，墓碑数据项不计入
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：前移 clockWall 模拟时间流逝
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestCountLiveExpired(t *testing.T) {
+	saved := clockWall
+	defer func() { clockWall = saved }()
+
+	cacher, _ := NewCache(0, 0, WithLoader(func(key string) (interface{}, error) {
+		return nil, ErrKeyNotFound
+	}), WithNegativeTTL(time.Hour))
+	cacher.Set("a", 1, time.Second)
+	cacher.Set("b", 2, time.Second)
+	cacher.Set("c", 3, time.Hour)
+	cacher.Get("missing") // 存入墓碑数据项
+	clockWall += int64(2 * time.Second)
+
+	if live, expired := cacher.CountLive(), cacher.CountExpired(); live != 1 || expired != 2 {
+		t.Errorf("CountLive, CountExpired = %d, %d, want 1, 2", live, expired)
+	}
+	cacher.DeleteExpired()
+	if live, expired := cacher.CountLive(), cacher.CountExpired(); live != 1 || expired != 0 {
+		t.Errorf("after DeleteExpired: CountLive, CountExpired = %d, %d, want 1, 0", live, expired)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 Touch 与 GetAndTouch 刷新数据项的生命周期
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：已过期与不存在的数据项返回 false 且不被复活；前移 clockWall 模拟时间流逝
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestGetAndTouch(t *testing.T) {
+	saved := clockWall
+	defer func() { clockWall = saved }()
+
+	cacher, _ := NewCache(0, 0, WithLoader(func(key string) (interface{}, error) {
+		t.Errorf("GetAndTouch called the loader for %s", key)
+		return nil, ErrKeyNotFound
+	}))
+	cacher.Set("a", 1, time.Minute)
+	cacher.Set("b", 2, time.Second)
+	clockWall += int64(30 * time.Second)
+
+	value, found := cacher.GetAndTouch("a", time.Hour)
+	if !found || value != 1 {
+		t.Fatalf("GetAndTouch(a) = %v, %v, want 1", value, found)
+	}
+	if ttl := cacher.Expirations()["a"]; ttl <= 59*time.Minute {
+		t.Errorf("a ttl = %v, want about 1h", ttl)
+	}
+	if _, found = cacher.GetAndTouch("b", time.Hour); found {
+		t.Error("GetAndTouch revived expired b")
+	}
+	if _, found = cacher.GetAndTouch("missing", time.Hour); found {
+		t.Error("GetAndTouch(missing) found")
+	}
+	if !cacher.Touch("a", time.Second) {
+		t.Error("Touch(a) = false")
+	}
+	clockWall += int64(2 * time.Second)
+	if cacher.Has("a") {
+		t.Error("a still live after Touch shortened its ttl")
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 SetDefaultExpiration 只影响之后以 DefaultExpiration 写入的数据项
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无效值按 NewCache 的规则改为 0.5h
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestSetDefaultExpiration(t *testing.T) {
+	cacher, _ := NewCache(time.Hour, 0)
+	cacher.Set("old", 1, DefaultExpiration)
+	cacher.SetDefaultExpiration(time.Minute)
+	cacher.Set("new", 2, DefaultExpiration)
+	cacher.Set("explicit", 3, 10*time.Minute)
+
+	ttls := cacher.Expirations()
+	if ttls["old"] <= 59*time.Minute {
+		t.Errorf("old ttl = %v, want about 1h", ttls["old"])
+	}
+	if ttls["new"] <= 59*time.Second || ttls["new"] > time.Minute {
+		t.Errorf("new ttl = %v, want about 1m", ttls["new"])
+	}
+	if ttls["explicit"] <= 9*time.Minute || ttls["explicit"] > 10*time.Minute {
+		t.Errorf("explicit ttl = %v, want about 10m", ttls["explicit"])
+	}
+
+	cacher.SetDefaultExpiration(-5 * time.Second)
+	cacher.Set("invalid", 4, DefaultExpiration)
+	if ttl := cacher.Expirations()["invalid"]; ttl <= 29*time.Minute || ttl > 30*time.Minute {
+		t.Errorf("ttl after an invalid default = %v, want about 30m", ttl)
+	}
+	cacher.SetDefaultExpiration(NoExpiration)
+	cacher.Set("pinned", 5, DefaultExpiration)
+	if ttl := cacher.Expirations()["pinned"]; ttl != NoExpiration {
+		t.Errorf("pinned ttl = %v, want NoExpiration", ttl)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 ExpiringWithin 只列出将在时间窗口内过期的数据项
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：不包含永不过期、已过期与窗口外的数据项；前移 clockWall 模拟时间流逝
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestExpiringWithin(t *testing.T) {
+	saved := clockWall
+	defer func() { clockWall = saved }()
+
+	cacher, _ := NewCache(0, 0)
+	cacher.Set("soon", 1, 30*time.Second)
+	cacher.Set("edge", 2, time.Minute)
+	cacher.Set("later", 3, time.Hour)
+	cacher.Set("never", 4, NoExpiration)
+	cacher.Set("expired", 5, time.Second)
+	clockWall += int64(2 * time.Second)
+
+	keys := cacher.ExpiringWithin(time.Minute)
+	sort.Strings(keys)
+	if fmt.Sprint(keys) != "[edge soon]" {
+		t.Errorf("ExpiringWithin(1m) = %v, want [edge soon]", keys)
+	}
+	if keys = cacher.ExpiringWithin(0); len(keys) != 0 {
+		t.Errorf("ExpiringWithin(0) = %v, want none", keys)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试首次清理延迟在 (0, interval] 内随机分布，WithoutGCJitter 时固定为 interval
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：直接调用 firstGcDelay，不启动 gcLoop
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestFirstGcDelay(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	seen := map[time.Duration]bool{}
+	for i := 0; i < 100; i++ {
+		delay := cacher.firstGcDelay(time.Minute)
+		if delay <= 0 || delay > time.Minute {
+			t.Fatalf("firstGcDelay = %v, want within (0, 1m]", delay)
+		}
+		seen[delay] = true
+	}
+	if len(seen) < 50 {
+		t.Errorf("%d distinct delays in 100 samples, want jittered delays", len(seen))
+	}
+	if delay := cacher.firstGcDelay(0); delay != 0 {
+		t.Errorf("firstGcDelay(0) = %v, want 0", delay)
+	}
+
+	fixed, _ := NewCache(0, 0, WithoutGCJitter())
+	if delay := fixed.firstGcDelay(time.Minute); delay != time.Minute {
+		t.Errorf("firstGcDelay without jitter = %v, want 1m", delay)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 WithLazyGC：首次写入前不启动 gcLoop，首次写入后启动；写入前 StopGc 则之后的写入也不再启动
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：读取 gcRunning 判断 gcLoop 是否运行
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestLazyGC(t *testing.T) {
+	running := func(cacher *Cache) bool {
+		cacher.gcMux.Lock()
+		defer cacher.gcMux.Unlock()
+		return cacher.gcRunning
+	}
+
+	cacher, _ := NewCache(0, time.Hour, WithLazyGC())
+	defer cacher.StopGc()
+	if running(cacher) {
+		t.Fatal("gcLoop started before the first write")
+	}
+	cacher.Get("a")
+	if running(cacher) {
+		t.Fatal("gcLoop started by a read")
+	}
+	cacher.Set("a", 1, 0)
+	if !running(cacher) {
+		t.Fatal("gcLoop not started by the first write")
+	}
+
+	stopped, _ := NewCache(0, time.Hour, WithLazyGC())
+	stopped.StopGc()
+	stopped.Set("a", 1, 0)
+	if running(stopped) {
+		t.Error("gcLoop started by a write after StopGc")
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 WithDeleteOnExpiredRead：读取到已过期的数据项时删除并以 Expired 调用 onEvicted，未启用时只对读取不可见
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：前移 clockWall 使数据项过期
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestDeleteOnExpiredRead(t *testing.T) {
+	saved := clockWall
+	defer func() { clockWall = saved }()
+
+	for _, enabled := range []bool{true, false} {
+		cacher, _ := NewCache(0, 0, WithDeleteOnExpiredRead(enabled))
+		var reasons []Reason
+		cacher.OnEvictedReason(func(key string, value interface{}, reason Reason) {
+			reasons = append(reasons, reason)
+		})
+		cacher.Set("a", 1, time.Minute)
+		cacher.Set("b", 2, NoExpiration)
+		clockWall += int64(2 * time.Minute)
+		if _, found, _ := cacher.Get("a"); found {
+			t.Fatalf("enabled %v: Get of an expired item found it", enabled)
+		}
+		want, wantReasons := 2, []Reason{}
+		if enabled {
+			want, wantReasons = 1, []Reason{Expired}
+		}
+		if got := cacher.Count(); got != want {
+			t.Errorf("enabled %v: Count after reading an expired item = %d, want %d", enabled, got, want)
+		}
+		if fmt.Sprint(reasons) != fmt.Sprint(wantReasons) {
+			t.Errorf("enabled %v: evicted reasons = %v, want %v", enabled, reasons, wantReasons)
+		}
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 SampleKeys：返回最多 n 个互不相同的未过期键名，n 不小于未过期数量时返回全部
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：前移 clockWall 使数据项过期
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestSampleKeys(t *testing.T) {
+	saved := clockWall
+	defer func() { clockWall = saved }()
+
+	cacher, _ := NewCache(0, 0)
+	for i := 0; i < 10; i++ {
+		cacher.Set(fmt.Sprintf("k%d", i), i, NoExpiration)
+	}
+	cacher.Set("gone", 1, time.Minute)
+	clockWall += int64(2 * time.Minute)
+
+	if keys := cacher.SampleKeys(0); len(keys) != 0 {
+		t.Fatalf("SampleKeys(0) = %v, want none", keys)
+	}
+	keys := cacher.SampleKeys(3)
+	seen := map[string]bool{}
+	for _, key := range keys {
+		if !strings.HasPrefix(key, "k") || seen[key] {
+			t.Fatalf("SampleKeys(3) = %v, want distinct unexpired keys", keys)
+		}
+		seen[key] = true
+	}
+	if len(keys) != 3 {
+		t.Fatalf("SampleKeys(3) returned %d keys, want 3", len(keys))
+	}
+	keys = cacher.SampleKeys(20)
+	sort.Strings(keys)
+	if len(keys) != 10 || keys[0] != "k0" || keys[9] != "k9" {
+		t.Errorf("SampleKeys(20) = %v, want the 10 unexpired keys", keys)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：比较设置与未设置初始容量时批量写入的分配
+ * 输入参数：b *testing.B
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func BenchmarkInitialCapacity(b *testing.B) {
+	keys := make([]string, 10000)
+	for i := range keys {
+		keys[i] = "key" + strconv.Itoa(i)
+	}
+	for _, hint := range []int{0, len(keys)} {
+		b.Run("hint="+strconv.Itoa(hint), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				cacher, _ := NewCache(0, 0, WithInitialCapacity(hint))
+				for _, key := range keys {
+					cacher.Set(key, i, 0)
+				}
+			}
+		})
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 Expirations 返回未过期数据项的剩余生命周期
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestExpirations(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	cacher.Set("hour", 1, time.Hour)
+	cacher.Set("forever", 2, NoExpiration)
+	cacher.Set("expired", 3, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	ttls := cacher.Expirations()
+	if len(ttls) != 2 {
+		t.Fatalf("Expirations = %v, want 2 entries", ttls)
+	}
+	if ttl := ttls["hour"]; ttl <= 59*time.Minute || ttl > time.Hour {
+		t.Errorf("hour ttl = %v, want about 1h", ttl)
+	}
+	if ttl := ttls["forever"]; ttl != NoExpiration {
+		t.Errorf("forever ttl = %v, want NoExpiration", ttl)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 FlushKeep 删除所有数据项后缓存仍可继续写入
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestFlushKeep(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	cacher.Set("a", 1, 0)
+	cacher.Set("b", 2, 0)
+	if got := cacher.FlushKeep(); got != 2 {
+		t.Errorf("FlushKeep = %d, want 2", got)
+	}
+	if got := cacher.Count(); got != 0 {
+		t.Errorf("Count after FlushKeep = %d, want 0", got)
+	}
+	cacher.Set("a", 1, 0)
+	if got := cacher.Count(); got != 1 {
+		t.Errorf("Count after FlushKeep and Set = %d, want 1", got)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：比较 Flush 与 FlushKeep 之后重新写入的分配
+ * 输入参数：b *testing.B
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func BenchmarkFlushRefill(b *testing.B) {
+	keys := make([]string, 10000)
+	for i := range keys {
+		keys[i] = "key" + strconv.Itoa(i)
+	}
+	flushes := map[string]func(*Cache) int{"Flush": (*Cache).Flush, "FlushKeep": (*Cache).FlushKeep}
+	for name, flush := range flushes {
+		b.Run(name, func(b *testing.B) {
+			cacher, _ := NewCache(0, 0)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				for _, key := range keys {
+					cacher.Set(key, i, 0)
+				}
+				flush(cacher)
+			}
+		})
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 Add 覆盖已过期的值时以 Expired 调用 onEvicted
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestAddOverExpired(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	var reasons []Reason
+	cacher.OnEvictedReason(func(key string, value interface{}, reason Reason) {
+		reasons = append(reasons, reason)
+	})
+	cacher.Set("a", 1, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if err := cacher.Add("a", 2, 0); err != nil {
+		t.Fatalf("Add over expired: %v", err)
+	}
+	if len(reasons) != 1 || reasons[0] != Expired {
+		t.Errorf("reasons = %v, want [Expired]", reasons)
+	}
+	if value, _, _ := cacher.Get("a"); value != 2 {
+		t.Errorf("a = %v, want 2", value)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 DeletePrefix 只删除指定前缀的数据项并调用 onEvicted
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestDeletePrefix(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	var deleted []string
+	cacher.OnEvicted(func(key string, value interface{}) {
+		deleted = append(deleted, key)
+	})
+	cacher.Set("user:1", 1, 0)
+	cacher.Set("user:2", 2, 0)
+	cacher.Set("order:1", 3, 0)
+
+	if got := cacher.DeletePrefix("user:"); got != 2 {
+		t.Errorf("DeletePrefix = %d, want 2", got)
+	}
+	sort.Strings(deleted)
+	if len(deleted) != 2 || deleted[0] != "user:1" || deleted[1] != "user:2" {
+		t.Errorf("evicted = %v, want [user:1 user:2]", deleted)
+	}
+	if _, found, _ := cacher.Get("order:1"); !found || cacher.Count() != 1 {
+		t.Errorf("Count = %d, want only order:1 left", cacher.Count())
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 GetRandom 只返回未过期的数据项，缓存为空时返回 false
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestGetRandom(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	if _, _, ok := cacher.GetRandom(); ok {
+		t.Error("GetRandom on an empty cache returned ok")
+	}
+	cacher.Set("expired", 0, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if _, _, ok := cacher.GetRandom(); ok {
+		t.Error("GetRandom with only an expired item returned ok")
+	}
+	cacher.Set("a", 1, 0)
+	if key, value, ok := cacher.GetRandom(); !ok || key != "a" || value != 1 {
+		t.Errorf("GetRandom = %q, %v, %v, want a, 1", key, value, ok)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 WithAccessObserver 报告 Get 的命中与未命中
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestAccessObserver(t *testing.T) {
+	var mux sync.Mutex
+	hits := map[string]bool{}
+	cacher, _ := NewCache(0, 0, WithAccessObserver(func(key string, hit bool) {
+		mux.Lock()
+		hits[key] = hit
+		mux.Unlock()
+	}))
+	cacher.Set("a", 1, 0)
+	cacher.Get("a")
+	cacher.Get("b")
+	mux.Lock()
+	defer mux.Unlock()
+	if hit, seen := hits["a"]; !seen || !hit {
+		t.Error("observer did not see the hit on a")
+	}
+	if hit, seen := hits["b"]; !seen || hit {
+		t.Error("observer did not see the miss on b")
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试冻结期间数据项不过期，解冻后按原过期时间过期
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestFreezeExpiration(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	cacher.Set("a", 1, 10*time.Millisecond)
+	cacher.FreezeExpiration(true)
+	time.Sleep(20 * time.Millisecond)
+	if _, found, _ := cacher.Get("a"); !found {
+		t.Error("a expired while frozen")
+	}
+	cacher.FreezeExpiration(false)
+	if _, found, _ := cacher.Get("a"); found {
+		t.Error("a still alive after unfreezing")
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 Age 返回数据项自写入以来的时长
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：Touch 不重新计时，Set 覆盖写入重新计时
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestAge(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	if _, found := cacher.Age("b"); found {
+		t.Error("Age of a missing key found it")
+	}
+	cacher.Set("b", 1, time.Hour)
+	time.Sleep(10 * time.Millisecond)
+	cacher.Touch("b", time.Hour)
+	if age, found := cacher.Age("b"); !found || age < 10*time.Millisecond {
+		t.Errorf("Age after Touch = %v, %v, want >= 10ms", age, found)
+	}
+	cacher.Set("b", 2, time.Hour)
+	if age, _ := cacher.Age("b"); age >= 10*time.Millisecond {
+		t.Errorf("Age after Set = %v, want reset", age)
+	}
+}
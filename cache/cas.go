@@ -0,0 +1,133 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：cas.go
+ * 内容摘要：基于数据项版本号的乐观并发控制(compare-and-swap)。
+ * 其他说明：每次写入数据项都会分配一个新的版本号，版本号在整个缓存内单调递增；
+ *           版本号 0 表示数据项不存在，SetWithCAS(key, value, dur, 0) 只在数据项不存在时写入。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"time"
+)
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：获取数据项及其版本号
+ * 输入参数：数据项键名：key string
+ * 输出参数：无
+ * 返 回 值：具体数据项的值，版本号，以及是否找到(bool)
+ * 其他说明：该函数为 Cache 类方法，不调用 loader；启用 WithCopyOnGet 时返回值的副本，复制失败时返回未找到
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) GetWithVersion(key string) (interface{}, uint64, bool) {
+	key, err := thisCache.checkKey(key)
+	if err != nil {
+		return nil, 0, false
+	}
+	item, found := thisCache.access(key)
+	if !found || item.negative() {
+		return nil, 0, false
+	}
+	value, err := thisCache.readValue(item.Object)
+	if err != nil {
+		return nil, 0, false
+	}
+	return value, item.Version, true
+}
+
+/***************************************************************************************
+ * 功能描述：数据项的当前版本号与 expectedVersion 一致时写入数据项
+ * 输入参数：数据项键名：key string, 数据项键值：value interface{}, 数据项生命周期：dur time.Duration,
+ *           期望的版本号：expectedVersion uint64
+ * 输出参数：无
+ * 返 回 值：写入后的版本号，版本号不一致时返回当前版本号与 ErrVersion
+ * 其他说明：该函数为 Cache 类方法，比较与写入在同一个写锁内完成；
+ *           不存在、已过期的数据项以及墓碑数据项的版本号视为 0
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) SetWithCAS(key string, value interface{}, dur time.Duration, expectedVersion uint64) (uint64, error) {
+	if thisCache.closed.Load() {
+		return 0, ErrCacheClosed
+	}
+	key, err := thisCache.checkKey(key)
+	if err != nil {
+		return 0, err
+	}
+	if err := thisCache.checkValueSize(value); err != nil {
+		return 0, err
+	}
+	if err := thisCache.lockWithTimeout(); err != nil {
+		return 0, err
+	}
+	var current uint64
+	if item, found := thisCache.items[key]; found && !thisCache.expired(item, nanotime()) && !item.negative() {
+		current = item.Version
+	}
+	if current != expectedVersion {
+		thisCache.unlock()
+		return current, ErrVersion
+	}
+	evicted, reason := thisCache.overwrite(key, value, dur)
+	version := thisCache.items[key].Version
+	onEvicted := thisCache.onEvicted
+	thisCache.unlock()
+
+	fireEvicted(onEvicted, evicted, reason)
+	return version, nil
+}
+
+/***************************************************************************************
+ * 功能描述：数据项的当前版本号与 expectedVersion 一致时删除数据项
+ * 输入参数：数据项键名：key string, 期望的版本号：expectedVersion uint64
+ * 输出参数：无
+ * 返 回 值：无 error， 则为 nil，版本号不一致时返回 ErrVersion
+ * 其他说明：该函数为 Cache 类方法，比较与删除在同一个写锁内完成，删除后调用 onEvicted(Deleted)；
+ *           不存在、已过期的数据项以及墓碑数据项的版本号视为 0，expectedVersion 为 0 时不做修改
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) DeleteWithCAS(key string, expectedVersion uint64) error {
+	key, err := thisCache.checkKey(key)
+	if err != nil {
+		return err
+	}
+	if err = thisCache.lockWithTimeout(); err != nil {
+		return err
+	}
+	var current uint64
+	if item, found := thisCache.items[key]; found && !thisCache.expired(item, nanotime()) && !item.negative() {
+		current = item.Version
+	}
+	if current != expectedVersion {
+		thisCache.unlock()
+		return ErrVersion
+	}
+	if current == 0 {
+		thisCache.unlock()
+		return nil
+	}
+	value, evicted, _ := thisCache.delete(key)
+	onEvicted := thisCache.onEvicted
+	thisCache.unlock()
+	if evicted && onEvicted != nil {
+		onEvicted(key, value, Deleted)
+	}
+	return nil
+}
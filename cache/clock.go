@@ -0,0 +1,76 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：clock.go
+ * 内容摘要：计算过期时间使用的单调时钟。
+ * 其他说明：Item.Expiration 仍为 Unix 纳秒时间戳(int64)，以保持导出字段与 Save/Load 格式不变；
+ *           当前时间由进程启动时的墙上时间加上此后经过的单调时间得到，NTP 校时、手动调整系统时间
+ *           等墙上时间跳变不会使已过期的数据项复活，也不会使未过期的数据项提前过期。
+ *           代价是进程运行期间墙上时间的调整不反映到缓存的时间中，跨进程 Save/Load 时
+ *           过期时间按新进程启动时的墙上时间解释。
+ *           gcLoop 的定时器通过 newGcTimer 创建，测试可替换为手动触发的定时器。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"time"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+var clockBase = time.Now() // 时钟基准，带单调时钟读数
+
+var clockWall = clockBase.UnixNano() // 时钟基准的墙上时间，Unix 纳秒
+
+type gcTimer interface { // gcLoop 使用的定时器，*time.Timer 经 stdTimer 包装后满足该接口
+	C() <-chan time.Time        // 到期通知管道
+	Reset(d time.Duration) bool // 重新开始计时
+	Stop() bool                 // 停止计时
+}
+
+type stdTimer struct { // 基于 *time.Timer 的定时器
+	*time.Timer
+}
+
+var newGcTimer = func(d time.Duration) gcTimer { // 创建 gcLoop 的定时器，测试中可替换
+	return stdTimer{time.NewTimer(d)}
+}
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：获取当前时间
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：当前时间的 Unix 纳秒时间戳，不受墙上时间跳变影响
+ * 其他说明：所有过期判断与过期时间计算都使用该函数，代替 time.Now().UnixNano()
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func nanotime() int64 {
+	return clockWall + int64(time.Since(clockBase))
+}
+
+/***************************************************************************************
+ * 功能描述：获取定时器的到期通知管道
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：到期通知管道
+ * 其他说明：该函数为 stdTimer 类方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisTimer stdTimer) C() <-chan time.Time {
+	return thisTimer.Timer.C
+}
@@ -0,0 +1,156 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：clock_test.go
+ * 内容摘要：单调时钟测试。
+ * 其他说明：通过修改 clockWall 模拟进程启动后墙上时间的跳变，测试不得并行执行。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：测试墙上时间跳变不影响数据项的生命周期
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：clockWall 前移 2 小时相当于启动后墙上时间回拨了 2 小时，
+ *           若过期时间按墙上时间计算，1 小时的数据项会立即过期；后移则会晚 2 小时过期
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestExpirationIgnoresWallClockJump(t *testing.T) {
+	saved := clockWall
+	defer func() { clockWall = saved }()
+
+	for _, jump := range []time.Duration{2 * time.Hour, -2 * time.Hour} {
+		clockWall = saved + int64(jump)
+		cacher, err := NewCache(0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cacher.Set("hour", 1, time.Hour)
+		cacher.Set("short", 1, 50*time.Millisecond)
+		if _, found, _ := cacher.Get("hour"); !found {
+			t.Fatalf("jump %v: item with 1h TTL expired immediately", jump)
+		}
+		if ttl := cacher.Expirations()["hour"]; ttl <= 59*time.Minute || ttl > time.Hour {
+			t.Fatalf("jump %v: remaining TTL %v, want about 1h", jump, ttl)
+		}
+		time.Sleep(100 * time.Millisecond)
+		if _, found, _ := cacher.Get("short"); found {
+			t.Fatalf("jump %v: item with 50ms TTL still present after 100ms", jump)
+		}
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 nanotime 单调递增且接近墙上时间
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestNanotime(t *testing.T) {
+	prev := nanotime()
+	for i := 0; i < 1000; i++ {
+		now := nanotime()
+		if now < prev {
+			t.Fatalf("nanotime went backwards: %d < %d", now, prev)
+		}
+		prev = now
+	}
+	if diff := time.Duration(nanotime() - time.Now().UnixNano()); diff > time.Minute || diff < -time.Minute {
+		t.Fatalf("nanotime differs from wall clock by %v", diff)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 gcLoop 按设置的周期计时，SetGCInterval、PauseGC、ResumeGC 即时生效
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：使用手动触发的 fakeGcTimer 代替真实定时器，并前移 clockWall 使数据项过期
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestGcCadence(t *testing.T) {
+	timer := &fakeGcTimer{c: make(chan time.Time), resets: make(chan time.Duration, 4), stops: make(chan struct{}, 4)}
+	savedTimer, savedWall := newGcTimer, clockWall
+	defer func() { newGcTimer, clockWall = savedTimer, savedWall }()
+	newGcTimer = func(d time.Duration) gcTimer {
+		timer.resets <- d
+		return timer
+	}
+
+	cacher, _ := NewCache(0, time.Minute, WithoutGCJitter())
+	defer cacher.StopGc()
+	if d := <-timer.resets; d != time.Minute {
+		t.Fatalf("first delay = %v, want 1m", d)
+	}
+	cacher.Set("a", 1, time.Second)
+	clockWall += int64(2 * time.Second)
+	timer.c <- time.Now()
+	if d := <-timer.resets; d != time.Minute {
+		t.Errorf("interval after gc = %v, want 1m", d)
+	}
+	if n := cacher.Count(); n != 0 {
+		t.Errorf("Count after gc = %d, want 0", n)
+	}
+
+	cacher.SetGCInterval(30 * time.Second)
+	<-timer.stops
+	if d := <-timer.resets; d != 30*time.Second {
+		t.Errorf("interval after SetGCInterval = %v, want 30s", d)
+	}
+
+	cacher.PauseGC()
+	<-timer.stops
+	select {
+	case d := <-timer.resets:
+		t.Errorf("timer reset to %v while paused", d)
+	case <-time.After(20 * time.Millisecond):
+	}
+	cacher.ResumeGC()
+	<-timer.stops
+	if d := <-timer.resets; d != 30*time.Second {
+		t.Errorf("interval after ResumeGC = %v, want 30s", d)
+	}
+}
+
+type fakeGcTimer struct { // 手动触发的 gcLoop 定时器
+	c      chan time.Time     // 测试向该管道发送即触发一次清理
+	resets chan time.Duration // 记录创建与每次 Reset 的周期
+	stops  chan struct{}      // 记录每次 Stop
+}
+
+func (thisTimer *fakeGcTimer) C() <-chan time.Time {
+	return thisTimer.c
+}
+
+func (thisTimer *fakeGcTimer) Reset(d time.Duration) bool {
+	thisTimer.resets <- d
+	return true
+}
+
+func (thisTimer *fakeGcTimer) Stop() bool {
+	thisTimer.stops <- struct{}{}
+	return true
+}
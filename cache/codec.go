@@ -0,0 +1,164 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：codec.go
+ * 内容摘要：Save/Load 使用的可插拔编解码器。
+ * 其他说明：默认使用 GobCodec。gob 要求 interface{} 中的具体类型先通过 gob.Register 注册，
+ *           而 gob 的类型注册表是整个进程共享的全局状态，注册后不可撤销，且同名类型重复注册
+ *           会 panic。GobCodec 对每个具体类型在进程内只注册一次(由 sync.Once 保护)，
+ *           多个缓存并发 Save 也不会重复注册。不希望修改 gob 全局注册表时，
+ *           可以通过 WithCodec 使用 JSONCodec 或自定义的 Codec。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"encoding/gob"
+	"encoding/json"
+	"io"
+	"reflect"
+	"sync"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+type Encoder interface { // 编码器，gob.Encoder 与 json.Encoder 均满足该接口
+	Encode(v interface{}) error
+}
+
+type Decoder interface { // 解码器，gob.Decoder 与 json.Decoder 均满足该接口
+	Decode(v interface{}) error
+}
+
+type Codec interface { // 编解码器
+	NewEncoder(wrt io.Writer) Encoder // 创建写入 wrt 的编码器
+	NewDecoder(rd io.Reader) Decoder  // 创建从 rd 读取的解码器
+}
+
+type GobCodec struct{} // gob 编解码器，编码前自动注册数据项的值类型
+
+type JSONCodec struct{} // JSON 编解码器，不修改任何全局状态，但数据项的值解码后不保留原类型
+
+type gobEncoder struct { // 编码前注册数据项值类型的 gob 编码器
+	encode *gob.Encoder
+}
+
+var gobTypes sync.Map // 已注册到 gob 的具体类型：reflect.Type -> *sync.Once
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：将值的具体类型注册到 gob，每个类型在进程内只注册一次
+ * 输入参数：值：value interface{}
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：gob.Register 修改的是进程全局的类型注册表；类型冲突时会 panic，由调用者 recover
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func registerGobType(value interface{}) {
+	if value == nil {
+		return
+	}
+	once, _ := gobTypes.LoadOrStore(reflect.TypeOf(value), new(sync.Once))
+	once.(*sync.Once).Do(func() {
+		gob.Register(value)
+	})
+}
+
+/***************************************************************************************
+ * 功能描述：创建 gob 编码器
+ * 输入参数：wrt io.Writer
+ * 输出参数：无
+ * 返 回 值：编码器
+ * 其他说明：该函数为 GobCodec 类方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (GobCodec) NewEncoder(wrt io.Writer) Encoder {
+	return gobEncoder{encode: gob.NewEncoder(wrt)}
+}
+
+/***************************************************************************************
+ * 功能描述：创建 gob 解码器
+ * 输入参数：rd io.Reader
+ * 输出参数：无
+ * 返 回 值：解码器
+ * 其他说明：该函数为 GobCodec 类方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (GobCodec) NewDecoder(rd io.Reader) Decoder {
+	return gob.NewDecoder(rd)
+}
+
+/***************************************************************************************
+ * 功能描述：注册数据项的值类型后进行 gob 编码
+ * 输入参数：要编码的值：v interface{}
+ * 输出参数：无
+ * 返 回 值：无 error， 则为 nil
+ * 其他说明：该函数为 gobEncoder 类方法，支持 Item、*Item、map[string]Item、*map[string]Item
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisEncoder gobEncoder) Encode(v interface{}) error {
+	switch items := v.(type) {
+	case Item:
+		registerGobType(items.Object)
+	case *Item:
+		registerGobType(items.Object)
+	case map[string]Item:
+		for _, val := range items {
+			registerGobType(val.Object)
+		}
+	case *map[string]Item:
+		for _, val := range *items {
+			registerGobType(val.Object)
+		}
+	}
+	return thisEncoder.encode.Encode(v)
+}
+
+/***************************************************************************************
+ * 功能描述：创建 JSON 编码器
+ * 输入参数：wrt io.Writer
+ * 输出参数：无
+ * 返 回 值：编码器
+ * 其他说明：该函数为 JSONCodec 类方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (JSONCodec) NewEncoder(wrt io.Writer) Encoder {
+	return json.NewEncoder(wrt)
+}
+
+/***************************************************************************************
+ * 功能描述：创建 JSON 解码器
+ * 输入参数：rd io.Reader
+ * 输出参数：无
+ * 返 回 值：解码器
+ * 其他说明：该函数为 JSONCodec 类方法，数值解码为 float64，结构体解码为 map[string]interface{}
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (JSONCodec) NewDecoder(rd io.Reader) Decoder {
+	return json.NewDecoder(rd)
+}
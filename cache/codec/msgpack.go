@@ -0,0 +1,65 @@
+package codec
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：msgpack.go
+ * 内容摘要：MessagePack 编解码器，用于 cache.WithCodec，使 Save/Load 的快照可被其它语言读取。
+ * 其他说明：MessagePack 依赖只存在于本子包，cache 包本身不依赖 MessagePack。
+ *           快照中每个数据项单独编码，外层为长度前缀的记录，格式见 cache/snapshot.go。
+ *           与 JSONCodec 相同，数据项的值以 interface{} 解码，不保留原类型：
+ *           整数解码为最小能容纳的整数类型(如 int8、uint16)，结构体解码为 map[string]interface{}，
+ *           需要原类型时应在读取后自行转换，或使用 GobCodec。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"go-libcache/cache"
+	"io"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+type MsgpackCodec struct{} // MessagePack 编解码器，编码单个 cache.Item，包括过期时间
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：创建 MessagePack 编码器
+ * 输入参数：wrt io.Writer
+ * 输出参数：无
+ * 返 回 值：编码器
+ * 其他说明：该函数为 MsgpackCodec 类方法，结构体按数组编码，不写入字段名，
+ *           cache.Item 编码为 [Object, Expiration, LastAccess, Version]
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (MsgpackCodec) NewEncoder(wrt io.Writer) cache.Encoder {
+	encode := msgpack.NewEncoder(wrt)
+	encode.UseArrayEncodedStructs(true)
+	return encode
+}
+
+/***************************************************************************************
+ * 功能描述：创建 MessagePack 解码器
+ * 输入参数：rd io.Reader
+ * 输出参数：无
+ * 返 回 值：解码器
+ * 其他说明：该函数为 MsgpackCodec 类方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (MsgpackCodec) NewDecoder(rd io.Reader) cache.Decoder {
+	return msgpack.NewDecoder(rd)
+}
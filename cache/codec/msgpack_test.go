@@ -0,0 +1,98 @@
+package codec
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：msgpack_test.go
+ * 内容摘要：MessagePack 编解码器测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"bytes"
+	"go-libcache/cache"
+	"strconv"
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：测试 MsgpackCodec 的 Save/Load 往返保留键名、值与过期时间
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：整数以最小整数类型解码，测试只比较数值
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestMsgpackRoundTrip(t *testing.T) {
+	cacher, _ := cache.NewCache(0, 0, cache.WithCodec(MsgpackCodec{}))
+	cacher.Set("str", "hello", 0)
+	cacher.Set("num", 300, time.Hour)
+	cacher.Set("list", []interface{}{"a", "b"}, 0)
+
+	var buf bytes.Buffer
+	if err := cacher.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, _ := cache.NewCache(0, 0, cache.WithCodec(MsgpackCodec{}))
+	if err := loaded.Load(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.Count(); got != 3 {
+		t.Errorf("Count = %d, want 3", got)
+	}
+	if value, found, _ := loaded.Get("str"); !found || value != "hello" {
+		t.Errorf("str = %v, %v, want hello", value, found)
+	}
+	if value, found, _ := loaded.Get("num"); !found || value != uint16(300) {
+		t.Errorf("num = %v (%T), %v, want 300", value, value, found)
+	}
+	if value, _, _ := loaded.Get("list"); len(value.([]interface{})) != 2 {
+		t.Errorf("list = %v, want [a b]", value)
+	}
+	ttl := loaded.Expirations()
+	if ttl["num"] <= 59*time.Minute || ttl["num"] > time.Hour {
+		t.Errorf("num ttl = %v, want about 1h", ttl["num"])
+	}
+	if ttl["str"] != cache.NoExpiration {
+		t.Errorf("str ttl = %v, want NoExpiration", ttl["str"])
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试相同数据的 MessagePack 快照小于 gob 快照
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：快照逐项编码，gob 每条记录都带有类型描述
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestMsgpackSmallerThanGob(t *testing.T) {
+	sizes := map[string]int{}
+	for name, codec := range map[string]cache.Codec{"gob": cache.GobCodec{}, "msgpack": MsgpackCodec{}} {
+		cacher, _ := cache.NewCache(0, 0, cache.WithCodec(codec))
+		for i := 0; i < 100; i++ {
+			cacher.Set("key"+strconv.Itoa(i), "value"+strconv.Itoa(i), time.Hour)
+		}
+		var buf bytes.Buffer
+		if err := cacher.Save(&buf); err != nil {
+			t.Fatal(name, err)
+		}
+		sizes[name] = buf.Len()
+	}
+	t.Logf("snapshot size: gob %d bytes, msgpack %d bytes", sizes["gob"], sizes["msgpack"])
+	if sizes["msgpack"] >= sizes["gob"] {
+		t.Errorf("msgpack %d bytes, want smaller than gob %d bytes", sizes["msgpack"], sizes["gob"])
+	}
+}
@@ -0,0 +1,99 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：codec_test.go
+ * 内容摘要：编解码器测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"bytes"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+/***************************************************************************************
+ * 功能描述：测试多个缓存在写入进行时并发 Save
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：每个写入者写入同一自定义类型，gob 类型注册只发生一次，需配合 -race 运行
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestConcurrentSave(t *testing.T) {
+	type point struct{ X, Y int }
+	caches := make([]*Cache, 4)
+	for i := range caches {
+		caches[i], _ = NewCache(0, 0)
+	}
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for _, cacher := range caches {
+		wg.Add(1)
+		go func(cacher *Cache) {
+			defer wg.Done()
+			for i := 0; ; i++ {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				cacher.Set("k"+strconv.Itoa(i%64), point{i, -i}, 0)
+			}
+		}(cacher)
+	}
+	errs := make(chan error, len(caches)*10)
+	var savers sync.WaitGroup
+	for _, cacher := range caches {
+		savers.Add(1)
+		go func(cacher *Cache) {
+			defer savers.Done()
+			for i := 0; i < 10; i++ {
+				var buf bytes.Buffer
+				if err := cacher.Save(&buf); err != nil {
+					errs <- err
+					continue
+				}
+				loaded, _ := NewCache(0, 0)
+				if err := loaded.Load(&buf); err != nil {
+					errs <- err
+				}
+			}
+		}(cacher)
+	}
+	savers.Wait()
+	close(stop)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	var buf bytes.Buffer
+	if err := caches[0].Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, _ := NewCache(0, 0)
+	if err := loaded.Load(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := loaded.Count(), caches[0].Count(); got != want {
+		t.Errorf("Count = %d, want %d", got, want)
+	}
+	if value, found, _ := loaded.Get("k0"); !found {
+		t.Error("k0 not found after Load")
+	} else if _, ok := value.(point); !ok {
+		t.Errorf("k0 = %T, want point", value)
+	}
+}
@@ -0,0 +1,96 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：compact.go
+ * 内容摘要：重建 items，回收缓存在数据量高峰之后仍占用的 map 内存。
+ * 其他说明：Go 的 map 删除数据项后不会收缩已分配的 bucket，只能重建一个新的 map。
+ *           缓存记录自上次重建以来数据项数量的峰值，近似表示 map 已分配的大小。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+
+/***************************************************************************************/
+// 数据结构与常量
+
+const compactMinPeak = 1024 // 峰值低于该数量时 gcLoop 不自动重建，小 map 重建收益不大
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：将 items 重建为只包含未过期数据项的新 map
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：int 重建后的数据项数量
+ * 其他说明：该函数为 Cache 类方法，在写锁内完成，保留数据项的过期时间等全部字段；
+ *           已过期的数据项被丢弃，释放锁后对其调用 onEvicted(Expired)
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) Compact() int {
+	var evictedItems []keyAndValue
+	var hooks []expiredHook
+	now := nanotime()
+	if thisCache.lockWithTimeout() != nil {
+		return 0
+	}
+	onEvicted := thisCache.onEvicted
+	items := make(map[string]Item, len(thisCache.items))
+	for key, val := range thisCache.items {
+		if !thisCache.expired(val, now) {
+			items[key] = val
+			continue
+		}
+		hook := thisCache.takeExpireHook(key)
+		if val.negative() {
+			continue
+		}
+		if onEvicted != nil {
+			evictedItems = append(evictedItems, keyAndValue{key, val.Object})
+		}
+		if hook != nil {
+			hooks = append(hooks, expiredHook{key, val.Object, hook})
+		}
+	}
+	thisCache.items = items
+	thisCache.count.Store(int64(len(items)))
+	thisCache.peak = int64(len(items))
+	thisCache.reindex()
+	thisCache.recountQuotas()
+	thisCache.markDirty()
+	thisCache.unlock()
+
+	thisCache.logger.Debugf("cache compact: %d items kept", len(items))
+	fireEvicted(onEvicted, evictedItems, Expired)
+	thisCache.fireExpireHooks(hooks)
+	return len(items)
+}
+
+/***************************************************************************************
+ * 功能描述：数据项数量低于峰值的 compactRatio 时重建 items
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，由 gcLoop 在每次清理后调用，未启用 WithAutoCompact 时不做任何事
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) maybeCompact() {
+	if thisCache.compactRatio <= 0 {
+		return
+	}
+	thisCache.mux.RLock()
+	peak := thisCache.peak
+	thisCache.mux.RUnlock()
+	if peak >= compactMinPeak && float64(thisCache.count.Load()) < thisCache.compactRatio*float64(peak) {
+		thisCache.Compact()
+	}
+}
@@ -0,0 +1,97 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：compact_test.go
+ * 内容摘要：重建 items 测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：测试填满、删空后 Compact 只保留剩余的未过期数据项及其过期时间
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：已过期的数据项被丢弃并调用 onEvicted
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestCompact(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	var evicted []string
+	cacher.OnEvicted(func(key string, value interface{}) {
+		evicted = append(evicted, key)
+	})
+	for i := 0; i < 2000; i++ {
+		cacher.Set("k"+strconv.Itoa(i), i, 0)
+	}
+	for i := 0; i < 1990; i++ {
+		cacher.Delete("k" + strconv.Itoa(i))
+	}
+	evicted = nil
+	cacher.Set("ttl", "x", time.Hour)
+	cacher.Set("gone", "y", time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	if kept := cacher.Compact(); kept != 11 {
+		t.Errorf("Compact kept %d items, want 11", kept)
+	}
+	if len(cacher.items) != 11 || cacher.Count() != 11 {
+		t.Errorf("items has %d entries, Count %d, want 11", len(cacher.items), cacher.Count())
+	}
+	if _, found := cacher.items["gone"]; found || len(evicted) != 1 || evicted[0] != "gone" {
+		t.Errorf("expired item kept or not reported: evicted %v", evicted)
+	}
+	if ttl := cacher.Expirations()["ttl"]; ttl <= 59*time.Minute {
+		t.Errorf("ttl after Compact = %v, want about 1h", ttl)
+	}
+	if value, found, _ := cacher.Get("k1995"); !found || value != 1995 {
+		t.Errorf("k1995 = %v, %v, want 1995", value, found)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 WithAutoCompact 在数量低于峰值的比例后由清理触发重建
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：直接调用 maybeCompact 代替 gcLoop
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestAutoCompact(t *testing.T) {
+	cacher, _ := NewCache(0, 0, WithAutoCompact(0.5))
+	for i := 0; i < 2000; i++ {
+		cacher.Set("k"+strconv.Itoa(i), i, 0)
+	}
+	for i := 0; i < 900; i++ {
+		cacher.Delete("k" + strconv.Itoa(i))
+	}
+	cacher.maybeCompact()
+	if cacher.peak != 2000 {
+		t.Fatalf("compacted at %d of 2000 items, want no compaction above the ratio", cacher.Count())
+	}
+	for i := 900; i < 1500; i++ {
+		cacher.Delete("k" + strconv.Itoa(i))
+	}
+	cacher.maybeCompact()
+	if cacher.peak != 500 {
+		t.Errorf("peak after auto compaction = %d, want 500", cacher.peak)
+	}
+}
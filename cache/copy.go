@@ -0,0 +1,78 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：copy.go
+ * 内容摘要：读取时复制数据项的值，避免调用者修改返回的 slice/map/指针 而破坏缓存中的值。
+ * 其他说明：默认通过 gob 编解码深复制，每次读取都会分配内存并编解码，开销远大于直接返回，
+ *           因此只在通过 WithCopyOnGet 启用时生效。gob 不复制未导出字段，且空 slice/map
+ *           复制后为 nil，对此敏感的类型应提供自定义的复制函数。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"bytes"
+	"encoding/gob"
+	"reflect"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+type CopyFunc func(value interface{}) (interface{}, error) // 复制数据项的值的函数
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：通过 gob 编解码深复制一个值
+ * 输入参数：值：value interface{}
+ * 输出参数：无
+ * 返 回 值：复制后的值，无 error 则为 nil
+ * 其他说明：按值的具体类型编解码，不需要 gob.Register；nil 直接返回
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func GobCopy(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(value); err != nil {
+		return nil, err
+	}
+	dst := reflect.New(reflect.TypeOf(value))
+	if err := gob.NewDecoder(&buf).DecodeValue(dst); err != nil {
+		return nil, err
+	}
+	return dst.Elem().Interface(), nil
+}
+
+/***************************************************************************************
+ * 功能描述：按配置复制数据项的值
+ * 输入参数：值：value interface{}
+ * 输出参数：无
+ * 返 回 值：复制后的值，未启用 WithCopyOnGet 时返回原值
+ * 其他说明：该函数为 Cache 类方法，不持有锁时调用
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) copyValue(value interface{}) (interface{}, error) {
+	if thisCache.copier == nil {
+		return value, nil
+	}
+	copied, err := thisCache.copier(value)
+	if err != nil {
+		thisCache.logger.Errorf("cache copy %T: %v", value, err)
+		return nil, err
+	}
+	return copied, nil
+}
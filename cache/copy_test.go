@@ -0,0 +1,63 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：copy_test.go
+ * 内容摘要：读取时复制测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"errors"
+	"testing"
+)
+
+/***************************************************************************************
+ * 功能描述：测试 WithCopyOnGet 使调用者修改返回的切片与 map 不影响缓存中的值
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：未启用时返回的是同一个切片；复制失败时 Get 返回错误
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestCopyOnGet(t *testing.T) {
+	plain, _ := NewCache(0, 0)
+	plain.Set("s", []int{1, 2, 3}, 0)
+	value, _, _ := plain.Get("s")
+	value.([]int)[0] = 100
+	if value, _, _ = plain.Get("s"); value.([]int)[0] != 100 {
+		t.Fatal("without WithCopyOnGet Get should return the stored slice")
+	}
+
+	cacher, _ := NewCache(0, 0, WithCopyOnGet(nil))
+	cacher.Set("s", []int{1, 2, 3}, 0)
+	cacher.Set("m", map[string]int{"a": 1}, 0)
+	value, _, _ = cacher.Get("s")
+	value.([]int)[0] = 100
+	value, _, _ = cacher.Get("m")
+	value.(map[string]int)["a"] = 100
+	if value, _, _ = cacher.Get("s"); value.([]int)[0] != 1 {
+		t.Errorf("stored slice changed to %v", value)
+	}
+	if value, _ = cacher.GetAndTouch("m", 0); value.(map[string]int)["a"] != 1 {
+		t.Errorf("stored map changed to %v", value)
+	}
+
+	boom := errors.New("boom")
+	failing, _ := NewCache(0, 0, WithCopyOnGet(func(value interface{}) (interface{}, error) {
+		return nil, boom
+	}))
+	failing.Set("s", []int{1}, 0)
+	if _, _, err := failing.Get("s"); !errors.Is(err, boom) {
+		t.Errorf("Get with a failing copier = %v, want boom", err)
+	}
+}
@@ -0,0 +1,214 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：counter.go
+ * 内容摘要：int64 计数器句柄，用于高频自增。
+ * 其他说明：句柄新建的数据项的值为 *CounterValue，已有整数值时保留原类型；每次自增都在写锁内完成，
+ *           与 Increment 一样分配新的版本号、写入增量持久化日志并通知订阅者，因此计数可以从日志恢复，
+ *           也可用于 CAS。自增时写入新的值而不修改原值，已取得的快照不会随之变化。
+ *           与 Increment 相比，句柄缓存规范化后的键名，并省去不必要的时钟读取与数据项复制。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"encoding/binary"
+	"errors"
+	"strconv"
+	"sync/atomic"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+type CounterValue struct { // 计数器数据项的值，Get 返回 *CounterValue；写入后不再修改，自增时替换为新的值
+	n atomic.Int64 // 当前计数
+}
+
+type Counter struct { // 计数器句柄，可在多个 goroutine 间共享
+	cacher *Cache // 计数器所在的缓存
+	key    string // 计数器数据项的键名
+}
+
+var errCounterValue = errors.New("counter value invalid.") // 计数器序列化数据长度不正确
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：获取 key 对应的计数器句柄
+ * 输入参数：数据项键名：key string
+ * 输出参数：无
+ * 返 回 值：计数器句柄
+ * 其他说明：该函数为 Cache 类方法，只创建句柄，首次 Add 时才查找或创建数据项；
+ *           数据项不存在或已过期时以 *CounterValue(0) 创建，使用默认过期时间；已有整数值时保留原类型与过期时间；
+ *           句柄只缓存键名，数据项被删除或过期后，下一次 Add 重新创建
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) Counter(key string) *Counter {
+	key, _ = thisCache.checkKey(key) // 不合法的键名为空，Add 与 Value 返回 0
+	return &Counter{cacher: thisCache, key: key}
+}
+
+/***************************************************************************************
+ * 功能描述：计数器自增 n
+ * 输入参数：增量：n int64
+ * 输出参数：无
+ * 返 回 值：自增后的值；键名不合法、缓存已关闭、获取写锁超时或数据项的值不是整数时返回 0
+ * 其他说明：该函数为 Counter 类方法，在写锁内完成查找、创建与自增，保留数据项原有的过期时间与值类型；
+ *           值为 *CounterValue 时写入新的计数器而不修改原值，已取得的快照与 Get 结果不受影响；
+ *           每次自增分配新的版本号，写入增量持久化日志并发送 EventSet 事件；
+ *           与 Increment 相比省去了键名校验，数据项没有过期时间且未启用空闲过期时不读取时钟，
+ *           未启用值序列化时不复制数据项
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCounter *Counter) Add(n int64) int64 {
+	thisCache := thisCounter.cacher
+	if len(thisCounter.key) == 0 || thisCache.closed.Load() {
+		return 0
+	}
+	if thisCache.lockWithTimeout() != nil {
+		return 0
+	}
+	defer thisCache.unlock()
+
+	item, found := thisCache.items[thisCounter.key]
+	if found && item.negative() {
+		found = false
+	}
+	if found && (item.Expiration > 0 || thisCache.idleTTL > 0) && thisCache.expired(item, nanotime()) {
+		found = false
+	}
+	if !found {
+		item = Item{Object: new(CounterValue), Expiration: thisCache.expiration(DefaultExpiration)}
+	} else if thisCache.valueCodec != nil {
+		plain, err := thisCache.plainItem(item)
+		if err != nil {
+			return 0
+		}
+		item = plain
+	}
+	value, result, err := incrementValue(item.Object, n)
+	if err != nil {
+		return 0
+	}
+	item.Object = value
+	if thisCache.valueCodec != nil {
+		if item, err = thisCache.storedItem(item); err != nil {
+			thisCache.logger.Errorf("cache encode %s: %v", thisCounter.key, err)
+			return 0
+		}
+	}
+	item.Version = thisCache.nextVersion()
+	thisCache.putItem(thisCounter.key, item)
+	return result
+}
+
+/***************************************************************************************
+ * 功能描述：获取计数器的当前值
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：当前值；数据项不存在、已过期或值不是整数时返回 0
+ * 其他说明：该函数为 Counter 类方法，只获取读锁，不创建数据项
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCounter *Counter) Value() int64 {
+	thisCache := thisCounter.cacher
+	thisCache.mux.RLock()
+	item, found := thisCache.items[thisCounter.key]
+	thisCache.mux.RUnlock()
+	if !found || thisCache.expired(item, nanotime()) || item.negative() {
+		return 0
+	}
+	item, err := thisCache.plainItem(item)
+	if err != nil {
+		return 0
+	}
+	if cell, ok := item.Object.(*CounterValue); ok {
+		return cell.n.Load()
+	}
+	_, result, err := incrementValue(item.Object, 0)
+	if err != nil {
+		return 0
+	}
+	return result
+}
+
+/***************************************************************************************
+ * 功能描述：获取计数器的当前值
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：当前值
+ * 其他说明：该函数为 CounterValue 类方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisValue *CounterValue) Load() int64 {
+	return thisValue.n.Load()
+}
+
+/***************************************************************************************
+ * 功能描述：序列化计数器，用于 gob、msgpack 等编解码器
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：8 字节大端序的当前值，error 总为 nil
+ * 其他说明：该函数为 CounterValue 类方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisValue *CounterValue) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, uint64(thisValue.n.Load()))
+	return data, nil
+}
+
+/***************************************************************************************
+ * 功能描述：反序列化计数器
+ * 输入参数：序列化数据：data []byte
+ * 输出参数：无
+ * 返 回 值：无 error， 则为 nil
+ * 其他说明：该函数为 CounterValue 类方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisValue *CounterValue) UnmarshalBinary(data []byte) error {
+	if len(data) != 8 {
+		return errCounterValue
+	}
+	thisValue.n.Store(int64(binary.BigEndian.Uint64(data)))
+	return nil
+}
+
+/***************************************************************************************
+ * 功能描述：将计数器序列化为 JSON 数字
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：JSON 数字，error 总为 nil
+ * 其他说明：该函数为 CounterValue 类方法，JSONCodec 解码后得到 float64 而不是 *CounterValue
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisValue *CounterValue) MarshalJSON() ([]byte, error) {
+	return strconv.AppendInt(nil, thisValue.n.Load(), 10), nil
+}
@@ -0,0 +1,230 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：counter_test.go
+ * 内容摘要：计数器句柄测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+/***************************************************************************************
+ * 功能描述：测试并发自增的结果
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：已有整数值时在原类型上自增
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestCounterAdd(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	cacher.Set("hits", 10, 0)
+	counter := cacher.Counter("hits")
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				counter.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := counter.Value(); got != 810 {
+		t.Fatalf("Value = %d, want 810", got)
+	}
+	if got := cacher.Counter("").Add(1); got != 0 {
+		t.Fatalf("Add on empty key = %d, want 0", got)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试每次自增都写入增量持久化日志，重放后得到最终计数
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestCounterWriteAheadLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.wal")
+	cacher, err := NewCache(0, 0, WithWriteAheadLog(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	counter := cacher.Counter("hits")
+	for i := 0; i < 3; i++ {
+		counter.Add(1)
+	}
+	if err = cacher.CloseLog(); err != nil {
+		t.Fatal(err)
+	}
+
+	replayed, _ := NewCache(0, 0)
+	if err = replayed.LoadFromLog(path); err != nil {
+		t.Fatal(err)
+	}
+	if got := replayed.Counter("hits").Value(); got != 3 {
+		t.Fatalf("replayed counter = %d, want 3", got)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试每次自增都通知订阅者并分配新的版本号
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestCounterNotifyAndVersion(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	sub := cacher.Subscribe(16, DropNewest)
+	defer sub.Unsubscribe()
+	counter := cacher.Counter("hits")
+
+	var lastVersion uint64
+	for i := 0; i < 3; i++ {
+		counter.Add(1)
+		_, version, found := cacher.GetWithVersion("hits")
+		if !found || version <= lastVersion {
+			t.Fatalf("Add %d: version %d, found %v, want > %d", i+1, version, found, lastVersion)
+		}
+		lastVersion = version
+		event := <-sub.Events()
+		if event.Kind != EventSet || event.Key != "hits" {
+			t.Fatalf("Add %d: event %+v, want EventSet hits", i+1, event)
+		}
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试启用 WithSerializedValues 时计数器可多次自增
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestCounterSerialized(t *testing.T) {
+	cacher := newSerialCache(t)
+	counter := cacher.Counter("hits")
+	for want := int64(1); want <= 3; want++ {
+		if got := counter.Add(1); got != want {
+			t.Fatalf("Add = %d, want %d", got, want)
+		}
+	}
+	if got := counter.Value(); got != 3 {
+		t.Fatalf("Value = %d, want 3", got)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试自增保留数据项原有的值类型
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：已有 int 值时仍为 int，句柄新建的数据项为 *CounterValue
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestCounterKeepsType(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	cacher.Set("hits", 10, 0)
+	if got := cacher.Counter("hits").Add(5); got != 15 {
+		t.Fatalf("Add = %d, want 15", got)
+	}
+	if value, _, _ := cacher.Get("hits"); value != 15 {
+		t.Fatalf("Get = %#v, want int 15", value)
+	}
+	cacher.Counter("fresh").Add(2)
+	value, _, _ := cacher.Get("fresh")
+	if cell, ok := value.(*CounterValue); !ok || cell.Load() != 2 {
+		t.Fatalf("Get = %#v, want *CounterValue 2", value)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试自增不修改已取得的快照与 Get 结果
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：每次自增写入新的 *CounterValue
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestCounterSnapshotUnchanged(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	counter := cacher.Counter("hits")
+	counter.Add(1)
+	snapshot := cacher.Snapshot()
+	before, _, _ := cacher.Get("hits")
+	counter.Add(1)
+	if got := snapshot["hits"].Object.(*CounterValue).Load(); got != 1 {
+		t.Fatalf("snapshot counter = %d after Add, want 1", got)
+	}
+	if got := before.(*CounterValue).Load(); got != 1 {
+		t.Fatalf("earlier Get = %d after Add, want 1", got)
+	}
+	if got := counter.Value(); got != 2 {
+		t.Fatalf("Value = %d, want 2", got)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：比较计数器句柄与重复调用 Increment 的开销
+ * 输入参数：b *testing.B
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：两者的数据项都没有过期时间
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func BenchmarkCounter(b *testing.B) {
+	b.Run("Counter.Add", func(b *testing.B) {
+		cacher, _ := NewCache(0, 0)
+		counter := cacher.Counter("hits")
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			counter.Add(1)
+		}
+	})
+	b.Run("Increment", func(b *testing.B) {
+		cacher, _ := NewCache(0, 0)
+		cacher.Set("hits", int64(0), 0)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			cacher.Increment("hits", 1)
+		}
+	})
+}
@@ -0,0 +1,177 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：cow.go
+ * 内容摘要：枚举数据项(Keys、Items、ForEach)，以及无锁枚举使用的写时复制快照。
+ * 其他说明：默认枚举时持有读锁复制数据项。通过 WithCopyOnWrite 启用后，每次修改了数据项的写锁
+ *           在释放前复制整个 items 并原子地发布，枚举直接读取已发布的只读 map，不获取任何锁，
+ *           也不阻塞写入。代价是每次写入(Set、Delete、Touch、过期清理等)都要复制全部数据项，
+ *           写入开销与数据项数量成正比，只适用于数据项较少、读多写少且频繁枚举的缓存；
+ *           仅由读取更新的 LastAccess 不触发复制，枚举看到的 LastAccess 可能滞后。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"sync/atomic"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+type cowState struct { // 写时复制的快照
+	items atomic.Pointer[map[string]Item] // 已发布的只读 items 副本
+	dirty bool                            // 本次写锁期间 items 是否被修改，由 mux 保护
+}
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：标记 items 已被修改，调用时须持有写锁
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，未启用 WithCopyOnWrite 时不做任何事
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) markDirty() {
+	if thisCache.cow != nil {
+		thisCache.cow.dirty = true
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：items 被修改时复制并发布新的快照，调用时须持有写锁
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，由 unlock 在释放写锁之前调用
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) publishItems() {
+	if thisCache.cow == nil || !thisCache.cow.dirty {
+		return
+	}
+	items := make(map[string]Item, len(thisCache.items))
+	for key, val := range thisCache.items {
+		items[key] = val
+	}
+	thisCache.cow.items.Store(&items)
+	thisCache.cow.dirty = false
+}
+
+/***************************************************************************************
+ * 功能描述：对每个未过期的数据项调用 fn
+ * 输入参数：回调函数：fn func(key string, value interface{}) bool，返回 false 时停止
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，顺序不确定；启用 WithCopyOnWrite 时遍历已发布的快照，不获取锁，
+ *           否则先在读锁内复制数据项，调用 fn 时不持有锁，fn 中可以读写缓存；
+ *           值的处理与 Get 相同(WithCopyOnGet 复制、WithSerializedValues 解码)，处理失败的数据项被跳过
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) ForEach(fn func(key string, value interface{}) bool) {
+	now := nanotime()
+	for key, val := range thisCache.view() {
+		if thisCache.expired(val, now) || val.negative() {
+			continue
+		}
+		value, err := thisCache.readValue(val.Object)
+		if err != nil {
+			continue
+		}
+		if !fn(key, value) {
+			return
+		}
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：获取所有未过期数据项的键名
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：[]string 键名，顺序不确定
+ * 其他说明：该函数为 Cache 类方法，启用 WithCopyOnWrite 时不获取锁
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) Keys() []string {
+	now := nanotime()
+	items := thisCache.view()
+	keys := make([]string, 0, len(items))
+	for key, val := range items {
+		if !thisCache.expired(val, now) && !val.negative() {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+/***************************************************************************************
+ * 功能描述：获取所有未过期的数据项
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：map[string]Item 数据项的副本
+ * 其他说明：该函数为 Cache 类方法，启用 WithCopyOnWrite 时不获取锁；
+ *           与 Snapshot 不同，不包含已过期但尚未清理的数据项；数据项的值为缓存中保存的值(浅拷贝)，
+ *           启用 WithSerializedValues 时为解码后的值，解码失败的数据项被跳过
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) Items() map[string]Item {
+	now := nanotime()
+	items := thisCache.view()
+	live := make(map[string]Item, len(items))
+	for key, val := range items {
+		if thisCache.expired(val, now) || val.negative() {
+			continue
+		}
+		if val, err := thisCache.plainItem(val); err == nil {
+			live[key] = val
+		}
+	}
+	return live
+}
+
+/***************************************************************************************
+ * 功能描述：获取用于枚举的数据项
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：map[string]Item 只读的数据项，调用者不得修改
+ * 其他说明：该函数为 Cache 类方法，启用 WithCopyOnWrite 时返回已发布的快照，否则在读锁内复制
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) view() map[string]Item {
+	if thisCache.cow != nil {
+		return *thisCache.cow.items.Load()
+	}
+	thisCache.mux.RLock()
+	defer thisCache.mux.RUnlock()
+
+	items := make(map[string]Item, len(thisCache.items))
+	for key, val := range thisCache.items {
+		items[key] = val
+	}
+	return items
+}
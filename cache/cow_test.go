@@ -0,0 +1,108 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：cow_test.go
+ * 内容摘要：枚举与写时复制测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：测试 Keys、Items、ForEach 在启用与未启用写时复制时只返回未过期的数据项，且反映最近的写入
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：ForEach 中可以写入缓存，返回 false 时停止
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestEnumeration(t *testing.T) {
+	modes := map[string][]Option{"RWMutex": nil, "CopyOnWrite": {WithCopyOnWrite()}}
+	for name, opts := range modes {
+		cacher, _ := NewCache(0, 0, opts...)
+		cacher.Set("a", 1, 0)
+		cacher.Set("b", 2, 0)
+		cacher.Set("c", 3, 0)
+		cacher.Set("expired", 4, time.Nanosecond)
+		time.Sleep(time.Millisecond)
+		cacher.Delete("a")
+
+		keys := cacher.Keys()
+		sort.Strings(keys)
+		if len(keys) != 2 || keys[0] != "b" || keys[1] != "c" {
+			t.Errorf("%s: Keys = %v, want [b c]", name, keys)
+		}
+		if items := cacher.Items(); len(items) != 2 || items["b"].Object != 2 {
+			t.Errorf("%s: Items = %v, want b and c", name, items)
+		}
+		visited := 0
+		cacher.ForEach(func(key string, value interface{}) bool {
+			visited++
+			cacher.Set(key+"!", value, 0)
+			return false
+		})
+		if visited != 1 || cacher.Count() != 4 {
+			t.Errorf("%s: ForEach visited %d items and left %d, want 1 and 4", name, visited, cacher.Count())
+		}
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：比较并发写入时启用与未启用写时复制的枚举开销
+ * 输入参数：b *testing.B
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：后台 goroutine 持续写入，并行调用 Keys
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func BenchmarkKeysUnderWrites(b *testing.B) {
+	modes := map[string][]Option{"RWMutex": nil, "CopyOnWrite": {WithCopyOnWrite()}}
+	for name, opts := range modes {
+		b.Run(name, func(b *testing.B) {
+			cacher, _ := NewCache(0, 0, opts...)
+			for i := 0; i < 1000; i++ {
+				cacher.Set("key"+strconv.Itoa(i), i, 0)
+			}
+			stop := make(chan struct{})
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				for i := 0; ; i++ {
+					select {
+					case <-stop:
+						return
+					default:
+						cacher.Set("key"+strconv.Itoa(i%1000), i, 0)
+					}
+				}
+			}()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					cacher.Keys()
+				}
+			})
+			b.StopTimer()
+			close(stop)
+			<-done
+		})
+	}
+}
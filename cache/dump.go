@@ -0,0 +1,90 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：dump.go
+ * 内容摘要：以 JSON Lines 格式导出数据项，用于排查问题。
+ * 其他说明：每行一个 JSON 对象 {"key":..,"value":..,"expires_at":..}，按键名排序，便于 grep 与 diff；
+ *           导出结果只用于阅读，不能通过 Load 导入，持久化请使用 Save。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+type dumpLine struct { // JSON Lines 导出的一行
+	Key       string          `json:"key"`        // 数据项键名
+	Value     json.RawMessage `json:"value"`      // 数据项的值
+	ExpiresAt *time.Time      `json:"expires_at"` // 过期时间，永不过期时为 null
+}
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：以 JSON Lines 格式导出所有未过期的数据项
+ * 输入参数：wrt io.Writer
+ * 输出参数：无
+ * 返 回 值：无 error， 则为 nil
+ * 其他说明：该函数为 Cache 类方法，基于 Snapshot 导出，写入期间不持有锁；
+ *           无法编码为 JSON 的值导出为 "<类型名>" 字符串，不影响其它数据项
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) DumpJSONL(wrt io.Writer) error {
+	now := nanotime()
+	snap := thisCache.Snapshot()
+	keys := make([]string, 0, len(snap))
+	for key, val := range snap {
+		if !thisCache.expired(val, now) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	bufWrt := bufio.NewWriter(wrt)
+	encode := json.NewEncoder(bufWrt) // Encode 在每个对象后写入换行
+	encode.SetEscapeHTML(false)
+	var valBuf bytes.Buffer
+	encodeVal := json.NewEncoder(&valBuf) // json.Marshal 总是转义 <>&，RawMessage 写出时不会还原
+	encodeVal.SetEscapeHTML(false)
+	for _, key := range keys {
+		val := snap[key]
+		if decoded, err := thisCache.decodeValue(val.Object); err == nil {
+			val.Object = decoded
+		}
+		valBuf.Reset()
+		var value json.RawMessage
+		if err := encodeVal.Encode(val.Object); err == nil {
+			value = bytes.TrimSuffix(valBuf.Bytes(), []byte("\n"))
+		} else {
+			value = json.RawMessage(strconv.Quote(fmt.Sprintf("<%T>", val.Object))) // 类型名只含 ASCII 字符
+		}
+		line := dumpLine{Key: key, Value: value}
+		if val.Expiration > 0 {
+			expiresAt := time.Unix(0, val.Expiration).UTC()
+			line.ExpiresAt = &expiresAt
+		}
+		if err := encode.Encode(line); err != nil {
+			return err
+		}
+	}
+	return bufWrt.Flush()
+}
@@ -0,0 +1,68 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：dump_test.go
+ * 内容摘要：JSON Lines 导出测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：测试 DumpJSONL：按键名排序逐行导出未过期的数据项，无法编码的值导出为类型名
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：前移 clockWall 使数据项过期
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestDumpJSONL(t *testing.T) {
+	saved := clockWall
+	defer func() { clockWall = saved }()
+
+	cacher, _ := NewCache(0, 0)
+	cacher.Set("b", map[string]int{"x": 1}, NoExpiration)
+	cacher.Set("a", "<tag>", NoExpiration)
+	cacher.Set("gone", 1, time.Minute)
+	clockWall += int64(2 * time.Minute)
+	cacher.Set("c", func() {}, time.Hour)
+
+	var buf bytes.Buffer
+	if err := cacher.DumpJSONL(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	want := []string{
+		`{"key":"a","value":"<tag>","expires_at":null}`,
+		`{"key":"b","value":{"x":1},"expires_at":null}`,
+	}
+	if len(lines) != 3 || lines[0] != want[0] || lines[1] != want[1] {
+		t.Fatalf("dump = %q, want %q followed by c", lines, want)
+	}
+	var last dumpLine
+	if err := json.Unmarshal([]byte(lines[2]), &last); err != nil {
+		t.Fatal(err)
+	}
+	if last.Key != "c" || string(last.Value) != `"<func()>"` || last.ExpiresAt == nil {
+		t.Fatalf("last line = %s, want c with its type name and expiry", lines[2])
+	}
+	if ttl := time.Until(*last.ExpiresAt) - 2*time.Minute; ttl <= 59*time.Minute || ttl > time.Hour {
+		t.Errorf("expires_at %v is %v away, want about 1h", last.ExpiresAt, ttl)
+	}
+}
@@ -0,0 +1,119 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：errors_test.go
+ * 内容摘要：哨兵错误测试。
+ * 其他说明：各方法返回的错误均可用 errors.Is 判断
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：测试键相关的哨兵错误
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestSentinelErrors(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	cacher.Set("name", "value", 0)
+
+	if err := cacher.Add("name", "other", 0); !errors.Is(err, ErrKeyExists) {
+		t.Errorf("Add existing: %v, want ErrKeyExists", err)
+	}
+	if err := cacher.Replace("missing", "value", 0); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Replace missing: %v, want ErrKeyNotFound", err)
+	}
+	if _, err := cacher.GetOrError("missing"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("GetOrError missing: %v, want ErrKeyNotFound", err)
+	}
+	if _, err := cacher.Increment("missing", 1); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Increment missing: %v, want ErrKeyNotFound", err)
+	}
+	if _, err := cacher.Increment("name", 1); !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("Increment string: %v, want ErrTypeMismatch", err)
+	}
+	if err := cacher.Set("", "value", 0); !errors.Is(err, ErrKeyInvalid) {
+		t.Errorf("Set empty key: %v, want ErrKeyInvalid", err)
+	}
+	if _, err := cacher.Increment("", 1); !errors.Is(err, ErrKeyInvalid) {
+		t.Errorf("Increment empty key: %v, want ErrKeyInvalid", err)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试缓存关闭后所有写入方法均被拒绝
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：返回 error 的方法返回 ErrCacheClosed，其余方法返回未写入
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestClosedCache(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	cacher.Set("name", "value", 0)
+	cacher.Set("hits", 1, 0)
+	cacher.Close()
+
+	compute := func() (interface{}, error) { return "computed", nil }
+	checks := map[string]error{
+		"Set":           cacher.Set("key", "value", 0),
+		"Add":           cacher.Add("key", "value", 0),
+		"Replace":       cacher.Replace("name", "other", 0),
+		"UnmarshalItem": cacher.UnmarshalItem("key", []byte("{}")),
+	}
+	_, checks["Increment"] = cacher.Increment("hits", 1)
+	_, checks["GetOrCompute"] = cacher.GetOrCompute("key", 0, compute)
+	_, checks["Put"] = cacher.Put("value", 0)
+	for name, err := range checks {
+		if !errors.Is(err, ErrCacheClosed) {
+			t.Errorf("%s: %v, want ErrCacheClosed", name, err)
+		}
+	}
+
+	if _, existed := cacher.Swap("name", "other", 0); existed {
+		t.Error("Swap succeeded on a closed cache")
+	}
+	if _, loaded := cacher.LoadOrStore("key", "value", 0); loaded {
+		t.Error("LoadOrStore succeeded on a closed cache")
+	}
+	if cacher.SetKeepTTL("name", "other") {
+		t.Error("SetKeepTTL succeeded on a closed cache")
+	}
+	if cacher.Update("name", func(old interface{}, found bool) (interface{}, time.Duration, bool) {
+		return "other", 0, true
+	}) {
+		t.Error("Update succeeded on a closed cache")
+	}
+	if cacher.Touch("name", 0) {
+		t.Error("Touch succeeded on a closed cache")
+	}
+	if got := cacher.Counter("hits").Add(1); got != 0 {
+		t.Errorf("Counter.Add = %d, want 0", got)
+	}
+	if value, found, _ := cacher.Get("name"); !found || value != "value" {
+		t.Errorf("Get after rejected writes = %v, %v, want value", value, found)
+	}
+	if _, found, _ := cacher.Get("key"); found {
+		t.Error("rejected write is visible")
+	}
+}
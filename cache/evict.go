@@ -0,0 +1,151 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：evict.go
+ * 内容摘要：数据项被移出缓存时的回调。
+ * 其他说明：回调总是在释放锁之后调用，回调中可以安全地访问缓存；墓碑数据项被移出时不调用回调。
+ *           Restore 与 Load 系列方法整体替换或恢复数据项，不调用回调。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"time"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+type Reason int // 数据项被移出缓存的原因
+
+const (
+	Deleted  Reason = iota // 被 Delete 删除
+	Expired                // 过期后被清理，或过期后被新值覆盖
+	Flushed                // 被 Flush 清空
+	Capacity               // 被 TrimToCount/TrimToBytes 裁剪
+	Replaced               // 未过期时被 Set 等写入的新值覆盖
+)
+
+type evictFunc func(key string, value interface{}, reason Reason) // 数据项被移出缓存时的回调
+
+type keyAndValue struct { // 被移出缓存的数据项
+	key   string      // 数据项键名
+	value interface{} // 数据项的值
+}
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：设置数据项被移出缓存时的回调
+ * 输入参数：回调函数：fn func(key string, value interface{})，为 nil 时取消回调
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，等价于忽略原因的 OnEvictedReason
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) OnEvicted(fn func(key string, value interface{})) {
+	if fn == nil {
+		thisCache.OnEvictedReason(nil)
+		return
+	}
+	thisCache.OnEvictedReason(func(key string, value interface{}, reason Reason) {
+		fn(key, value)
+	})
+}
+
+/***************************************************************************************
+ * 功能描述：设置数据项被移出缓存时的回调，回调可得到移出的原因
+ * 输入参数：回调函数：fn func(key string, value interface{}, reason Reason)，为 nil 时取消回调
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，Delete(Deleted)、过期清理与 Compact(Expired)、Flush(Flushed)、
+ *           裁剪(Capacity)、写入覆盖未过期的值(Replaced)或已过期的值(Expired)均会调用该回调；
+ *           回调 panic 时被恢复并记录日志，见 recover.go
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) OnEvictedReason(fn func(key string, value interface{}, reason Reason)) {
+	if fn != nil {
+		callback := fn
+		fn = func(key string, value interface{}, reason Reason) {
+			defer thisCache.recoverCallback("onEvicted", key, nil) // 单个回调 panic 不影响其余数据项的回调
+			if decoded, err := thisCache.decodeValue(value); err == nil {
+				value = decoded
+			}
+			callback(key, value, reason)
+		}
+	}
+	thisCache.lock()
+	defer thisCache.unlock()
+	thisCache.onEvicted = fn
+}
+
+/***************************************************************************************
+ * 功能描述：写入数据项，返回被覆盖的数据项及其原因，调用时须持有写锁
+ * 输入参数：数据项键名：key string, 数据项键值：value interface{}, 数据项生命周期：dur time.Duration
+ * 输出参数：无
+ * 返 回 值：需要调用 onEvicted 的数据项(未设置回调或没有覆盖时为 nil)，以及原因
+ * 其他说明：该函数为 Cache 类方法，覆盖未过期的值为 Replaced，覆盖已过期但未清理的值为 Expired；
+ *           调用者释放锁后将返回值传给 fireEvicted
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) overwrite(key string, value interface{}, dur time.Duration) ([]keyAndValue, Reason) {
+	evicted, reason := thisCache.displaced(key)
+	thisCache.set(key, value, dur)
+	return evicted, reason
+}
+
+/***************************************************************************************
+ * 功能描述：判断写入 key 时将被覆盖的数据项及其原因，调用时须持有锁
+ * 输入参数：数据项键名：key string
+ * 输出参数：无
+ * 返 回 值：需要调用 onEvicted 的数据项(未设置回调或没有覆盖时为 nil)，以及原因
+ * 其他说明：该函数为 Cache 类方法，用于不经过 set 的写入
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) displaced(key string) ([]keyAndValue, Reason) {
+	old, found := thisCache.items[key]
+	if !found || thisCache.onEvicted == nil || old.negative() {
+		return nil, Replaced
+	}
+	if thisCache.expired(old, nanotime()) {
+		return []keyAndValue{{key, old.Object}}, Expired
+	}
+	return []keyAndValue{{key, old.Object}}, Replaced
+}
+
+/***************************************************************************************
+ * 功能描述：对一组被移出缓存的数据项调用回调
+ * 输入参数：回调函数：onEvicted, 被移出的数据项：evicted []keyAndValue, 原因：reason Reason
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：必须在释放锁之后调用
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func fireEvicted(onEvicted evictFunc, evicted []keyAndValue, reason Reason) {
+	if onEvicted == nil {
+		return
+	}
+	for _, kv := range evicted {
+		onEvicted(kv.key, kv.value, reason)
+	}
+}
@@ -0,0 +1,121 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：expire.go
+ * 内容摘要：为单个数据项设置过期时调用一次的回调。
+ * 其他说明：与全局的 onEvicted 不同，回调只属于一个数据项，只在该数据项因过期被 gcLoop、Compact
+ *           或 WithDeleteOnExpiredRead 删除时调用；Delete、覆盖写入、裁剪与 Flush 等移除数据项时
+ *           回调被丢弃而不调用。每个设置了回调的数据项额外占用一个 map 项与一个闭包，
+ *           闭包引用的对象在数据项移除前不会被回收。回调不随 Save/Load 持久化。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"time"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+type expireHooks map[string]func(key string, value interface{}) // 键名到过期回调
+
+type expiredHook struct { // 待调用的过期回调
+	key   string                              // 数据项键名
+	value interface{}                         // 数据项的值
+	hook  func(key string, value interface{}) // 过期回调
+}
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：设置数据项，并设置该数据项过期时调用一次的回调
+ * 输入参数：数据项键名：key string, 数据项键值：value interface{}, 数据项生命周期：dur time.Duration,
+ *           过期回调：onExpire func(key string, value interface{})
+ * 输出参数：无
+ * 返 回 值：无 error， 则为 nil
+ * 其他说明：该函数为 Cache 类方法，其余行为与 Set 相同；onExpire 为 nil 时等同于 Set；
+ *           onExpire 在释放锁后调用，panic 时被恢复并记录日志
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) SetWithExpireCallback(key string, value interface{}, dur time.Duration, onExpire func(key string, value interface{})) error {
+	if thisCache.closed.Load() {
+		return ErrCacheClosed
+	}
+	key, err := thisCache.checkKey(key)
+	if err != nil {
+		return err
+	}
+	if err := thisCache.checkValueSize(value); err != nil {
+		return err
+	}
+	if err := thisCache.lockWithTimeout(); err != nil {
+		return err
+	}
+	evicted, reason := thisCache.overwrite(key, value, dur)
+	if onExpire != nil {
+		if thisCache.expireHooks == nil {
+			thisCache.expireHooks = expireHooks{}
+		}
+		thisCache.expireHooks[key] = onExpire
+	}
+	onEvicted := thisCache.onEvicted
+	thisCache.unlock()
+
+	fireEvicted(onEvicted, evicted, reason)
+	return nil
+}
+
+/***************************************************************************************
+ * 功能描述：取出并移除数据项的过期回调，调用时须持有写锁
+ * 输入参数：数据项键名：key string
+ * 输出参数：无
+ * 返 回 值：过期回调，未设置时为 nil
+ * 其他说明：该函数为 Cache 类方法，数据项被移除或覆盖时调用
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) takeExpireHook(key string) func(key string, value interface{}) {
+	if len(thisCache.expireHooks) == 0 {
+		return nil
+	}
+	hook, found := thisCache.expireHooks[key]
+	if found {
+		delete(thisCache.expireHooks, key)
+	}
+	return hook
+}
+
+/***************************************************************************************
+ * 功能描述：调用过期回调
+ * 输入参数：待调用的过期回调：hooks []expiredHook
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，不持有锁时调用；启用 WithSerializedValues 时传入解码后的值
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) fireExpireHooks(hooks []expiredHook) {
+	for _, expired := range hooks {
+		value, err := thisCache.decodeValue(expired.value)
+		if err != nil {
+			continue
+		}
+		thisCache.protect("onExpire", expired.key, func() error {
+			expired.hook(expired.key, value)
+			return nil
+		})
+	}
+}
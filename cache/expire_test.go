@@ -0,0 +1,47 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：expire_test.go
+ * 内容摘要：数据项过期回调测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：测试过期回调只在因过期删除时调用一次
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：覆盖写入时丢弃回调
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestExpireCallback(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	var expired []string
+	onExpire := func(key string, value interface{}) {
+		expired = append(expired, key)
+	}
+	cacher.SetWithExpireCallback("a", 1, time.Nanosecond, onExpire)
+	cacher.SetWithExpireCallback("b", 2, time.Nanosecond, onExpire)
+	cacher.Set("b", 3, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	cacher.DeleteExpired()
+	cacher.DeleteExpired()
+	if len(expired) != 1 || expired[0] != "a" {
+		t.Errorf("expired = %v, want [a]", expired)
+	}
+}
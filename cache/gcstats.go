@@ -0,0 +1,130 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：gcstats.go
+ * 内容摘要：过期数据项清理的统计。
+ * 其他说明：每次清理(gcLoop 或 DeleteExpired)记录持有写锁扫描的耗时与删除数量，
+ *           耗时即清理期间读写被阻塞的时间，可用于调整 gcInterval。
+ *           平均值为指数移动平均，最近一次清理的权重为 gcStatsWeight。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"time"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+type GCStats struct { // 过期数据项清理的统计
+	Sweeps       int64         // 清理次数
+	LastSweep    time.Time     // 最近一次清理的开始时间
+	LastDuration time.Duration // 最近一次清理持有写锁的耗时
+	LastScanned  int           // 最近一次清理扫描的数据项数量
+	LastReaped   int           // 最近一次清理删除的数据项数量
+	AvgDuration  time.Duration // 清理耗时的移动平均
+	AvgReaped    float64       // 清理删除数量的移动平均
+}
+
+const gcStatsWeight = 0.2 // 移动平均中最近一次清理的权重
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：记录一次清理，调用时须持有 statMux
+ * 输入参数：开始时间：start time.Time, 耗时：dur time.Duration,
+ *           扫描数量：scanned int, 删除数量：reaped int
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 GCStats 类方法，第一次清理直接作为平均值
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisStats *GCStats) record(start time.Time, dur time.Duration, scanned, reaped int) {
+	if thisStats.Sweeps == 0 {
+		thisStats.AvgDuration = dur
+		thisStats.AvgReaped = float64(reaped)
+	} else {
+		thisStats.AvgDuration += time.Duration(gcStatsWeight * float64(dur-thisStats.AvgDuration))
+		thisStats.AvgReaped += gcStatsWeight * (float64(reaped) - thisStats.AvgReaped)
+	}
+	thisStats.Sweeps++
+	thisStats.LastSweep = start
+	thisStats.LastDuration = dur
+	thisStats.LastScanned = scanned
+	thisStats.LastReaped = reaped
+}
+
+/***************************************************************************************
+ * 功能描述：获取过期数据项清理的统计
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：GCStats 统计的副本
+ * 其他说明：该函数为 Cache 类方法，不获取 mux，不会被正在进行的清理阻塞
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) GCStats() GCStats {
+	thisCache.statMux.Lock()
+	defer thisCache.statMux.Unlock()
+	return thisCache.gcStats
+}
+
+/***************************************************************************************
+ * 功能描述：获取最近一次清理完成的时间
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：time.Time 最近一次 gcLoop 或 DeleteExpired 完成(包括 onEvicted 回调)的时间，从未清理时为零值
+ * 其他说明：该函数为 Cache 类方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) LastGCRun() time.Time {
+	thisCache.statMux.Lock()
+	defer thisCache.statMux.Unlock()
+	return thisCache.lastGcRun
+}
+
+/***************************************************************************************
+ * 功能描述：检查 gcLoop 是否按周期执行清理，用于存活探针
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：gcLoop 正常为 true
+ * 其他说明：该函数为 Cache 类方法。gcLoop 应运行(已启动且未暂停)时，若最近一次清理完成、
+ *           启动、恢复或修改周期以来超过两个清理周期(自适应时为上限)仍未完成清理，
+ *           说明 gcLoop 阻塞在锁或 onEvicted 回调上，返回 false；
+ *           gcLoop 未运行(未设置周期、WithLazyGC 尚未写入、StopGc 或 PauseGC)时返回 true
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) IsHealthy() bool {
+	thisCache.gcMux.Lock()
+	running := thisCache.gcRunning && !thisCache.gcPaused
+	interval := thisCache.gcInterval
+	if thisCache.gcMinInterval > 0 {
+		interval = thisCache.gcMaxInterval
+	}
+	since := thisCache.gcSince
+	thisCache.gcMux.Unlock()
+	if !running || interval <= 0 {
+		return true
+	}
+	if last := thisCache.LastGCRun(); last.After(since) {
+		since = last
+	}
+	return time.Since(since) <= 2*interval
+}
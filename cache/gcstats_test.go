@@ -0,0 +1,124 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：gcstats_test.go
+ * 内容摘要：过期数据项清理统计测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：测试 GCStats：DeleteExpired 记录扫描与删除数量，平均删除数量按移动平均更新
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：前移 clockWall 使数据项过期
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestGCStats(t *testing.T) {
+	saved := clockWall
+	defer func() { clockWall = saved }()
+
+	cacher, _ := NewCache(0, 0)
+	if stats := cacher.GCStats(); stats.Sweeps != 0 || !stats.LastSweep.IsZero() {
+		t.Fatalf("stats before any sweep = %+v, want zero", stats)
+	}
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		cacher.Set(key, 1, time.Minute)
+	}
+	cacher.Set("keep", 1, NoExpiration)
+	clockWall += int64(2 * time.Minute)
+	before := time.Now()
+	cacher.DeleteExpired()
+	stats := cacher.GCStats()
+	if stats.Sweeps != 1 || stats.LastScanned != 6 || stats.LastReaped != 5 || stats.AvgReaped != 5 {
+		t.Fatalf("stats after first sweep = %+v, want 1 sweep scanning 6 and reaping 5", stats)
+	}
+	if stats.LastSweep.Before(before) || stats.LastDuration < 0 || stats.AvgDuration != stats.LastDuration {
+		t.Fatalf("stats after first sweep = %+v, want timing of this sweep", stats)
+	}
+
+	cacher.DeleteExpired()
+	stats = cacher.GCStats()
+	if want := 5 * (1 - gcStatsWeight); stats.Sweeps != 2 || stats.LastScanned != 1 || stats.LastReaped != 0 || stats.AvgReaped != want {
+		t.Errorf("stats after second sweep = %+v, want 2 sweeps scanning 1 and reaping 0 with average %v", stats, want)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 LastGCRun 与 IsHealthy：超过两个清理周期未完成清理时不健康，清理完成后恢复；未运行或暂停时总是健康
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：使用手动触发的 fakeGcTimer，并回拨 gcSince 模拟 gcLoop 阻塞
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestIsHealthy(t *testing.T) {
+	timer := &fakeGcTimer{c: make(chan time.Time), resets: make(chan time.Duration, 4), stops: make(chan struct{}, 4)}
+	savedTimer := newGcTimer
+	defer func() { newGcTimer = savedTimer }()
+	newGcTimer = func(d time.Duration) gcTimer {
+		timer.resets <- d
+		return timer
+	}
+	stall := func(cacher *Cache) {
+		cacher.gcMux.Lock()
+		cacher.gcSince = time.Now().Add(-3 * time.Minute)
+		cacher.gcMux.Unlock()
+	}
+
+	idle, _ := NewCache(0, 0)
+	stall(idle)
+	if !idle.IsHealthy() || !idle.LastGCRun().IsZero() {
+		t.Fatal("cache without gcLoop reported unhealthy or a GC run")
+	}
+
+	cacher, _ := NewCache(0, time.Minute, WithoutGCJitter())
+	defer cacher.StopGc()
+	<-timer.resets
+	if !cacher.IsHealthy() {
+		t.Fatal("unhealthy right after start")
+	}
+	stall(cacher)
+	if cacher.IsHealthy() {
+		t.Fatal("healthy after 3 intervals without a GC run")
+	}
+	cacher.PauseGC()
+	<-timer.stops
+	if !cacher.IsHealthy() {
+		t.Fatal("unhealthy while paused")
+	}
+	cacher.ResumeGC()
+	<-timer.stops
+	<-timer.resets
+	stall(cacher)
+	if cacher.IsHealthy() {
+		t.Fatal("healthy after resuming and 3 intervals without a GC run")
+	}
+	before := time.Now()
+	timer.c <- before
+	<-timer.resets
+	if last := cacher.LastGCRun(); last.Before(before) {
+		t.Fatalf("LastGCRun = %v, want after %v", last, before)
+	}
+	if !cacher.IsHealthy() {
+		t.Error("unhealthy after a GC run")
+	}
+}
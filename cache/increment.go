@@ -0,0 +1,177 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：increment.go
+ * 内容摘要：数值类缓存数据项的原子自增操作。
+ * 其他说明：支持 int、int8、int16、int32、int64、uint、uint8、uint16、uint32、uint64、
+ *           uintptr、float32、float64 类型的数据项，自增后保持原有类型。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"fmt"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：对数值类型的值进行自增，无锁操作
+ * 输入参数：原值：val interface{}, 增量：n int64
+ * 输出参数：无
+ * 返 回 值：自增后的值(保持原类型)，自增后的值(int64)，error
+ * 其他说明：非数值类型返回错误
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func incrementValue(val interface{}, n int64) (interface{}, int64, error) {
+	switch v := val.(type) {
+	case int:
+		v += int(n)
+		return v, int64(v), nil
+	case int8:
+		v += int8(n)
+		return v, int64(v), nil
+	case int16:
+		v += int16(n)
+		return v, int64(v), nil
+	case int32:
+		v += int32(n)
+		return v, int64(v), nil
+	case int64:
+		v += n
+		return v, v, nil
+	case uint:
+		v += uint(n)
+		return v, int64(v), nil
+	case uintptr:
+		v += uintptr(n)
+		return v, int64(v), nil
+	case uint8:
+		v += uint8(n)
+		return v, int64(v), nil
+	case uint16:
+		v += uint16(n)
+		return v, int64(v), nil
+	case uint32:
+		v += uint32(n)
+		return v, int64(v), nil
+	case uint64:
+		v += uint64(n)
+		return v, int64(v), nil
+	case float32:
+		v += float32(n)
+		return v, int64(v), nil
+	case float64:
+		v += float64(n)
+		return v, int64(v), nil
+	case *CounterValue:
+		cell := new(CounterValue) // 不修改原计数器，已取得的快照与 Get 结果保持不变
+		cell.n.Store(v.n.Load() + n)
+		return cell, cell.n.Load(), nil
+	}
+	return nil, 0, fmt.Errorf("value %v is not a number: %w", val, ErrTypeMismatch)
+}
+
+/***************************************************************************************
+ * 功能描述：对数据项的值自增 n，保留数据项原有的过期时间
+ * 输入参数：数据项键名：key string, 增量：n int64
+ * 输出参数：无
+ * 返 回 值：自增后的值，无 error 则为 nil
+ * 其他说明：该函数为 Cache 类方法，数据项不存在或已过期时返回错误
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) Increment(key string, n int64) (int64, error) {
+	if thisCache.closed.Load() {
+		return 0, ErrCacheClosed
+	}
+	key, err := thisCache.checkKey(key)
+	if err != nil {
+		return 0, err
+	}
+	if err = thisCache.lockWithTimeout(); err != nil {
+		return 0, err
+	}
+	defer thisCache.unlock()
+
+	item, found := thisCache.items[key]
+	if !found || thisCache.expired(item, nanotime()) || item.negative() {
+		return 0, fmt.Errorf("item %v: %w", key, ErrKeyNotFound)
+	}
+	item, err = thisCache.plainItem(item)
+	if err != nil {
+		return 0, err
+	}
+	newVal, result, err := incrementValue(item.Object, n)
+	if err != nil {
+		return 0, err
+	}
+	item.Object = newVal
+	if item, err = thisCache.storedItem(item); err != nil {
+		return 0, err
+	}
+	item.Version = thisCache.nextVersion()
+	thisCache.putItem(key, item)
+	return result, nil
+}
+
+/***************************************************************************************
+ * 功能描述：对数据项的值自增 n，并将过期时间重置为 now+dur
+ * 输入参数：数据项键名：key string, 增量：n int64, 数据项生命周期：dur time.Duration
+ * 输出参数：无
+ * 返 回 值：自增后的值，无 error 则为 nil
+ * 其他说明：该函数为 Cache 类方法，数据项不存在或已过期时以 int64(n) 创建；
+ *           与 Increment 不同，每次自增都会刷新过期时间，适用于滑动窗口计数
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) IncrementWithExpiration(key string, n int64, dur time.Duration) (int64, error) {
+	if thisCache.closed.Load() {
+		return 0, ErrCacheClosed
+	}
+	key, err := thisCache.checkKey(key)
+	if err != nil {
+		return 0, err
+	}
+	if err = thisCache.lockWithTimeout(); err != nil {
+		return 0, err
+	}
+	item, found := thisCache.items[key]
+	if !found || thisCache.expired(item, nanotime()) || item.negative() {
+		evicted, reason := thisCache.overwrite(key, n, dur)
+		onEvicted := thisCache.onEvicted
+		thisCache.unlock()
+
+		fireEvicted(onEvicted, evicted, reason)
+		return n, nil
+	}
+	defer thisCache.unlock()
+
+	item, err = thisCache.plainItem(item)
+	if err != nil {
+		return 0, err
+	}
+	newVal, result, err := incrementValue(item.Object, n)
+	if err != nil {
+		return 0, err
+	}
+	item.Object = newVal
+	if item, err = thisCache.storedItem(item); err != nil {
+		return 0, err
+	}
+	item.Expiration = thisCache.expiration(dur)
+	item.Version = thisCache.nextVersion()
+	thisCache.putItem(key, item)
+	return result, nil
+}
@@ -0,0 +1,76 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：increment_test.go
+ * 内容摘要：自增测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：测试滑动窗口限流，持续自增使数据项一直存活
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：每次自增都将过期时间重置为 now+dur，停止自增后数据项在 dur 后过期
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestIncrementWithExpiration(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	window := 100 * time.Millisecond
+	for i := int64(1); i <= 5; i++ {
+		got, err := cacher.IncrementWithExpiration("requests", 1, window)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != i {
+			t.Fatalf("IncrementWithExpiration = %d, want %d", got, i)
+		}
+		time.Sleep(window / 3)
+	}
+	if value, found, _ := cacher.Get("requests"); !found || value != int64(5) {
+		t.Fatalf("requests = %v, %v, want 5 after %v", value, found, 5*window/3)
+	}
+	time.Sleep(window + window/2)
+	if _, found, _ := cacher.Get("requests"); found {
+		t.Error("requests still alive after the window passed without increments")
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 Increment 保留原有的过期时间
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestIncrementKeepsExpiration(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	cacher.Set("requests", int64(1), 60*time.Millisecond)
+	time.Sleep(40 * time.Millisecond)
+	if got, err := cacher.Increment("requests", 1); err != nil || got != 2 {
+		t.Fatalf("Increment = %d, %v, want 2", got, err)
+	}
+	time.Sleep(40 * time.Millisecond)
+	if _, found, _ := cacher.Get("requests"); found {
+		t.Error("Increment extended the expiration")
+	}
+}
@@ -0,0 +1,232 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：index.go
+ * 内容摘要：二级索引，按数据项的值中提取的字段查找数据项。
+ * 其他说明：每个索引保存 索引值 -> 键名集合 以及 键名 -> 索引值 两个 map，
+ *           内存开销约为每个被索引的数据项两份键名与一份索引值；每次写入都要调用所有索引的
+ *           提取函数，提取函数应当快速且不能访问缓存(调用时持有写锁)。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+
+/***************************************************************************************/
+// 数据结构与常量
+
+type IndexFunc func(value interface{}) (string, bool) // 从数据项的值中提取索引值，false 表示不索引
+
+type index struct { // 二级索引
+	owner   *Cache                         // 所属的缓存，用于在恢复边界内调用提取函数
+	extract IndexFunc                      // 提取索引值的函数
+	keys    map[string]map[string]struct{} // 索引值 -> 键名集合
+	values  map[string]string              // 键名 -> 索引值，用于删除旧的索引
+}
+
+type indexSet map[string]*index // 索引名 -> 二级索引
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：添加二级索引
+ * 输入参数：索引名：name string, 提取函数：extractor IndexFunc
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，对已有数据项立即建立索引，之后随写入、删除、过期清理更新；
+ *           同名索引被替换
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) AddIndex(name string, extractor IndexFunc) {
+	idx := &index{
+		owner:   thisCache,
+		extract: extractor,
+		keys:    map[string]map[string]struct{}{},
+		values:  map[string]string{},
+	}
+	thisCache.lock()
+	defer thisCache.unlock()
+	if thisCache.indexes == nil {
+		thisCache.indexes = indexSet{}
+	}
+	for key, item := range thisCache.items {
+		if item, err := thisCache.plainItem(item); err == nil {
+			idx.add(key, item)
+		}
+	}
+	thisCache.indexes[name] = idx
+}
+
+/***************************************************************************************
+ * 功能描述：按索引值查找数据项
+ * 输入参数：索引名：name string, 索引值：indexValue string
+ * 输出参数：无
+ * 返 回 值：[]interface{} 未过期的数据项的值，顺序不确定；索引不存在时为 nil
+ * 其他说明：该函数为 Cache 类方法，只需读锁；启用 WithCopyOnGet 时返回值的副本，复制失败的不包含在内
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) GetByIndex(name, indexValue string) []interface{} {
+	now := nanotime()
+	thisCache.mux.RLock()
+	idx, found := thisCache.indexes[name]
+	if !found {
+		thisCache.mux.RUnlock()
+		return nil
+	}
+	values := make([]interface{}, 0, len(idx.keys[indexValue]))
+	for key := range idx.keys[indexValue] {
+		if item, found := thisCache.items[key]; found && !thisCache.expired(item, now) {
+			values = append(values, item.Object)
+		}
+	}
+	thisCache.mux.RUnlock()
+
+	if thisCache.copier == nil && thisCache.valueCodec == nil {
+		return values
+	}
+	copied := values[:0]
+	for _, value := range values {
+		if value, err := thisCache.readValue(value); err == nil {
+			copied = append(copied, value)
+		}
+	}
+	return copied
+}
+
+/***************************************************************************************
+ * 功能描述：写入数据项后更新所有索引，调用时须持有写锁
+ * 输入参数：数据项键名：key string, 数据项：item Item
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，启用 WithSerializedValues 时解码后交给提取函数，解码失败的不加入索引
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) indexItem(key string, item Item) {
+	if len(thisCache.indexes) == 0 {
+		return
+	}
+	item, err := thisCache.plainItem(item)
+	for _, idx := range thisCache.indexes {
+		idx.remove(key)
+		if err == nil {
+			idx.add(key, item)
+		}
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：删除数据项后更新所有索引，调用时须持有写锁
+ * 输入参数：数据项键名：key string
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) unindexItem(key string) {
+	for _, idx := range thisCache.indexes {
+		idx.remove(key)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：items 被整体替换后重建所有索引，调用时须持有写锁
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，用于 Flush、Restore、Compact
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) reindex() {
+	if len(thisCache.indexes) == 0 {
+		return
+	}
+	for _, idx := range thisCache.indexes {
+		idx.keys = map[string]map[string]struct{}{}
+		idx.values = map[string]string{}
+	}
+	for key, item := range thisCache.items {
+		item, err := thisCache.plainItem(item)
+		if err != nil {
+			continue
+		}
+		for _, idx := range thisCache.indexes {
+			idx.add(key, item)
+		}
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：将数据项加入索引
+ * 输入参数：数据项键名：key string, 数据项：item Item
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 index 类方法，墓碑数据项以及提取函数返回 false 的数据项不加入索引；
+ *           提取函数在恢复边界内调用，panic 时视为返回 false
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisIndex *index) add(key string, item Item) {
+	if item.negative() {
+		return
+	}
+	var indexValue string
+	var ok bool
+	thisIndex.owner.protect("index", key, func() error {
+		indexValue, ok = thisIndex.extract(item.Object)
+		return nil
+	})
+	if !ok {
+		return
+	}
+	keys, found := thisIndex.keys[indexValue]
+	if !found {
+		keys = map[string]struct{}{}
+		thisIndex.keys[indexValue] = keys
+	}
+	keys[key] = struct{}{}
+	thisIndex.values[key] = indexValue
+}
+
+/***************************************************************************************
+ * 功能描述：将数据项移出索引
+ * 输入参数：数据项键名：key string
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 index 类方法，索引值下没有数据项时删除该索引值
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisIndex *index) remove(key string) {
+	indexValue, found := thisIndex.values[key]
+	if !found {
+		return
+	}
+	delete(thisIndex.values, key)
+	keys := thisIndex.keys[indexValue]
+	delete(keys, key)
+	if len(keys) == 0 {
+		delete(thisIndex.keys, indexValue)
+	}
+}
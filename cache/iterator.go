@@ -0,0 +1,110 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：iterator.go
+ * 内容摘要：逐个读取数据项的迭代器。
+ * 其他说明：迭代器创建时复制键名，之后每次 Next 只短暂持有读锁读取一个数据项，调用者处理每个数据项
+ *           时不持有锁，可以长时间处理而不阻塞写入。读取到的是 Next 时的当前值，而不是创建时的值：
+ *           创建之后被删除或过期的数据项被跳过，值可能已被修改，创建之后新增的键名不会被遍历到。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+
+/***************************************************************************************/
+// 数据结构与常量
+
+type Iterator struct { // 数据项迭代器，不可在多个 goroutine 中同时使用
+	cacher *Cache      // 被遍历的缓存
+	keys   []string    // 创建时的键名
+	pos    int         // 下一个读取的键名位置
+	key    string      // 当前数据项的键名
+	value  interface{} // 当前数据项的值
+}
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：创建遍历所有未过期数据项的迭代器
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：迭代器，使用完毕后调用 Close
+ * 其他说明：该函数为 Cache 类方法，创建时复制键名(见 Keys)，顺序不确定
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) NewIterator() *Iterator {
+	return &Iterator{cacher: thisCache, keys: thisCache.Keys()}
+}
+
+/***************************************************************************************
+ * 功能描述：前进到下一个仍然存在且未过期的数据项
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：有下一个数据项为 true，遍历结束或已 Close 时为 false
+ * 其他说明：该函数为 Iterator 类方法，不调用 loader，不记录访问时间；
+ *           值的处理与 Get 相同(WithCopyOnGet 复制、WithSerializedValues 解码)，处理失败的数据项被跳过
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisIter *Iterator) Next() bool {
+	for thisIter.pos < len(thisIter.keys) {
+		key := thisIter.keys[thisIter.pos]
+		thisIter.pos++
+
+		thisIter.cacher.mux.RLock()
+		value, found, _ := thisIter.cacher.get(key)
+		thisIter.cacher.mux.RUnlock()
+		if !found {
+			continue
+		}
+		value, err := thisIter.cacher.readValue(value)
+		if err != nil {
+			continue
+		}
+		thisIter.key, thisIter.value = key, value
+		return true
+	}
+	thisIter.key, thisIter.value = "", nil
+	return false
+}
+
+/***************************************************************************************
+ * 功能描述：获取当前数据项
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：当前数据项的键名与值，Next 返回 false 之后为空
+ * 其他说明：该函数为 Iterator 类方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisIter *Iterator) Item() (string, interface{}) {
+	return thisIter.key, thisIter.value
+}
+
+/***************************************************************************************
+ * 功能描述：结束遍历并释放复制的键名
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Iterator 类方法，之后 Next 返回 false；可重复调用
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisIter *Iterator) Close() {
+	thisIter.keys = nil
+	thisIter.pos = 0
+	thisIter.key, thisIter.value = "", nil
+}
@@ -0,0 +1,59 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：iterator_test.go
+ * 内容摘要：迭代器测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"testing"
+)
+
+/***************************************************************************************
+ * 功能描述：测试迭代器读取 Next 时的当前值：创建后删除的数据项被跳过，修改的值读到新值，新增的键名不被遍历
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestIterator(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	cacher.Set("a", 1, 0)
+	cacher.Set("b", 2, 0)
+	cacher.Set("c", 3, 0)
+	iter := cacher.NewIterator()
+	defer iter.Close()
+	cacher.Delete("a")
+	cacher.Set("b", 20, 0)
+	cacher.Set("d", 4, 0)
+
+	got := map[string]interface{}{}
+	for iter.Next() {
+		key, value := iter.Item()
+		got[key] = value
+	}
+	if len(got) != 2 || got["b"] != 20 || got["c"] != 3 {
+		t.Errorf("iterated items = %v, want map[b:20 c:3]", got)
+	}
+	if key, value := iter.Item(); key != "" || value != nil {
+		t.Errorf("Item after the end = %q, %v, want empty", key, value)
+	}
+
+	closed := cacher.NewIterator()
+	closed.Close()
+	if closed.Next() {
+		t.Error("Next after Close returned true")
+	}
+}
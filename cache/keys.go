@@ -0,0 +1,96 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：keys.go
+ * 内容摘要：键名的校验与规范化。
+ * 其他说明：WithKeyValidator 设置的函数在所有接受键名的方法(Set、Get、Increment、Counter、GetOrLoad、
+ *           批量操作等)中统一调用，写入与查找使用同一个规范化后的键名，例如去除首尾空白后 " a " 与 "a"
+ *           为同一数据项。批量读取的返回结果以调用者传入的键名为键；DeletePrefix 与 SetNamespaceQuota
+ *           的前缀同样经过规范化。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+
+/***************************************************************************************/
+// 数据结构与常量
+
+// 校验并规范化键名，返回规范化后的键名，不合法时返回错误
+type KeyValidator func(key string) (normalized string, err error)
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：校验并规范化键名
+ * 输入参数：数据项键名：key string
+ * 输出参数：无
+ * 返 回 值：规范化后的键名，无 error 则为 nil
+ * 其他说明：该函数为 Cache 类方法，先调用 keyValidator，规范化后为空时返回 ErrKeyInvalid；
+ *           keyValidator 在恢复边界内调用，panic 时返回 ErrCallbackPanic
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) checkKey(key string) (string, error) {
+	if thisCache.keyValidator != nil {
+		var normalized string
+		if err := thisCache.protect("validate", key, func() (err error) {
+			normalized, err = thisCache.keyValidator(key)
+			return err
+		}); err != nil {
+			return "", err
+		}
+		key = normalized
+	}
+	if len(key) == 0 {
+		err := ErrKeyInvalid
+		return "", err
+	}
+	return key, nil
+}
+
+/***************************************************************************************
+ * 功能描述：批量校验并规范化键名
+ * 输入参数：数据项键名：keys []string
+ * 输出参数：无
+ * 返 回 值：与 keys 一一对应的规范化后的键名，不合法的键名对应空字符串
+ * 其他说明：该函数为 Cache 类方法，在获取锁之前调用，避免在锁内调用 keyValidator
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) checkKeys(keys []string) []string {
+	normalized := make([]string, len(keys))
+	for i, key := range keys {
+		normalized[i], _ = thisCache.checkKey(key)
+	}
+	return normalized
+}
+
+/***************************************************************************************
+ * 功能描述：规范化键名前缀
+ * 输入参数：键名前缀：prefix string
+ * 输出参数：无
+ * 返 回 值：规范化后的前缀
+ * 其他说明：该函数为 Cache 类方法，用于 DeletePrefix、SetNamespaceQuota；空前缀表示全部键名，原样返回；
+ *           keyValidator 拒绝前缀(例如要求完整的键名格式)时原样返回
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) normalizePrefix(prefix string) string {
+	if len(prefix) == 0 {
+		return prefix
+	}
+	if normalized, err := thisCache.checkKey(prefix); err == nil {
+		return normalized
+	}
+	return prefix
+}
@@ -0,0 +1,144 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：keys_test.go
+ * 内容摘要：键名校验与规范化测试。
+ * 其他说明：使用去除首尾空白并转为小写的 keyValidator，各方法传入 " A " 与 "a" 应访问同一数据项。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：创建使用去除空白并转为小写的 keyValidator 的缓存
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：缓存
+ * 其他说明：包含 "bad" 的键名被拒绝
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func newKeyCache(t *testing.T) *Cache {
+	cacher, err := NewCache(0, 0, WithKeyValidator(func(key string) (string, error) {
+		if strings.Contains(key, "bad") {
+			return "", ErrKeyInvalid
+		}
+		return strings.ToLower(strings.TrimSpace(key)), nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cacher
+}
+
+/***************************************************************************************
+ * 功能描述：测试单键方法统一规范化键名
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestKeyNormalization(t *testing.T) {
+	cacher := newKeyCache(t)
+	cacher.Set(" A ", 1, 0)
+	if n, err := cacher.Increment("a", 1); err != nil || n != 2 {
+		t.Fatalf("Increment(a) = %d, %v", n, err)
+	}
+	if n, err := cacher.IncrementWithExpiration(" A", 1, 0); err != nil || n != 3 {
+		t.Fatalf("IncrementWithExpiration( A) = %d, %v", n, err)
+	}
+	if previous, existed := cacher.Swap("a ", 10, 0); !existed || previous != 3 {
+		t.Fatalf("Swap = %v, %v", previous, existed)
+	}
+	if actual, loaded := cacher.LoadOrStore(" a", 20, 0); !loaded || actual != 10 {
+		t.Fatalf("LoadOrStore = %v, %v", actual, loaded)
+	}
+	if !cacher.SetKeepTTL("A", 11) {
+		t.Fatal("SetKeepTTL(A) = false")
+	}
+	if !cacher.Update(" A ", func(old interface{}, found bool) (interface{}, time.Duration, bool) {
+		return old.(int) + 1, 0, found
+	}) {
+		t.Fatal("Update( A ) = false")
+	}
+	value, version, found := cacher.GetWithVersion("A")
+	if !found || value != 12 {
+		t.Fatalf("GetWithVersion(A) = %v, %v", value, found)
+	}
+	if _, err := cacher.SetWithCAS(" a ", 13, 0, version); err != nil {
+		t.Fatalf("SetWithCAS( a ) = %v", err)
+	}
+	if value, err := cacher.GetOrCompute("A", 0, func() (interface{}, error) { return 0, nil }); err != nil || value != 13 {
+		t.Fatalf("GetOrCompute(A) = %v, %v", value, err)
+	}
+	if value, state := cacher.GetState(" A"); value != 13 || state != StateHit {
+		t.Fatalf("GetState( A) = %v, %v", value, state)
+	}
+	if got := cacher.Count(); got != 1 {
+		t.Fatalf("Count = %d, want 1", got)
+	}
+
+	if _, err := cacher.Increment("bad", 1); !errors.Is(err, ErrKeyInvalid) {
+		t.Fatalf("Increment(bad) error = %v, want ErrKeyInvalid", err)
+	}
+	if _, loaded := cacher.LoadOrStore("bad", 1, 0); loaded || cacher.Count() != 1 {
+		t.Fatal("LoadOrStore(bad) stored an invalid key")
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试计数器、批量操作与前缀操作统一规范化键名
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：批量读取的结果以调用者传入的键名为键
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestKeyNormalizationMulti(t *testing.T) {
+	cacher := newKeyCache(t)
+	cacher.Counter(" Hits ").Add(2)
+	if got := cacher.Counter("hits").Value(); got != 2 {
+		t.Fatalf("Counter(hits) = %d, want 2", got)
+	}
+
+	cacher.Set("user:1", "x", 0)
+	values := cacher.GetMulti([]string{" USER:1", "bad", "user:2"})
+	if len(values) != 1 || values[" USER:1"] != "x" {
+		t.Fatalf("GetMulti = %v", values)
+	}
+	loaded, err := cacher.GetOrLoadMulti([]string{"User:1", " USER:2 "}, func(missing []string) (map[string]interface{}, error) {
+		return map[string]interface{}{" USER:2 ": "y"}, nil
+	}, 0)
+	if err != nil || loaded["User:1"] != "x" || loaded[" USER:2 "] != "y" {
+		t.Fatalf("GetOrLoadMulti = %v, %v", loaded, err)
+	}
+	if value, _, _ := cacher.Get("user:2"); value != "y" {
+		t.Fatalf("loaded value stored under %v", cacher.SortedKeys())
+	}
+	if _, err = cacher.GetOrLoadMulti([]string{"bad"}, nil, 0); !errors.Is(err, ErrKeyInvalid) {
+		t.Fatalf("GetOrLoadMulti(bad) error = %v, want ErrKeyInvalid", err)
+	}
+	if got := cacher.DeletePrefix(" USER:"); got != 2 {
+		t.Fatalf("DeletePrefix( USER:) = %d, want 2", got)
+	}
+}
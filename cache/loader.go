@@ -0,0 +1,537 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：loader.go
+ * 内容摘要：未命中时通过 loader 加载数据项(read-through)，以及负缓存。
+ * 其他说明：loader 返回 ErrKeyNotFound 表示后端确实不存在该数据项，此时缓存存入一个
+ *           墓碑数据项，墓碑只在缓存内部使用，不会作为数据项的值返回给调用者。
+ *           启用 WithLoaderBackoff 时，loader 返回其它错误会存入错误墓碑，按指数退避的
+ *           时间内直接返回该错误，退避结束后只有一个调用者重试 loader。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+type LoaderFunc func(key string) (interface{}, error) // 未命中时加载数据项的函数
+
+var errLoadPanicked = errors.New("cache load panicked.") // 加载函数 panic 时等待者收到的错误
+
+var errNotLoaded = errors.New("cache loader found nothing.") // Get 的 loader 返回 ErrKeyNotFound，只在 loads 内部传递
+
+type tombstone struct{} // 墓碑数据项，表示后端不存在该数据项
+
+type loadError struct { // 错误墓碑数据项，表示 loader 加载失败
+	err      error // loader 最近一次返回的错误
+	attempts int   // 连续失败的次数
+	retryAt  int64 // 允许重试的时间(Unix时间戳，单位纳秒)
+}
+
+type loadCall struct { // 正在进行的加载，同一 key 的并发调用者等待同一次加载
+	done  sync.WaitGroup // 加载完成时 Done
+	value interface{}    // 加载的值
+	found bool           // 批量加载时 loader 是否返回了该 key
+	err   error          // 加载的错误
+}
+
+type loadCalls map[string]*loadCall // 正在进行的加载，key 为数据项键名
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：判断数据项是否为墓碑数据项
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：墓碑数据项或错误墓碑数据项为true
+ * 其他说明：该函数为 Item 类方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisItem Item) negative() bool {
+	switch thisItem.Object.(type) {
+	case tombstone, loadError:
+		return true
+	}
+	return false
+}
+
+/***************************************************************************************
+ * 功能描述：调用 loader 加载数据项并存入缓存，合并同一 key 的并发加载
+ * 输入参数：数据项键名：key string
+ * 输出参数：无
+ * 返 回 值：具体数据项的值，是否找到(bool)，以及 error
+ * 其他说明：该函数为 Cache 类方法，同一 key 的并发未命中只调用一次 loader，其它调用者等待并得到相同的结果；
+ *           等待期间上一次加载已经完成并存入的，直接返回缓存中的值
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) load(key string) (interface{}, bool, error) {
+	value, err := thisCache.coalesce(&thisCache.loads, key, func() (interface{}, error) {
+		if item, found := thisCache.access(key); found && !item.negative() { // 上一次加载刚刚完成
+			return thisCache.decodeValue(item.Object)
+		}
+		value, found, err := thisCache.loadOnce(key)
+		if err == nil && !found {
+			return nil, errNotLoaded
+		}
+		return value, err
+	})
+	if err == errNotLoaded {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+/***************************************************************************************
+ * 功能描述：调用 loader 加载数据项并存入缓存
+ * 输入参数：数据项键名：key string
+ * 输出参数：无
+ * 返 回 值：具体数据项的值，是否找到(bool)，以及 error
+ * 其他说明：该函数为 Cache 类方法，由 load 在合并后调用，调用 loader 时不持有锁；
+ *           loader 返回 ErrKeyNotFound 时按 negativeTTL 存入墓碑数据项并返回未命中；
+ *           启用退避时，退避期内直接返回上次的错误，loader 返回其它错误时存入错误墓碑
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) loadOnce(key string) (interface{}, bool, error) {
+	if thisCache.backoffBase > 0 {
+		if err := thisCache.claimRetry(key); err != nil {
+			return nil, false, err
+		}
+	}
+	var value interface{}
+	err := thisCache.protect("loader", key, func() (err error) {
+		value, err = thisCache.loader(key)
+		return err
+	})
+	if errors.Is(err, ErrKeyNotFound) {
+		if thisCache.negativeTTL > 0 && thisCache.lockWithTimeout() == nil {
+			thisCache.set(key, tombstone{}, thisCache.negativeTTL)
+			thisCache.unlock()
+		}
+		return nil, false, nil
+	}
+	if err != nil {
+		thisCache.logger.Errorf("cache loader %s: %v", key, err)
+		if thisCache.backoffBase > 0 && thisCache.lockWithTimeout() == nil {
+			thisCache.storeLoadError(key, err)
+			thisCache.unlock()
+		}
+		return nil, false, err
+	}
+	if thisCache.lockWithTimeout() != nil { // 未能写入缓存，仍返回加载的值
+		return value, true, nil
+	}
+	evicted, reason := thisCache.overwrite(key, value, DefaultExpiration)
+	onEvicted := thisCache.onEvicted
+	thisCache.unlock()
+
+	fireEvicted(onEvicted, evicted, reason)
+	return value, true, nil
+}
+
+/***************************************************************************************
+ * 功能描述：检查错误墓碑的退避时间，退避结束时由当前调用者占用本次重试
+ * 输入参数：数据项键名：key string
+ * 输出参数：无
+ * 返 回 值：仍在退避期内时返回上次的错误，否则为 nil
+ * 其他说明：该函数为 Cache 类方法；占用重试时预先按失败推进退避时间，
+ *           重试期间其它调用者仍直接返回上次的错误，重试成功后错误墓碑被新值覆盖
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) claimRetry(key string) error {
+	now := nanotime()
+	if err := thisCache.lockWithTimeout(); err != nil {
+		return err
+	}
+	defer thisCache.unlock()
+
+	item, found := thisCache.items[key]
+	if !found || thisCache.expired(item, now) {
+		return nil
+	}
+	failure, ok := item.Object.(loadError)
+	if !ok {
+		return nil
+	}
+	if now < failure.retryAt {
+		return failure.err
+	}
+	failure.attempts++
+	backoff := thisCache.backoff(failure.attempts)
+	failure.retryAt = now + int64(backoff)
+	thisCache.set(key, failure, backoff+thisCache.backoffMax)
+	return nil
+}
+
+/***************************************************************************************
+ * 功能描述：存入错误墓碑，调用时须持有写锁
+ * 输入参数：数据项键名：key string, loader 返回的错误：err error
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法；加载期间已被写入新值时不覆盖；
+ *           错误墓碑在退避结束后再保留 backoffMax，期间再次失败时退避时间继续翻倍
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) storeLoadError(key string, err error) {
+	now := nanotime()
+	failure := loadError{err: err, attempts: 1}
+	if item, found := thisCache.items[key]; found && !thisCache.expired(item, now) {
+		previous, ok := item.Object.(loadError)
+		if !ok {
+			return
+		}
+		failure.attempts = previous.attempts // claimRetry 已推进过次数
+	}
+	backoff := thisCache.backoff(failure.attempts)
+	failure.retryAt = now + int64(backoff)
+	thisCache.set(key, failure, backoff+thisCache.backoffMax)
+}
+
+/***************************************************************************************
+ * 功能描述：计算第 attempts 次失败后的退避时间
+ * 输入参数：连续失败的次数：attempts int
+ * 输出参数：无
+ * 返 回 值：退避时间，base * 2^(attempts-1)，不超过 backoffMax
+ * 其他说明：该函数为 Cache 类方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) backoff(attempts int) time.Duration {
+	backoff := thisCache.backoffBase
+	for i := 1; i < attempts && backoff < thisCache.backoffMax; i++ {
+		backoff *= 2
+	}
+	if backoff > thisCache.backoffMax {
+		backoff = thisCache.backoffMax
+	}
+	return backoff
+}
+
+/***************************************************************************************
+ * 功能描述：获取数据项，未命中时调用 fn 计算并存入缓存(cache-aside)
+ * 输入参数：数据项键名：key string, 数据项生命周期：dur time.Duration,
+ *           计算函数：fn func() (interface{}, error)
+ * 输出参数：无
+ * 返 回 值：数据项的值，无 error 则为 nil
+ * 其他说明：该函数为 Cache 类方法，调用 fn 时不持有锁；fn 返回错误时不缓存，直接返回该错误
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) GetOrCompute(key string, dur time.Duration, fn func() (interface{}, error)) (interface{}, error) {
+	if thisCache.closed.Load() {
+		return nil, ErrCacheClosed
+	}
+	key, err := thisCache.checkKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if item, found := thisCache.access(key); found && !item.negative() {
+		return thisCache.readValue(item.Object)
+	}
+
+	var value interface{}
+	err = thisCache.protect("compute", key, func() (err error) {
+		value, err = fn()
+		return err
+	})
+	if err != nil {
+		thisCache.logger.Errorf("cache compute %s: %v", key, err)
+		return nil, err
+	}
+	if err = thisCache.checkValueSize(value); err != nil {
+		return nil, err
+	}
+	if thisCache.lockWithTimeout() != nil { // 未能写入缓存，仍返回计算的值
+		return thisCache.copyValue(value)
+	}
+	evicted, reason := thisCache.overwrite(key, value, dur)
+	onEvicted := thisCache.onEvicted
+	thisCache.unlock()
+
+	fireEvicted(onEvicted, evicted, reason)
+	return thisCache.copyValue(value)
+}
+
+/***************************************************************************************
+ * 功能描述：获取数据项并刷新其过期时间，未命中时调用 fn 计算并存入缓存
+ * 输入参数：数据项键名：key string, 数据项生命周期：dur time.Duration,
+ *           计算函数：fn func() (interface{}, error)
+ * 输出参数：无
+ * 返 回 值：数据项的值，无 error 则为 nil
+ * 其他说明：该函数为 Cache 类方法，命中时与 GetAndTouch 相同，过期时间从本次访问起重新计算，
+ *           频繁读取的数据项一直保留，空闲的数据项按 dur 过期；不会重新调用 fn。
+ *           未命中时与 GetOrCompute 相同，新值的生命周期为 dur
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) GetOrRenew(key string, dur time.Duration, fn func() (interface{}, error)) (interface{}, error) {
+	if thisCache.closed.Load() {
+		return nil, ErrCacheClosed
+	}
+	key, err := thisCache.checkKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if err = thisCache.lockWithTimeout(); err != nil {
+		return nil, err
+	}
+	item, found := thisCache.touch(key, dur)
+	thisCache.unlock()
+	if found {
+		return thisCache.readValue(item.Object)
+	}
+	return thisCache.GetOrCompute(key, dur, fn)
+}
+
+/***************************************************************************************
+ * 功能描述：获取数据项，未命中时调用 loader 加载，由 loader 决定数据项的生命周期
+ * 输入参数：数据项键名：key string,
+ *           加载函数：loader func() (value interface{}, ttl time.Duration, err error)
+ * 输出参数：无
+ * 返 回 值：数据项的值，无 error 则为 nil
+ * 其他说明：该函数为 Cache 类方法，同一 key 的并发未命中只调用一次 loader，其它调用者等待其结果；
+ *           ttl 为 DefaultExpiration 时使用默认过期时间，为 NoExpiration 时永不过期；
+ *           loader 返回错误时不缓存，所有等待者都返回该错误
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) GetOrLoad(key string, loader func() (value interface{}, ttl time.Duration, err error)) (interface{}, error) {
+	if thisCache.closed.Load() {
+		return nil, ErrCacheClosed
+	}
+	key, err := thisCache.checkKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if item, found := thisCache.access(key); found && !item.negative() {
+		return thisCache.readValue(item.Object)
+	}
+
+	value, err := thisCache.coalesce(&thisCache.calls, key, func() (interface{}, error) {
+		if item, found := thisCache.access(key); found && !item.negative() { // 上一次加载刚刚完成
+			return thisCache.decodeValue(item.Object)
+		}
+		var value interface{}
+		var ttl time.Duration
+		err := thisCache.protect("load", key, func() (err error) {
+			value, ttl, err = loader()
+			return err
+		})
+		if err != nil {
+			thisCache.logger.Errorf("cache load %s: %v", key, err)
+			return nil, err
+		}
+		if err = thisCache.checkValueSize(value); err != nil {
+			return nil, err
+		}
+		if thisCache.lockWithTimeout() != nil { // 未能写入缓存，仍返回加载的值
+			return value, nil
+		}
+		evicted, reason := thisCache.overwrite(key, value, ttl)
+		onEvicted := thisCache.onEvicted
+		thisCache.unlock()
+
+		fireEvicted(onEvicted, evicted, reason)
+		return value, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return thisCache.copyValue(value)
+}
+
+/***************************************************************************************
+ * 功能描述：获取数据项，未命中时调用 fn 计算并存入缓存，并返回是否由本次调用计算
+ * 输入参数：数据项键名：key string, 数据项生命周期：dur time.Duration, 计算函数：fn func() interface{}
+ * 输出参数：无
+ * 返 回 值：value 数据项的值，computed 为 true 表示由本次调用执行 fn 计算
+ * 其他说明：该函数为 Cache 类方法，与 LoadOrStore 类似但值由 fn 计算；fn 不返回错误，适用于纯计算。
+ *           同一 key 的并发未命中只调用一次 fn，只有执行 fn 的调用者得到 computed 为 true；
+ *           值超过 maxValueBytes 时返回计算结果但不存入；key 为空或 fn panic 时返回 (nil, false)
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) GetOrSetFunc(key string, dur time.Duration, fn func() interface{}) (value interface{}, computed bool) {
+	if thisCache.closed.Load() {
+		return nil, false
+	}
+	key, err := thisCache.checkKey(key)
+	if err != nil {
+		return nil, false
+	}
+	if item, found := thisCache.access(key); found && !item.negative() {
+		value, err := thisCache.readValue(item.Object)
+		if err != nil {
+			return nil, false
+		}
+		return value, false
+	}
+
+	value, err = thisCache.coalesce(&thisCache.calls, key, func() (interface{}, error) {
+		if item, found := thisCache.access(key); found && !item.negative() { // 上一次加载刚刚完成
+			return thisCache.decodeValue(item.Object)
+		}
+		var value interface{}
+		if err := thisCache.protect("compute", key, func() error {
+			value = fn()
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		computed = true
+		if thisCache.checkValueSize(value) != nil {
+			return value, nil
+		}
+		if thisCache.lockWithTimeout() != nil { // 未能写入缓存，仍返回计算的值
+			return value, nil
+		}
+		evicted, reason := thisCache.overwrite(key, value, dur)
+		onEvicted := thisCache.onEvicted
+		thisCache.unlock()
+
+		fireEvicted(onEvicted, evicted, reason)
+		return value, nil
+	})
+	if err != nil {
+		return nil, false
+	}
+	if value, err = thisCache.copyValue(value); err != nil {
+		return nil, false
+	}
+	return value, computed
+}
+
+/***************************************************************************************
+ * 功能描述：合并同一 key 的并发加载
+ * 输入参数：正在进行的加载：calls *loadCalls, 数据项键名：key string, 加载函数：fn func() (interface{}, error)
+ * 输出参数：无
+ * 返 回 值：加载的值，无 error 则为 nil
+ * 其他说明：该函数为 Cache 类方法，key 在 calls 中已有正在进行的加载时等待其结果，否则由当前调用者执行 fn；
+ *           调用 fn 时不持有任何锁；不同的 calls 之间互不合并
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) coalesce(calls *loadCalls, key string, fn func() (interface{}, error)) (interface{}, error) {
+	thisCache.callMux.Lock()
+	if *calls == nil {
+		*calls = make(loadCalls)
+	}
+	if call, found := (*calls)[key]; found {
+		thisCache.callMux.Unlock()
+		call.done.Wait()
+		return call.value, call.err
+	}
+	call := new(loadCall)
+	call.done.Add(1)
+	(*calls)[key] = call
+	thisCache.callMux.Unlock()
+
+	defer func() { // fn panic 时同样唤醒等待者
+		call.done.Done()
+		thisCache.callMux.Lock()
+		delete(*calls, key)
+		thisCache.callMux.Unlock()
+	}()
+	call.err = errLoadPanicked
+	call.value, call.err = fn()
+	return call.value, call.err
+}
+
+/***************************************************************************************
+ * 功能描述：为批量加载登记键名，已有正在进行的加载的键名改为等待
+ * 输入参数：数据项键名：keys []string
+ * 输出参数：无
+ * 返 回 值：由当前调用者加载的键名及其登记的加载，需要等待其它调用者的加载
+ * 其他说明：该函数为 Cache 类方法，登记的加载须通过 finishLoads 完成
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) claimLoads(keys []string) (owned []string, calls []*loadCall, waits map[string]*loadCall) {
+	thisCache.callMux.Lock()
+	defer thisCache.callMux.Unlock()
+	if thisCache.batchLoads == nil {
+		thisCache.batchLoads = make(loadCalls)
+	}
+	for _, key := range keys {
+		if call, found := thisCache.batchLoads[key]; found {
+			if waits == nil {
+				waits = make(map[string]*loadCall)
+			}
+			waits[key] = call
+			continue
+		}
+		call := new(loadCall)
+		call.done.Add(1)
+		thisCache.batchLoads[key] = call
+		owned = append(owned, key)
+		calls = append(calls, call)
+	}
+	return owned, calls, waits
+}
+
+/***************************************************************************************
+ * 功能描述：完成 claimLoads 登记的加载并唤醒等待者
+ * 输入参数：登记的键名：keys []string, 登记的加载：calls []*loadCall,
+ *           加载的数据项：loaded map[string]interface{}, 加载的错误：err error
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，loaded 中没有的键名以未找到完成
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) finishLoads(keys []string, calls []*loadCall, loaded map[string]interface{}, err error) {
+	thisCache.callMux.Lock()
+	for i, key := range keys {
+		delete(thisCache.batchLoads, key)
+		calls[i].value, calls[i].found = loaded[key]
+		calls[i].err = err
+	}
+	thisCache.callMux.Unlock()
+	for _, call := range calls {
+		call.done.Done()
+	}
+}
@@ -0,0 +1,374 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：loader_test.go
+ * 内容摘要：loader 与加载合并测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：测试 Get 的并发未命中只调用一次 loader
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：loader 返回前所有调用者都已未命中，等待者得到相同的值
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestLoaderCoalesce(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	cacher, _ := NewCache(0, 0, WithLoader(func(key string) (interface{}, error) {
+		calls.Add(1)
+		<-release
+		return "v:" + key, nil
+	}))
+	var wg sync.WaitGroup
+	results := make([]interface{}, 16)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _, _ = cacher.Get("k")
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if got := calls.Load(); got != 1 {
+		t.Errorf("loader calls = %d, want 1", got)
+	}
+	for i, value := range results {
+		if value != "v:k" {
+			t.Errorf("result %d = %v, want v:k", i, value)
+		}
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 GetOrLoadMulti 的并发调用只加载一次同一个 key
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：第二个调用者只把第一个调用者未在加载的键名交给 loader
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestGetOrLoadMultiCoalesce(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	release := make(chan struct{})
+	var mux sync.Mutex
+	requested := map[string]int{}
+	loader := func(missing []string) (map[string]interface{}, error) {
+		mux.Lock()
+		for _, key := range missing {
+			requested[key]++
+		}
+		mux.Unlock()
+		<-release
+		loaded := make(map[string]interface{}, len(missing))
+		for _, key := range missing {
+			loaded[key] = "v:" + key
+		}
+		return loaded, nil
+	}
+	var wg sync.WaitGroup
+	results := make([]map[string]interface{}, 2)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0], _ = cacher.GetOrLoadMulti([]string{"a", "b"}, loader, 0)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[1], _ = cacher.GetOrLoadMulti([]string{"b", "c"}, loader, 0)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	for key, n := range requested {
+		if n != 1 {
+			t.Errorf("%s loaded %d times, want 1", key, n)
+		}
+	}
+	if len(requested) != 3 {
+		t.Errorf("requested = %v, want a, b and c once each", requested)
+	}
+	if results[1]["b"] != "v:b" || results[1]["c"] != "v:c" || results[0]["a"] != "v:a" {
+		t.Errorf("results = %v", results)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 loader 返回 ErrKeyNotFound 后墓碑数据项在 negativeTTL 内挡住重复加载
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：前移 clockWall 模拟时间流逝；未设置 WithNegativeTTL 时每次未命中都调用 loader
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestNegativeTTL(t *testing.T) {
+	saved := clockWall
+	defer func() { clockWall = saved }()
+
+	var calls atomic.Int32
+	loader := func(key string) (interface{}, error) {
+		calls.Add(1)
+		return nil, ErrKeyNotFound
+	}
+	cacher, _ := NewCache(0, 0, WithLoader(loader), WithNegativeTTL(time.Minute))
+	for i := 0; i < 3; i++ {
+		if value, found, err := cacher.Get("missing"); found || err != nil {
+			t.Fatalf("Get = %v, %v, %v, want a miss", value, found, err)
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("loader calls within negative TTL = %d, want 1", got)
+	}
+	clockWall += int64(time.Minute + time.Second)
+	cacher.Get("missing")
+	if got := calls.Load(); got != 2 {
+		t.Errorf("loader calls after negative TTL = %d, want 2", got)
+	}
+
+	calls.Store(0)
+	uncached, _ := NewCache(0, 0, WithLoader(loader))
+	uncached.Get("missing")
+	uncached.Get("missing")
+	if got := calls.Load(); got != 2 {
+		t.Errorf("loader calls without negative TTL = %d, want 2", got)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 loader 失败后退避时间翻倍增长、不超过上限，加载成功后重置
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：前移 clockWall 模拟时间流逝；退避期内 Get 直接返回上次的错误
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestLoaderBackoff(t *testing.T) {
+	saved := clockWall
+	defer func() { clockWall = saved }()
+
+	boom := errors.New("boom")
+	var calls atomic.Int32
+	var healthy atomic.Bool
+	cacher, _ := NewCache(0, 0, WithLoaderBackoff(time.Second, 4*time.Second), WithLoader(func(key string) (interface{}, error) {
+		calls.Add(1)
+		if healthy.Load() {
+			return "v", nil
+		}
+		return nil, boom
+	}))
+	step := func(advance time.Duration, wantCalls int32) {
+		t.Helper()
+		clockWall += int64(advance)
+		if _, _, err := cacher.Get("k"); !healthy.Load() && !errors.Is(err, boom) {
+			t.Fatalf("Get err = %v, want boom", err)
+		}
+		if got := calls.Load(); got != wantCalls {
+			t.Fatalf("after +%v: loader calls = %d, want %d", advance, got, wantCalls)
+		}
+	}
+	step(0, 1)                     // 第 1 次失败，退避 1s
+	step(500*time.Millisecond, 1)  // 退避期内
+	step(600*time.Millisecond, 2)  // 第 2 次失败，退避 2s
+	step(1500*time.Millisecond, 2) // 退避期内
+	step(600*time.Millisecond, 3)  // 第 3 次失败，退避 4s
+	step(3*time.Second, 3)         // 退避期内
+	step(1100*time.Millisecond, 4) // 第 4 次失败，退避不超过 4s
+	step(3900*time.Millisecond, 4) // 退避期内
+	healthy.Store(true)
+	step(200*time.Millisecond, 5) // 加载成功
+	if value, found, err := cacher.Get("k"); !found || value != "v" || err != nil {
+		t.Fatalf("Get after recovery = %v, %v, %v", value, found, err)
+	}
+
+	healthy.Store(false)
+	cacher.Delete("k")
+	step(0, 6) // 成功后重置，退避回到 1s
+	step(1100*time.Millisecond, 7)
+}
+
+/***************************************************************************************
+ * 功能描述：测试 GetOrLoad 使用 loader 返回的生命周期，并合并同一 key 的并发未命中
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：NoExpiration 使数据项永不过期；loader 返回错误时所有等待者都得到该错误且不缓存
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestGetOrLoad(t *testing.T) {
+	cacher, _ := NewCache(time.Hour, 0)
+	load := func(value interface{}, ttl time.Duration) func() (interface{}, time.Duration, error) {
+		return func() (interface{}, time.Duration, error) { return value, ttl, nil }
+	}
+	cacher.GetOrLoad("short", load(1, time.Minute))
+	cacher.GetOrLoad("pinned", load(2, NoExpiration))
+	cacher.GetOrLoad("default", load(3, DefaultExpiration))
+	ttls := cacher.Expirations()
+	if ttls["short"] <= 59*time.Second || ttls["short"] > time.Minute {
+		t.Errorf("short ttl = %v, want about 1m", ttls["short"])
+	}
+	if ttls["pinned"] != NoExpiration {
+		t.Errorf("pinned ttl = %v, want NoExpiration", ttls["pinned"])
+	}
+	if ttls["default"] <= 59*time.Minute {
+		t.Errorf("default ttl = %v, want about 1h", ttls["default"])
+	}
+	if value, _ := cacher.GetOrLoad("short", load(100, time.Minute)); value != 1 {
+		t.Errorf("GetOrLoad on a hit = %v, want the cached 1", value)
+	}
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	boom := errors.New("boom")
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = cacher.GetOrLoad("k", func() (interface{}, time.Duration, error) {
+				calls.Add(1)
+				<-release
+				return nil, 0, boom
+			})
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if got := calls.Load(); got != 1 {
+		t.Errorf("loader calls = %d, want 1", got)
+	}
+	for i, err := range errs {
+		if !errors.Is(err, boom) {
+			t.Errorf("caller %d err = %v, want boom", i, err)
+		}
+	}
+	if cacher.Has("k") {
+		t.Error("failed load was cached")
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 GetOrRenew 命中时从本次访问起重新计算生命周期且不调用 fn，空闲的数据项按 dur 过期
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：与 GetOrCompute 对比：GetOrCompute 命中时不刷新；前移 clockWall 模拟时间流逝
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestGetOrRenew(t *testing.T) {
+	saved := clockWall
+	defer func() { clockWall = saved }()
+
+	cacher, _ := NewCache(0, 0)
+	var calls atomic.Int32
+	compute := func() (interface{}, error) {
+		return calls.Add(1), nil
+	}
+	cacher.GetOrRenew("renewed", time.Minute, compute)
+	cacher.GetOrCompute("computed", time.Minute, compute)
+	for i := 0; i < 3; i++ {
+		clockWall += int64(40 * time.Second)
+		if value, _ := cacher.GetOrRenew("renewed", time.Minute, compute); value != int32(1) {
+			t.Fatalf("GetOrRenew after %ds = %v, want the first computed value", (i+1)*40, value)
+		}
+	}
+	if value, _ := cacher.GetOrCompute("computed", time.Minute, compute); value != int32(3) {
+		t.Errorf("GetOrCompute after 120s = %v, want a recomputed value", value)
+	}
+	clockWall += int64(61 * time.Second)
+	if value, _ := cacher.GetOrRenew("renewed", time.Minute, compute); value != int32(4) {
+		t.Errorf("GetOrRenew after 61s idle = %v, want a recomputed value", value)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 GetOrSetFunc：命中时不调用 fn，并发未命中只调用一次 fn 且只有一个调用者得到 computed 为 true
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：fn 返回前所有调用者都已未命中；fn panic 时返回 (nil, false) 且不存入
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestGetOrSetFunc(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	var calls atomic.Int32
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	var computedCount atomic.Int32
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, computed := cacher.GetOrSetFunc("k", NoExpiration, func() interface{} {
+				calls.Add(1)
+				<-release
+				return "v"
+			})
+			if value != "v" {
+				t.Errorf("GetOrSetFunc = %v, want v", value)
+			}
+			if computed {
+				computedCount.Add(1)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if calls.Load() != 1 || computedCount.Load() != 1 {
+		t.Fatalf("fn calls = %d, computed = %d, want 1 and 1", calls.Load(), computedCount.Load())
+	}
+	if value, computed := cacher.GetOrSetFunc("k", NoExpiration, func() interface{} { return "other" }); value != "v" || computed {
+		t.Fatalf("GetOrSetFunc on hit = (%v, %v), want (v, false)", value, computed)
+	}
+
+	if value, computed := cacher.GetOrSetFunc("panic", NoExpiration, func() interface{} { panic("boom") }); value != nil || computed {
+		t.Errorf("GetOrSetFunc with panicking fn = (%v, %v), want (nil, false)", value, computed)
+	}
+	if _, found, _ := cacher.Get("panic"); found {
+		t.Error("panicking fn stored a value")
+	}
+}
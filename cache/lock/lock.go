@@ -0,0 +1,112 @@
+package lock
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：lock.go
+ * 内容摘要：基于缓存的带过期时间的互斥锁。
+ * 其他说明：加锁时以随机 token 为值、以 ttl 为生命周期写入锁数据项，数据项已存在时加锁失败；
+ *           解锁时只删除 token 一致的锁数据项，避免释放其它持有者的锁；
+ *           持有者崩溃或忘记解锁时，锁数据项按 ttl 过期。
+ *           缓存位于进程内存中，该锁只在单个进程内有效，且为尽力而为：
+ *           持有者的操作超过 ttl 时锁会过期并被其它调用者获得。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"go-libcache/cache"
+	"time"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+type Locker struct { // 锁结构
+	cacher *cache.Cache // 存储锁数据项的缓存
+}
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：创建一个锁
+ * 输入参数：存储锁数据项的缓存：cacher *cache.Cache
+ * 输出参数：无
+ * 返 回 值：一个新的锁
+ * 其他说明：锁数据项与缓存中的其它数据项共用键空间，建议为 key 加上前缀
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func NewLocker(cacher *cache.Cache) *Locker {
+	return &Locker{cacher: cacher}
+}
+
+/***************************************************************************************
+ * 功能描述：加锁
+ * 输入参数：锁的键名：key string, 锁的生命周期：ttl time.Duration
+ * 输出参数：无
+ * 返 回 值：解锁使用的 token，以及是否加锁成功(bool)
+ * 其他说明：该函数为 Locker 类方法，不阻塞，锁已被持有时直接返回 false；ttl 必须大于 0
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisLocker *Locker) Acquire(key string, ttl time.Duration) (token string, ok bool) {
+	if len(key) == 0 || ttl <= 0 {
+		return "", false
+	}
+	token, err := newToken()
+	if err != nil {
+		return "", false
+	}
+	if _, err = thisLocker.cacher.SetWithCAS(key, token, ttl, 0); err != nil {
+		return "", false
+	}
+	return token, true
+}
+
+/***************************************************************************************
+ * 功能描述：解锁
+ * 输入参数：锁的键名：key string, 加锁时返回的 token：token string
+ * 输出参数：无
+ * 返 回 值：解锁成功为 true，锁已过期或已被其它调用者持有时为 false
+ * 其他说明：该函数为 Locker 类方法，比较 token 与删除基于版本号，期间锁被重新获得时不会误删
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisLocker *Locker) Release(key, token string) bool {
+	value, version, found := thisLocker.cacher.GetWithVersion(key)
+	if !found || value != token {
+		return false
+	}
+	return thisLocker.cacher.DeleteWithCAS(key, version) == nil
+}
+
+/***************************************************************************************
+ * 功能描述：生成随机 token
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：16 字节随机数的 16 进制字符串，无 error 则为 nil
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func newToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
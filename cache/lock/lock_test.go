@@ -0,0 +1,136 @@
+package lock
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：lock_test.go
+ * 内容摘要：基于缓存的锁测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"go-libcache/cache"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：测试加锁与解锁
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：持有期间再次加锁失败，错误的 token 不能解锁
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestAcquireRelease(t *testing.T) {
+	cacher, _ := cache.NewCache(0, 0)
+	locker := NewLocker(cacher)
+
+	token, ok := locker.Acquire("job", time.Minute)
+	if !ok || len(token) == 0 {
+		t.Fatalf("Acquire = %q, %v, want a token", token, ok)
+	}
+	if _, ok = locker.Acquire("job", time.Minute); ok {
+		t.Error("second Acquire succeeded while held")
+	}
+	if locker.Release("job", "other") {
+		t.Error("Release with a wrong token succeeded")
+	}
+	if !locker.Release("job", token) {
+		t.Error("Release with the owner token failed")
+	}
+	if locker.Release("job", token) {
+		t.Error("second Release succeeded")
+	}
+	if _, ok = locker.Acquire("job", time.Minute); !ok {
+		t.Error("Acquire after Release failed")
+	}
+	if _, ok = locker.Acquire("", time.Minute); ok {
+		t.Error("Acquire with an empty key succeeded")
+	}
+	if _, ok = locker.Acquire("x", 0); ok {
+		t.Error("Acquire with a zero ttl succeeded")
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试锁过期后可被重新获取，原持有者不能再解锁
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestAcquireExpired(t *testing.T) {
+	cacher, _ := cache.NewCache(0, 0)
+	locker := NewLocker(cacher)
+
+	stale, _ := locker.Acquire("job", 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	token, ok := locker.Acquire("job", time.Minute)
+	if !ok {
+		t.Fatal("Acquire after expiry failed")
+	}
+	if locker.Release("job", stale) {
+		t.Error("Release with the expired token succeeded")
+	}
+	if !locker.Release("job", token) {
+		t.Error("Release with the new token failed")
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试并发争用时同一时刻最多只有一个持有者
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：需配合 -race 运行
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestAcquireContention(t *testing.T) {
+	cacher, _ := cache.NewCache(0, 0)
+	locker := NewLocker(cacher)
+
+	var holders, acquired atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < 200; n++ {
+				token, ok := locker.Acquire("job", time.Minute)
+				if !ok {
+					continue
+				}
+				if holders.Add(1) != 1 {
+					t.Error("two holders at once")
+				}
+				acquired.Add(1)
+				holders.Add(-1)
+				if !locker.Release("job", token) {
+					t.Error("Release by the holder failed")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if acquired.Load() == 0 {
+		t.Error("lock never acquired")
+	}
+}
@@ -0,0 +1,110 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：lockwait.go
+ * 内容摘要：带超时地获取写锁，用于发现长时间持有写锁造成的阻塞。
+ * 其他说明：通过 WithLockTimeout 启用后，所有写入数据项的方法在超时内未获取到写锁时放弃：
+ *           返回 error 的方法返回 ErrLockTimeout，返回 bool 或数量的方法返回 false 或 0，
+ *           并累加 LockStalls；gcLoop 的定期清理超时时跳过本轮，Get 记录访问时间超时时不记录。
+ *           只有 SetDefaultExpiration、AddIndex、Subscribe、CloseLog 等配置方法与后台预热一直等待。
+ *           sync.RWMutex 不支持带超时的 Lock，因此写者先在容量为 1 的 writers 通道中取得令牌，
+ *           取令牌时在 timer 上 select，超时的写者直接返回，不留下任何排队的 goroutine；
+ *           取得令牌后调用 Lock 只需等待当前的读者，并阻止新的读者进入，读者不会使写者饿死。
+ *           unlock 释放写锁后归还令牌。超时只计算等待其它写者的时间。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：获取写锁，启用 WithLockTimeout 时一直等待
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，用于不能放弃写入的配置方法，调用者须通过 unlock 释放写锁
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) lock() {
+	if thisCache.writers != nil {
+		thisCache.writers <- struct{}{}
+	}
+	thisCache.mux.Lock()
+}
+
+/***************************************************************************************
+ * 功能描述：获取写锁，启用 WithLockTimeout 时最多等待 lockWait
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：获取到写锁时为 nil，超时返回 ErrLockTimeout
+ * 其他说明：该函数为 Cache 类方法，返回 nil 时调用者须通过 unlock 释放写锁；
+ *           超时的调用者没有占用令牌，也没有留下等待写锁的 goroutine
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) lockWithTimeout() error {
+	if thisCache.writers == nil {
+		thisCache.mux.Lock()
+		return nil
+	}
+	select {
+	case thisCache.writers <- struct{}{}:
+	default:
+		timer := time.NewTimer(thisCache.lockWait)
+		defer timer.Stop()
+		select {
+		case thisCache.writers <- struct{}{}:
+		case <-timer.C:
+			thisCache.lockStalls.Add(1)
+			thisCache.logger.Errorf("cache lock: not acquired within %v", thisCache.lockWait)
+			return ErrLockTimeout
+		}
+	}
+	thisCache.mux.Lock()
+	return nil
+}
+
+/***************************************************************************************
+ * 功能描述：释放写锁并归还写者令牌，不发送事件
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，由 unlock 调用
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) release() {
+	thisCache.mux.Unlock()
+	if thisCache.writers != nil {
+		<-thisCache.writers
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：统计获取写锁超时的次数
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：int64 自缓存创建以来返回 ErrLockTimeout 的次数
+ * 其他说明：该函数为 Cache 类方法，不加锁；未启用 WithLockTimeout 时总为 0
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) LockStalls() int64 {
+	return thisCache.lockStalls.Load()
+}
@@ -0,0 +1,152 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：lockwait_test.go
+ * 内容摘要：带超时获取写锁的测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"bytes"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：测试写锁被长时间持有时写入方法返回 ErrLockTimeout
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：所有写入方法超时后放弃且不修改数据项，不留下等待写锁的 goroutine，之后的写入不受影响
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestLockTimeout(t *testing.T) {
+	cacher, _ := NewCache(0, 0, WithLockTimeout(20*time.Millisecond))
+	cacher.Set("a", 1, 0)
+
+	var snap bytes.Buffer
+	cacher.Save(&snap)
+	before := runtime.NumGoroutine()
+	cacher.lock()
+	checks := map[string]error{
+		"Set":           cacher.Set("b", 2, 0),
+		"Delete":        cacher.Delete("a"),
+		"DeleteExpired": cacher.DeleteExpired(),
+		"Load":          cacher.Load(bytes.NewReader(snap.Bytes())),
+		"Restore":       cacher.Restore(map[string]Item{}, false),
+	}
+	_, checks["Increment"] = cacher.Increment("a", 1)
+	rejected := map[string]bool{
+		"Swap":       func() bool { _, existed := cacher.Swap("a", 3, 0); return !existed }(),
+		"SetKeepTTL": !cacher.SetKeepTTL("a", 3),
+		"Touch":      !cacher.Touch("a", time.Minute),
+		"TouchMulti": cacher.TouchMulti([]string{"a"}, time.Minute) == 0,
+		"Counter":    cacher.Counter("a").Add(1) == 0,
+		"Update": !cacher.Update("a", func(old interface{}, found bool) (interface{}, time.Duration, bool) {
+			return 3, 0, true
+		}),
+	}
+	if got := runtime.NumGoroutine(); got > before {
+		t.Errorf("NumGoroutine = %d after timeouts, want at most %d", got, before)
+	}
+	cacher.unlock()
+	for name, err := range checks {
+		if !errors.Is(err, ErrLockTimeout) {
+			t.Errorf("%s: %v, want ErrLockTimeout", name, err)
+		}
+	}
+	for name, ok := range rejected {
+		if !ok {
+			t.Errorf("%s succeeded while the write lock was held", name)
+		}
+	}
+	if got, want := cacher.LockStalls(), int64(len(checks)+len(rejected)); got != want {
+		t.Errorf("LockStalls = %d, want %d", got, want)
+	}
+	if value, _, _ := cacher.Get("a"); value != 1 {
+		t.Errorf("a = %v after timeouts, want 1", value)
+	}
+
+	if err := cacher.Delete("a"); err != nil {
+		t.Fatalf("Delete after release: %v", err)
+	}
+	if _, found, _ := cacher.Get("a"); found {
+		t.Error("a not deleted")
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试写锁在超时前释放时写入成功
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestLockTimeoutReleased(t *testing.T) {
+	cacher, _ := NewCache(0, 0, WithLockTimeout(time.Second))
+	cacher.lock()
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cacher.unlock()
+	}()
+	if err := cacher.Set("a", 1, 0); err != nil {
+		t.Fatalf("Set: %v, want nil", err)
+	}
+	if got := cacher.LockStalls(); got != 0 {
+		t.Errorf("LockStalls = %d, want 0", got)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试持续的读者不会使带超时的写者饿死
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：读锁一直被某个读者持有，等待写锁的写者须阻止新的读者进入
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestLockTimeoutReaders(t *testing.T) {
+	cacher, _ := NewCache(0, 0, WithLockTimeout(time.Second))
+	var stop atomic.Bool
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !stop.Load() {
+				cacher.mux.RLock()
+				time.Sleep(time.Millisecond)
+				cacher.mux.RUnlock()
+			}
+		}()
+		time.Sleep(250 * time.Microsecond)
+	}
+	for i := 0; i < 10; i++ {
+		if err := cacher.Set("a", i, 0); err != nil {
+			t.Fatalf("Set %d: %v, want nil", i, err)
+		}
+	}
+	stop.Store(true)
+	wg.Wait()
+}
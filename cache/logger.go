@@ -0,0 +1,53 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：logger.go
+ * 内容摘要：可注入的日志接口。
+ * 其他说明：默认使用不输出任何内容的 nopLogger，不依赖任何日志库。
+ *           Get/Set 等常用路径只在出错时记录日志。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+
+/***************************************************************************************/
+// 数据结构与常量
+
+type Logger interface { // 日志接口
+	Debugf(format string, args ...interface{}) // 调试信息，如清理数量
+	Errorf(format string, args ...interface{}) // 错误信息，如持久化失败、loader 失败
+}
+
+type nopLogger struct{} // 不输出任何内容的日志
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：不输出调试信息
+ * 输入参数：格式：format string, 参数：args ...interface{}
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 nopLogger 类方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (nopLogger) Debugf(format string, args ...interface{}) {}
+
+/***************************************************************************************
+ * 功能描述：不输出错误信息
+ * 输入参数：格式：format string, 参数：args ...interface{}
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 nopLogger 类方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (nopLogger) Errorf(format string, args ...interface{}) {}
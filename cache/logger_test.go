@@ -0,0 +1,87 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：logger_test.go
+ * 内容摘要：日志接口测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：测试 WithLogger 记录清理数量、Flush 数量与 loader 错误
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：WithLogger(nil) 不输出也不 panic
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestLogger(t *testing.T) {
+	logger := &recordLogger{}
+	cacher, _ := NewCache(0, 0, WithLogger(logger), WithLoader(func(key string) (interface{}, error) {
+		return nil, errors.New("backend down")
+	}))
+	cacher.Set("a", 1, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	cacher.DeleteExpired()
+	cacher.Set("b", 2, 0)
+	cacher.Flush()
+	cacher.Get("missing")
+
+	logger.expect(t, "debug: cache gc: scanned 1 items, reaped 1 expired items")
+	logger.expect(t, "debug: cache flush: evicted 1 items")
+	logger.expect(t, "error: cache loader missing: backend down")
+
+	silent, _ := NewCache(0, 0, WithLogger(nil), WithLoader(func(key string) (interface{}, error) {
+		return nil, errors.New("backend down")
+	}))
+	if _, _, err := silent.Get("missing"); err == nil {
+		t.Error("Get with a failing loader = nil error")
+	}
+}
+
+type recordLogger struct { // 记录日志内容的 Logger
+	mux   sync.Mutex
+	lines []string
+}
+
+func (thisLogger *recordLogger) Debugf(format string, args ...interface{}) {
+	thisLogger.mux.Lock()
+	defer thisLogger.mux.Unlock()
+	thisLogger.lines = append(thisLogger.lines, "debug: "+fmt.Sprintf(format, args...))
+}
+
+func (thisLogger *recordLogger) Errorf(format string, args ...interface{}) {
+	thisLogger.mux.Lock()
+	defer thisLogger.mux.Unlock()
+	thisLogger.lines = append(thisLogger.lines, "error: "+fmt.Sprintf(format, args...))
+}
+
+func (thisLogger *recordLogger) expect(t *testing.T, line string) {
+	t.Helper()
+	thisLogger.mux.Lock()
+	defer thisLogger.mux.Unlock()
+	for _, logged := range thisLogger.lines {
+		if logged == line {
+			return
+		}
+	}
+	t.Errorf("log %q not found in:\n%s", line, strings.Join(thisLogger.lines, "\n"))
+}
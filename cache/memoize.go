@@ -0,0 +1,57 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：memoize.go
+ * 内容摘要：以缓存记忆函数的结果。
+ * 其他说明：基于 GetOrLoad，同一 key 的并发未命中只调用一次函数，函数返回错误时不缓存。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"fmt"
+	"time"
+)
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：包装函数，使其结果按参数缓存
+ * 输入参数：缓存：cacher *Cache, 由参数计算键名的函数：keyFn func(K) string,
+ *           被包装的函数：fn func(K) (V, error), 结果的生命周期：ttl time.Duration
+ * 输出参数：无
+ * 返 回 值：包装后的函数
+ * 其他说明：包装后的函数先查找缓存，未命中时调用 fn 并存入成功的结果；同一键名的并发调用只执行一次 fn。
+ *           不同参数的 keyFn 结果必须不同，且不与缓存中其它数据项的键名冲突，建议加上前缀；
+ *           缓存中已有的值不是 V 类型时返回 ErrTypeMismatch
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func Memoize[K comparable, V any](cacher *Cache, keyFn func(K) string, fn func(K) (V, error), ttl time.Duration) func(K) (V, error) {
+	return func(arg K) (V, error) {
+		var zero V
+		key := keyFn(arg)
+		value, err := cacher.GetOrLoad(key, func() (interface{}, time.Duration, error) {
+			result, err := fn(arg)
+			return result, ttl, err
+		})
+		if err != nil {
+			return zero, err
+		}
+		if value == nil { // V 为接口类型且 fn 返回 nil
+			return zero, nil
+		}
+		result, ok := value.(V)
+		if !ok {
+			return zero, fmt.Errorf("item %s: %w", key, ErrTypeMismatch)
+		}
+		return result, nil
+	}
+}
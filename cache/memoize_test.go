@@ -0,0 +1,71 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：memoize_test.go
+ * 内容摘要：函数结果记忆测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：测试 Memoize：同一参数只调用一次函数，结果按 ttl 过期，错误不缓存，缓存中的值类型不符时返回 ErrTypeMismatch
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：前移 clockWall 使结果过期
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestMemoize(t *testing.T) {
+	saved := clockWall
+	defer func() { clockWall = saved }()
+
+	cacher, _ := NewCache(0, 0)
+	calls := map[int]int{}
+	square := Memoize(cacher, func(n int) string { return "square:" + strconv.Itoa(n) }, func(n int) (int, error) {
+		calls[n]++
+		if n < 0 {
+			return 0, errors.New("negative")
+		}
+		return n * n, nil
+	}, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		if got, err := square(3); got != 9 || err != nil {
+			t.Fatalf("square(3) = (%d, %v), want (9, nil)", got, err)
+		}
+	}
+	if calls[3] != 1 {
+		t.Fatalf("fn(3) called %d times, want 1", calls[3])
+	}
+	clockWall += int64(2 * time.Minute)
+	square(3)
+	if calls[3] != 2 {
+		t.Errorf("fn(3) called %d times after the ttl, want 2", calls[3])
+	}
+
+	square(-1)
+	if _, err := square(-1); err == nil || calls[-1] != 2 {
+		t.Errorf("square(-1) = %v after %d calls, want the error uncached", err, calls[-1])
+	}
+
+	cacher.Set("square:4", "sixteen", NoExpiration)
+	if _, err := square(4); !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("square(4) with a string cached = %v, want ErrTypeMismatch", err)
+	}
+}
@@ -0,0 +1,61 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：migrate.go
+ * 内容摘要：加载逐项保存的数据项时，解码失败的数据项交给调用者迁移。
+ * 其他说明：值类型的字段改变后，旧版本保存的数据项可能无法解码；通过 WithItemMigrator 设置迁移函数后，
+ *           Load/LoadFileToMem/LoadMerge/WarmFromFile、LoadStream 与 UnmarshalItem 对解码失败的数据项
+ *           调用迁移函数，由调用者按旧格式重新解释；解码成功的数据项不经过迁移函数。
+ *           旧格式的快照(整个 map 一次编码，见 snapshot.go)无法定位到单个数据项，不调用迁移函数。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+
+/***************************************************************************************/
+// 数据结构与常量
+
+// 迁移解码失败的数据项，raw 为保存的原始记录(codec 编码的 Item)，返回新的值；返回 false 时丢弃该数据项
+type ItemMigrator func(key string, raw []byte) (value interface{}, ok bool)
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：对解码失败的数据项调用迁移函数
+ * 输入参数：数据项键名：key string, 原始记录：raw []byte, 解码错误：decodeErr error
+ * 输出参数：无
+ * 返 回 值：迁移后的数据项，是否保留(bool)；未设置迁移函数时返回 decodeErr
+ * 其他说明：该函数为 Cache 类方法，不持有锁时调用；迁移函数返回 false 或 panic、迁移后的值编码失败时
+ *           不保留该数据项，由调用者跳过；原始记录的过期时间无法解码，迁移后的数据项使用默认过期时间
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) migrateItem(key string, raw []byte, decodeErr error) (Item, bool, error) {
+	if thisCache.migrator == nil {
+		return Item{}, false, decodeErr
+	}
+	var value interface{}
+	var ok bool
+	if thisCache.protect("migrate", key, func() error {
+		value, ok = thisCache.migrator(key, raw)
+		return nil
+	}) != nil || !ok {
+		thisCache.logger.Debugf("cache migrate %s: discarded", key)
+		return Item{}, false, nil
+	}
+	value, err := thisCache.encodeValue(value)
+	if err != nil {
+		thisCache.logger.Errorf("cache migrate %s: %v", key, err)
+		return Item{}, false, nil
+	}
+	thisCache.mux.RLock()
+	expiration := thisCache.expiration(DefaultExpiration)
+	thisCache.mux.RUnlock()
+	return Item{Object: value, Expiration: expiration}, true, nil
+}
@@ -0,0 +1,213 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：migrate_test.go
+ * 内容摘要：快照格式与数据项迁移测试。
+ * 其他说明：旧版本的数据项以具体的结构体类型编码，无法解码到 Item 的 interface{} 字段
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+type userV1 struct { // 旧版本的值类型，Age 为字符串
+	Name string
+	Age  string
+}
+
+type itemV1 struct { // 旧版本保存的数据项
+	Object     userV1
+	Expiration int64
+}
+
+type userV2 struct { // 新版本的值类型
+	Name string
+	Age  int
+}
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：构造包含旧版本数据项的快照
+ * 输入参数：t *testing.T, 旧版本数据项：old map[string]userV1, 新版本数据项：fresh map[string]interface{}
+ * 输出参数：无
+ * 返 回 值：快照内容
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func oldSnapshot(t *testing.T, old map[string]userV1, fresh map[string]interface{}) []byte {
+	var snap, buf bytes.Buffer
+	snap.Write(snapshotMagic)
+	for key, val := range old {
+		buf.Reset()
+		if err := gob.NewEncoder(&buf).Encode(itemV1{Object: val}); err != nil {
+			t.Fatal(err)
+		}
+		writeRecord(&snap, []byte(key))
+		writeRecord(&snap, buf.Bytes())
+	}
+	for key, val := range fresh {
+		buf.Reset()
+		if err := (GobCodec{}).NewEncoder(&buf).Encode(&Item{Object: val}); err != nil {
+			t.Fatal(err)
+		}
+		writeRecord(&snap, []byte(key))
+		writeRecord(&snap, buf.Bytes())
+	}
+	return snap.Bytes()
+}
+
+/***************************************************************************************
+ * 功能描述：将旧版本的数据项迁移为新版本
+ * 输入参数：数据项键名：key string, 原始记录：raw []byte
+ * 输出参数：无
+ * 返 回 值：新版本的值，Age 不是数字时返回 false
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func migrateUser(key string, raw []byte) (interface{}, bool) {
+	var old itemV1
+	if err := gob.NewDecoder(bytes.NewReader(raw)).Decode(&old); err != nil {
+		return nil, false
+	}
+	age := 0
+	for _, c := range old.Object.Age {
+		if c < '0' || c > '9' {
+			return nil, false
+		}
+		age = age*10 + int(c-'0')
+	}
+	return userV2{Name: old.Object.Name, Age: age}, true
+}
+
+/***************************************************************************************
+ * 功能描述：测试 Load 对解码失败的数据项调用迁移函数
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：迁移函数返回 false 的数据项被跳过，其余数据项照常加载
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestLoadMigrate(t *testing.T) {
+	snap := oldSnapshot(t,
+		map[string]userV1{"alice": {Name: "alice", Age: "30"}, "bob": {Name: "bob", Age: "unknown"}},
+		map[string]interface{}{"count": 7})
+
+	cacher, _ := NewCache(0, 0, WithItemMigrator(migrateUser))
+	if err := cacher.Load(bytes.NewReader(snap)); err != nil {
+		t.Fatal(err)
+	}
+	if value, found, _ := cacher.Get("alice"); !found || value != (userV2{Name: "alice", Age: 30}) {
+		t.Errorf("alice = %v, %v, want migrated userV2", value, found)
+	}
+	if _, found, _ := cacher.Get("bob"); found {
+		t.Error("bob was rejected by the migrator but loaded")
+	}
+	if value, found, _ := cacher.Get("count"); !found || value != 7 {
+		t.Errorf("count = %v, %v, want 7", value, found)
+	}
+
+	plain, _ := NewCache(0, 0)
+	if err := plain.Load(bytes.NewReader(snap)); err == nil {
+		t.Error("Load without migrator succeeded on an undecodable item")
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 LoadStream 与 UnmarshalItem 在迁移函数返回 false 时跳过数据项
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestLoadStreamMigrate(t *testing.T) {
+	snap := oldSnapshot(t,
+		map[string]userV1{"alice": {Name: "alice", Age: "30"}, "bob": {Name: "bob", Age: "unknown"}},
+		map[string]interface{}{"count": 7})
+
+	cacher, _ := NewCache(0, 0, WithItemMigrator(migrateUser))
+	if err := cacher.LoadStream(bytes.NewReader(snap)); err != nil {
+		t.Fatal(err)
+	}
+	if got := cacher.Count(); got != 2 {
+		t.Errorf("Count = %d, want 2", got)
+	}
+
+	var buf bytes.Buffer
+	gob.NewEncoder(&buf).Encode(itemV1{Object: userV1{Name: "carol", Age: "old"}})
+	if err := cacher.UnmarshalItem("carol", buf.Bytes()); err != nil {
+		t.Errorf("UnmarshalItem rejected item: %v, want nil", err)
+	}
+	if _, found, _ := cacher.Get("carol"); found {
+		t.Error("carol was rejected by the migrator but stored")
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 Save 写出的快照与旧格式的快照都能被 Load 读取
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestSaveLoad(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	cacher.Set("a", 1, 0)
+	cacher.Set("b", "two", 0)
+	var buf bytes.Buffer
+	if err := cacher.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), snapshotMagic) {
+		t.Fatal("snapshot has no header")
+	}
+	loaded, _ := NewCache(0, 0)
+	if err := loaded.Load(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if value, found, _ := loaded.Get("b"); !found || value != "two" {
+		t.Errorf("b = %v, %v, want two", value, found)
+	}
+
+	buf.Reset()
+	legacy := map[string]Item{"a": {Object: 1}, "b": {Object: "two"}}
+	if err := (GobCodec{}).NewEncoder(&buf).Encode(&legacy); err != nil {
+		t.Fatal(err)
+	}
+	loaded, _ = NewCache(0, 0)
+	if err := loaded.Load(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.Count(); got != 2 {
+		t.Errorf("legacy Count = %d, want 2", got)
+	}
+}
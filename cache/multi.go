@@ -0,0 +1,480 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：multi.go
+ * 内容摘要：批量读写操作。
+ * 其他说明：批量操作只获取一次锁，避免逐个调用时 N 次加锁的开销。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"context"
+	"time"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+type ValueExpiration struct { // 数据项的值与过期时间
+	Value      interface{} // 数据项的值
+	Expiration time.Time   // 数据项的过期时间，永不过期时为零值
+}
+
+type ImportEntry struct { // 批量导入的数据项
+	Value interface{}   // 数据项的值
+	TTL   time.Duration // 数据项生命周期，DefaultExpiration 与 NoExpiration 的含义与 Set 相同
+}
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：批量获取数据项
+ * 输入参数：数据项键名：keys []string
+ * 输出参数：无
+ * 返 回 值：map[string]interface{} 找到的数据项，不存在或已过期的键名不包含在内
+ * 其他说明：该函数为 Cache 类方法，只获取一次读锁，不调用 loader，不记录访问时间；
+ *           启用 WithCopyOnGet 时在释放锁后复制，复制失败的键名不包含在内
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) GetMulti(keys []string) map[string]interface{} {
+	values := make(map[string]interface{}, len(keys))
+	normalized := thisCache.checkKeys(keys)
+	thisCache.mux.RLock()
+	for i, key := range keys {
+		if len(normalized[i]) == 0 {
+			continue
+		}
+		if value, found, _ := thisCache.get(normalized[i]); found {
+			values[key] = value
+		}
+	}
+	thisCache.mux.RUnlock()
+
+	if thisCache.copier != nil || thisCache.valueCodec != nil {
+		for key, value := range values {
+			if copied, err := thisCache.readValue(value); err == nil {
+				values[key] = copied
+			} else {
+				delete(values, key)
+			}
+		}
+	}
+	return values
+}
+
+/***************************************************************************************
+ * 功能描述：批量获取数据项，并返回未命中的键名
+ * 输入参数：数据项键名：keys []string
+ * 输出参数：无
+ * 返 回 值：found 找到的数据项，missing 不存在、已过期或为墓碑的键名(按 keys 的顺序，去重)
+ * 其他说明：该函数为 Cache 类方法，只获取一次读锁，不调用 loader，不记录访问时间；
+ *           missing 可直接交给 loader 加载(read-through)；
+ *           启用 WithCopyOnGet 时在释放锁后复制，复制失败的键名计入 missing
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) GetBatch(keys []string) (found map[string]interface{}, missing []string) {
+	found = make(map[string]interface{}, len(keys))
+	normalized := thisCache.checkKeys(keys)
+	thisCache.mux.RLock()
+	for i, key := range keys {
+		if len(normalized[i]) == 0 {
+			continue
+		}
+		if value, ok, _ := thisCache.get(normalized[i]); ok {
+			found[key] = value
+		}
+	}
+	thisCache.mux.RUnlock()
+
+	if thisCache.copier != nil || thisCache.valueCodec != nil {
+		for key, value := range found {
+			if copied, err := thisCache.readValue(value); err == nil {
+				found[key] = copied
+			} else {
+				delete(found, key)
+			}
+		}
+	}
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if _, ok := found[key]; !ok && !seen[key] {
+			seen[key] = true
+			missing = append(missing, key)
+		}
+	}
+	return found, missing
+}
+
+/***************************************************************************************
+ * 功能描述：批量获取数据项的值与过期时间
+ * 输入参数：数据项键名：keys []string
+ * 输出参数：无
+ * 返 回 值：map[string]ValueExpiration 找到的数据项，不存在或已过期的键名不包含在内
+ * 其他说明：该函数为 Cache 类方法，只获取一次读锁，可用于计算一批数据的 Cache-Control；
+ *           启用 WithCopyOnGet 时在释放锁后复制，复制失败的键名不包含在内
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) GetMultiWithExpiration(keys []string) map[string]ValueExpiration {
+	now := nanotime()
+	values := make(map[string]ValueExpiration, len(keys))
+	normalized := thisCache.checkKeys(keys)
+	thisCache.mux.RLock()
+	for i, key := range keys {
+		if len(normalized[i]) == 0 {
+			continue
+		}
+		item, found := thisCache.items[normalized[i]]
+		if !found || thisCache.expired(item, now) || item.negative() {
+			continue
+		}
+		var expiration time.Time
+		if item.Expiration > 0 {
+			expiration = time.Unix(0, item.Expiration)
+		}
+		values[key] = ValueExpiration{Value: item.Object, Expiration: expiration}
+	}
+	thisCache.mux.RUnlock()
+
+	if thisCache.copier != nil || thisCache.valueCodec != nil {
+		for key, value := range values {
+			if copied, err := thisCache.readValue(value.Value); err == nil {
+				value.Value = copied
+				values[key] = value
+			} else {
+				delete(values, key)
+			}
+		}
+	}
+	return values
+}
+
+/***************************************************************************************
+ * 功能描述：批量刷新数据项的过期时间
+ * 输入参数：数据项键名：keys []string, 数据项生命周期：dur time.Duration
+ * 输出参数：无
+ * 返 回 值：int 实际刷新的数据项数量
+ * 其他说明：该函数为 Cache 类方法，只获取一次写锁；不存在、已过期的数据项以及墓碑数据项被跳过
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) TouchMulti(keys []string, dur time.Duration) int {
+	if thisCache.closed.Load() {
+		return 0
+	}
+	normalized := thisCache.checkKeys(keys)
+	if thisCache.lockWithTimeout() != nil {
+		return 0
+	}
+	defer thisCache.unlock()
+
+	touched := 0
+	for _, key := range normalized {
+		if len(key) == 0 {
+			continue
+		}
+		if _, found := thisCache.touch(key, dur); found {
+			touched++
+		}
+	}
+	return touched
+}
+
+/***************************************************************************************
+ * 功能描述：批量导入数据项
+ * 输入参数：数据项：entries map[string]ImportEntry
+ * 输出参数：无
+ * 返 回 值：无 error， 则为 nil
+ * 其他说明：该函数为 Cache 类方法，先校验全部键名与值的大小，任一不合法时不导入任何数据项；
+ *           在一次写锁内存入全部数据项，被覆盖的值在释放锁后调用 onEvicted(Replaced/Expired)
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) Import(entries map[string]ImportEntry) error {
+	if thisCache.closed.Load() {
+		return ErrCacheClosed
+	}
+	normalized := make(map[string]ImportEntry, len(entries))
+	for key, entry := range entries {
+		key, err := thisCache.checkKey(key)
+		if err != nil {
+			return err
+		}
+		if err := thisCache.checkValueSize(entry.Value); err != nil {
+			return err
+		}
+		normalized[key] = entry
+	}
+
+	var replaced, expired []keyAndValue
+	if err := thisCache.lockWithTimeout(); err != nil {
+		return err
+	}
+	for key, entry := range normalized {
+		evicted, reason := thisCache.overwrite(key, entry.Value, entry.TTL)
+		if reason == Expired {
+			expired = append(expired, evicted...)
+		} else {
+			replaced = append(replaced, evicted...)
+		}
+	}
+	onEvicted := thisCache.onEvicted
+	thisCache.unlock()
+
+	fireEvicted(onEvicted, replaced, Replaced)
+	fireEvicted(onEvicted, expired, Expired)
+	return nil
+}
+
+/***************************************************************************************
+ * 功能描述：批量获取数据项，未命中的数据项通过 loader 一次加载
+ * 输入参数：数据项键名：keys []string,
+ *           加载函数：loader func(missing []string) (map[string]interface{}, error),
+ *           数据项生命周期：dur time.Duration
+ * 输出参数：无
+ * 返 回 值：map[string]interface{} 命中与加载的数据项，loader 未返回的键名不包含在内；无 error 则为 nil
+ * 其他说明：该函数为 Cache 类方法，没有未命中的数据项时不调用 loader；
+ *           loader 只收到未命中的键名(去重)，返回结果中不属于 missing 的键名被忽略；
+ *           加载的数据项在一次写锁内存入，超过 maxValueBytes 的值只返回不存入；
+ *           loader 返回错误时不存入任何数据项，返回该错误。
+ *           并发调用之间按 key 合并加载：其它调用者正在加载的键名不再交给 loader，而是等待其结果，
+ *           对方的 loader 返回错误时同样返回该错误
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) GetOrLoadMulti(keys []string, loader func(missing []string) (map[string]interface{}, error), dur time.Duration) (map[string]interface{}, error) {
+	if thisCache.closed.Load() {
+		return nil, ErrCacheClosed
+	}
+	for _, key := range keys {
+		if _, err := thisCache.checkKey(key); err != nil {
+			return nil, err
+		}
+	}
+	values := thisCache.GetMulti(keys)
+	var missing []string
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if _, found := values[key]; !found && !seen[key] {
+			seen[key] = true
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) == 0 {
+		return values, nil
+	}
+
+	owned, calls, waits := thisCache.claimLoads(missing)
+	if len(owned) > 0 {
+		if err := thisCache.loadBatch(values, owned, calls, loader, dur); err != nil {
+			return nil, err
+		}
+	}
+	for key, call := range waits { // 由其它调用者正在加载的键名
+		call.done.Wait()
+		if call.err != nil {
+			return nil, call.err
+		}
+		if !call.found {
+			continue
+		}
+		if value, err := thisCache.copyValue(call.value); err == nil {
+			values[key] = value
+		}
+	}
+	return values, nil
+}
+
+/***************************************************************************************
+ * 功能描述：加载由当前调用者登记的键名，存入缓存并完成登记的加载
+ * 输入参数：返回结果：values map[string]interface{}, 登记的键名：owned []string,
+ *           登记的加载：calls []*loadCall,
+ *           加载函数：loader func(missing []string) (map[string]interface{}, error),
+ *           数据项生命周期：dur time.Duration
+ * 输出参数：values 加入加载的数据项
+ * 返 回 值：loader 的错误，无 error 则为 nil
+ * 其他说明：该函数为 Cache 类方法，登记之前刚完成的加载已存入的键名直接从缓存读取，不再交给 loader；
+ *           loader panic 时等待者同样被唤醒
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) loadBatch(values map[string]interface{}, owned []string, calls []*loadCall, loader func(missing []string) (map[string]interface{}, error), dur time.Duration) error {
+	loaded := make(map[string]interface{}, len(owned))
+	err := errLoadPanicked
+	defer func() {
+		thisCache.finishLoads(owned, calls, loaded, err)
+	}()
+
+	var missing []string
+	for _, key := range owned { // 上一次加载刚刚完成
+		normalized, _ := thisCache.checkKey(key)
+		if item, found := thisCache.access(normalized); found && !item.negative() {
+			if value, decodeErr := thisCache.decodeValue(item.Object); decodeErr == nil {
+				loaded[key] = value
+				if value, readErr := thisCache.readValue(item.Object); readErr == nil {
+					values[key] = value
+				}
+				continue
+			}
+		}
+		missing = append(missing, key)
+	}
+	if len(missing) == 0 {
+		err = nil
+		return nil
+	}
+	var result map[string]interface{}
+	err = thisCache.protect("load multi", "", func() (err error) {
+		result, err = loader(missing)
+		return err
+	})
+	if err != nil {
+		thisCache.logger.Errorf("cache load multi %v: %v", missing, err)
+		return err
+	}
+	for _, key := range missing {
+		if value, found := result[key]; found {
+			loaded[key] = value
+		}
+	}
+	thisCache.storeLoaded(values, missing, result, dur)
+	return nil
+}
+
+/***************************************************************************************
+ * 功能描述：批量获取数据项，未命中的数据项通过 loader 一次加载，ctx 被取消时返回已命中的数据项
+ * 输入参数：上下文：ctx context.Context, 数据项键名：keys []string,
+ *           加载函数：loader func(missing []string) (map[string]interface{}, error),
+ *           数据项生命周期：dur time.Duration
+ * 输出参数：无
+ * 返 回 值：map[string]interface{} 命中与加载的数据项；无 error 则为 nil
+ * 其他说明：该函数为 Cache 类方法，与 GetOrLoadMulti 相同，但 loader 在单独的 goroutine 中执行：
+ *           调用前或加载期间 ctx 被取消时立即返回缓存中已命中的数据项与 ctx.Err()，
+ *           loader 继续执行至返回，但其结果被丢弃，不存入缓存(也不存入部分结果)；
+ *           loader 应自行监听 ctx 以尽早结束。loader 返回错误时返回 nil 与该错误
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) GetMultiContext(ctx context.Context, keys []string, loader func(missing []string) (map[string]interface{}, error), dur time.Duration) (map[string]interface{}, error) {
+	if thisCache.closed.Load() {
+		return nil, ErrCacheClosed
+	}
+	for _, key := range keys {
+		if _, err := thisCache.checkKey(key); err != nil {
+			return nil, err
+		}
+	}
+	values, missing := thisCache.GetBatch(keys)
+	if len(missing) == 0 {
+		return values, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return values, err
+	}
+
+	type loadResult struct {
+		loaded map[string]interface{}
+		err    error
+	}
+	done := make(chan loadResult, 1) // 带缓冲，取消后 loader 返回时不会阻塞
+	go func() {
+		var result loadResult
+		result.err = thisCache.protect("load multi", "", func() (err error) {
+			result.loaded, err = loader(missing)
+			return err
+		})
+		done <- result
+	}()
+
+	var result loadResult
+	select {
+	case <-ctx.Done():
+		return values, ctx.Err()
+	case result = <-done:
+	}
+	if result.err != nil {
+		thisCache.logger.Errorf("cache load multi %v: %v", missing, result.err)
+		return nil, result.err
+	}
+	thisCache.storeLoaded(values, missing, result.loaded, dur)
+	return values, nil
+}
+
+/***************************************************************************************
+ * 功能描述：存入批量加载的数据项，并加入返回结果
+ * 输入参数：返回结果：values map[string]interface{}, 未命中的键名：missing []string,
+ *           加载的数据项：loaded map[string]interface{}, 数据项生命周期：dur time.Duration
+ * 输出参数：values 加入 loaded 中属于 missing 的数据项
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，在一次写锁内存入，超过 maxValueBytes 的值以及获取写锁超时时只返回不存入；
+ *           被覆盖的值在释放锁后调用 onEvicted(Replaced/Expired)；启用 WithCopyOnGet 时返回副本
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) storeLoaded(values map[string]interface{}, missing []string, loaded map[string]interface{}, dur time.Duration) {
+	for _, key := range missing {
+		if value, found := loaded[key]; found {
+			values[key] = value
+		}
+	}
+	normalized := thisCache.checkKeys(missing)
+	if thisCache.lockWithTimeout() == nil { // 获取写锁超时时只返回加载的值，不存入
+		var replaced, expired []keyAndValue
+		for i, key := range missing {
+			value, found := loaded[key]
+			if !found || len(normalized[i]) == 0 || thisCache.checkValueSize(value) != nil {
+				continue
+			}
+			evicted, reason := thisCache.overwrite(normalized[i], value, dur)
+			if reason == Expired {
+				expired = append(expired, evicted...)
+			} else {
+				replaced = append(replaced, evicted...)
+			}
+		}
+		onEvicted := thisCache.onEvicted
+		thisCache.unlock()
+
+		fireEvicted(onEvicted, replaced, Replaced)
+		fireEvicted(onEvicted, expired, Expired)
+	}
+	if thisCache.copier != nil {
+		for _, key := range missing {
+			value, found := values[key]
+			if !found {
+				continue
+			}
+			if copied, err := thisCache.copyValue(value); err == nil {
+				values[key] = copied
+			} else {
+				delete(values, key)
+			}
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：multi_test.go
+ * 内容摘要：批量操作测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：测试 TouchMulti 批量刷新过期时间并返回实际刷新的数量
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：不存在与已过期的数据项被跳过；前移 clockWall 模拟时间流逝
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestTouchMulti(t *testing.T) {
+	saved := clockWall
+	defer func() { clockWall = saved }()
+
+	cacher, _ := NewCache(0, 0)
+	cacher.Set("a", 1, time.Minute)
+	cacher.Set("b", 2, time.Minute)
+	cacher.Set("expired", 3, time.Second)
+	clockWall += int64(2 * time.Second)
+
+	if n := cacher.TouchMulti([]string{"a", "b", "expired", "missing"}, time.Hour); n != 2 {
+		t.Errorf("TouchMulti = %d, want 2", n)
+	}
+	ttls := cacher.Expirations()
+	for _, key := range []string{"a", "b"} {
+		if ttls[key] <= 59*time.Minute {
+			t.Errorf("%s ttl = %v, want about 1h", key, ttls[key])
+		}
+	}
+	if cacher.Has("expired") {
+		t.Error("TouchMulti revived an expired item")
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 Import 按每个数据项的 TTL 存入，任一数据项不合法时不导入任何数据项
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：DefaultExpiration 与 NoExpiration 的含义与 Set 相同；被覆盖的值调用 onEvicted(Replaced)
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestImport(t *testing.T) {
+	cacher, _ := NewCache(time.Hour, 0, WithMaxValueBytes(4))
+	var reasons []Reason
+	cacher.OnEvictedReason(func(key string, value interface{}, reason Reason) {
+		reasons = append(reasons, reason)
+	})
+	cacher.Set("a", "old", 0)
+	err := cacher.Import(map[string]ImportEntry{
+		"a":       {Value: "new", TTL: time.Minute},
+		"pinned":  {Value: "p", TTL: NoExpiration},
+		"default": {Value: "d", TTL: DefaultExpiration},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ttls := cacher.Expirations()
+	if ttls["a"] <= 59*time.Second || ttls["a"] > time.Minute {
+		t.Errorf("a ttl = %v, want about 1m", ttls["a"])
+	}
+	if ttls["pinned"] != NoExpiration {
+		t.Errorf("pinned ttl = %v, want NoExpiration", ttls["pinned"])
+	}
+	if ttls["default"] <= 59*time.Minute {
+		t.Errorf("default ttl = %v, want about 1h", ttls["default"])
+	}
+	if value, _, _ := cacher.Get("a"); value != "new" || len(reasons) != 1 || reasons[0] != Replaced {
+		t.Errorf("a = %v, eviction reasons %v, want new and [Replaced]", value, reasons)
+	}
+
+	err = cacher.Import(map[string]ImportEntry{
+		"ok":  {Value: "x", TTL: 0},
+		"big": {Value: "too big", TTL: 0},
+	})
+	if !errors.Is(err, ErrValueTooBig) || cacher.Has("ok") {
+		t.Errorf("Import with an oversized value = %v, ok stored %v, want ErrValueTooBig and nothing stored", err, cacher.Has("ok"))
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 GetBatch 区分命中与未命中的键名
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：已过期的数据项计入 missing
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestGetBatch(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	cacher.Set("a", 1, 0)
+	cacher.Set("expired", 2, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	found, missing := cacher.GetBatch([]string{"a", "b", "expired"})
+	if len(found) != 1 || found["a"] != 1 {
+		t.Errorf("found = %v, want map[a:1]", found)
+	}
+	if len(missing) != 2 || missing[0] != "b" || missing[1] != "expired" {
+		t.Errorf("missing = %v, want [b expired]", missing)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 GetMultiContext 加载未命中的数据项，被取消时丢弃加载结果
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestGetMultiContext(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	cacher.Set("a", 1, 0)
+	loader := func(missing []string) (map[string]interface{}, error) {
+		loaded := map[string]interface{}{}
+		for _, key := range missing {
+			loaded[key] = key + "!"
+		}
+		return loaded, nil
+	}
+
+	values, err := cacher.GetMultiContext(context.Background(), []string{"a", "b"}, loader, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 2 || values["a"] != 1 || values["b"] != "b!" {
+		t.Errorf("values = %v, want map[a:1 b:b!]", values)
+	}
+	if value, found, _ := cacher.Get("b"); !found || value != "b!" {
+		t.Errorf("b = %v, %v, want stored b!", value, found)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	release := make(chan struct{})
+	slow := func(missing []string) (map[string]interface{}, error) {
+		<-release
+		return loader(missing)
+	}
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	values, err = cacher.GetMultiContext(ctx, []string{"a", "c"}, slow, 0)
+	close(release)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if len(values) != 1 || values["a"] != 1 {
+		t.Errorf("values = %v, want map[a:1]", values)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if _, found, _ := cacher.Get("c"); found {
+		t.Error("result of a canceled load was stored")
+	}
+}
@@ -0,0 +1,41 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：observe.go
+ * 内容摘要：观察 Get 的命中情况，用于统计键名的访问热度。
+ * 其他说明：观察函数在每次 Get 时同步调用(不持有锁)，位于读取的热路径上，应只做计数等轻量操作，
+ *           耗时的分析应交给其它 goroutine；未设置时只有一次 nil 判断的开销。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+
+/***************************************************************************************/
+// 数据结构与常量
+
+// 观察 Get 的结果，hit 为 true 表示在缓存中找到了未过期的数据项
+type AccessObserver func(key string, hit bool)
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：向观察函数报告一次 Get 的结果
+ * 输入参数：数据项键名：key string, 是否命中：hit bool
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，不持有锁时调用；观察函数 panic 时被恢复并记录日志
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) observe(key string, hit bool) {
+	thisCache.protect("observer", key, func() error {
+		thisCache.observer(key, hit)
+		return nil
+	})
+}
@@ -0,0 +1,647 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：options.go
+ * 内容摘要：NewCache 的可选配置项。
+ * 其他说明：配置项在 NewCache 中按传入顺序依次生效，且在启动 gcLoop 之前完成。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"hash"
+	"time"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+type Option func(*Cache) // 缓存的可选配置项
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：设置未命中时加载数据项的函数(read-through)
+ * 输入参数：加载函数：loader LoaderFunc
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：设置后，Get 未命中时调用 loader 加载并以默认过期时间存入缓存
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithLoader(loader LoaderFunc) Option {
+	return func(thisCache *Cache) {
+		thisCache.loader = loader
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：设置负缓存(墓碑数据项)的生命周期
+ * 输入参数：墓碑数据项生命周期：dur time.Duration
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：loader 返回 ErrKeyNotFound 时存入墓碑数据项，dur 内的 Get 直接返回未命中，
+ *           不再调用 loader；dur <= 0 时不缓存未命中结果
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithNegativeTTL(dur time.Duration) Option {
+	return func(thisCache *Cache) {
+		thisCache.negativeTTL = dur
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：设置 loader 失败后的指数退避
+ * 输入参数：初始退避时间：base time.Duration, 最大退避时间：max time.Duration
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：loader 返回 ErrKeyNotFound 以外的错误后，退避时间内的 Get 直接返回该错误，
+ *           不再调用 loader；每次连续失败退避时间翻倍，不超过 max，加载成功后重置；
+ *           base <= 0 时不启用，max 小于 base 时按 base 处理
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithLoaderBackoff(base, max time.Duration) Option {
+	return func(thisCache *Cache) {
+		if max < base {
+			max = base
+		}
+		thisCache.backoffBase = base
+		thisCache.backoffMax = max
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：设置闲置过期时间(滑动过期)
+ * 输入参数：闲置时长：idle time.Duration
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：启用后每次 Get 命中都会记录数据项的访问时间(需要写锁)，
+ *           超过 idle 未被访问的数据项视为过期，由 DeleteExpired 清理；
+ *           与绝对过期时间同时生效，任一条件满足即过期
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithIdleExpiration(idle time.Duration) Option {
+	return func(thisCache *Cache) {
+		thisCache.idleTTL = idle
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：记录数据项的访问时间
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：启用后每次 Get 命中都会记录数据项的访问时间(需要写锁)，
+ *           使 TrimToCount/TrimToBytes 按最近访问时间而非最近写入时间裁剪；
+ *           启用闲置过期时总会记录访问时间
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithAccessTracking() Option {
+	return func(thisCache *Cache) {
+		thisCache.trackAccess.Store(true)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：设置 Save/Load 使用的编解码器
+ * 输入参数：编解码器：codec Codec
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：默认为 GobCodec；使用 JSONCodec 或自定义 Codec 可避免修改 gob 的全局类型注册表
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithCodec(codec Codec) Option {
+	return func(thisCache *Cache) {
+		thisCache.codec = codec
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：启用自适应清理周期
+ * 输入参数：周期下限：min time.Duration, 周期上限：max time.Duration
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：gcLoop 根据每次清理时过期数据项所占比例调整周期：过期多则缩短，过期少则延长，
+ *           周期限制在 [min, max] 内，初始周期为 gcInterval；min <= 0 或 max < min 时不启用
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithAdaptiveGC(min, max time.Duration) Option {
+	return func(thisCache *Cache) {
+		if min <= 0 || max < min {
+			return
+		}
+		thisCache.gcMinInterval = min
+		thisCache.gcMaxInterval = max
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：限制单个数据项值的最大字节数
+ * 输入参数：最大字节数：n int
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：超过限制时 Set、Add、Replace 返回 ErrValueTooBig，数据项不会写入缓存。
+ *           []byte 与 string 按 len 计算；其它类型默认不受限制，见 WithGobValueSize；
+ *           n <= 0 表示不限制
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithMaxValueBytes(n int) Option {
+	return func(thisCache *Cache) {
+		thisCache.maxValueBytes = n
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：对 []byte、string 以外类型的值也按 gob 编码后的长度限制大小
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：需同时设置 WithMaxValueBytes；每次写入都要编码一次，开销较大；
+ *           无法用 gob 编码的值写入时返回错误
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithGobValueSize() Option {
+	return func(thisCache *Cache) {
+		thisCache.gobSizeValues = true
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：设置日志
+ * 输入参数：日志：logger Logger
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：记录清理数量、持久化失败、Flush 移出数量、loader 失败等；为 nil 时不输出
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithLogger(logger Logger) Option {
+	return func(thisCache *Cache) {
+		if logger == nil {
+			logger = nopLogger{}
+		}
+		thisCache.logger = logger
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：设置 items 的初始容量
+ * 输入参数：初始容量：n int
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：仅为容量提示，并不限制数据项数量；NewCache 与 Flush 按该容量预分配 map，
+ *           减少预热阶段 map 扩容带来的内存分配
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithInitialCapacity(n int) Option {
+	return func(thisCache *Cache) {
+		if n > 0 {
+			thisCache.initialCapacity = n
+		}
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：读取时返回数据项的值的副本
+ * 输入参数：复制函数：copier CopyFunc，为 nil 时使用 GobCopy
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：对 Get、GetAndTouch、GetMulti、GetMultiWithExpiration、GetOrCompute 生效；
+ *           每次命中都要复制，gob 复制的开销通常是直接返回的数十倍以上，只对可变的值启用
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithCopyOnGet(copier CopyFunc) Option {
+	return func(thisCache *Cache) {
+		if copier == nil {
+			copier = GobCopy
+		}
+		thisCache.copier = copier
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：gcLoop 清理后在数据项数量大幅下降时自动重建 items
+ * 输入参数：比例：ratio float64，取值 (0, 1)
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：数据项数量低于峰值的 ratio 倍(且峰值不少于 1024)时调用 Compact；
+ *           重建需要在写锁内复制全部数据项，ratio 越大重建越频繁
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithAutoCompact(ratio float64) Option {
+	return func(thisCache *Cache) {
+		thisCache.compactRatio = ratio
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：启用增量持久化日志
+ * 输入参数：日志文件路径：path string
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：NewCache 以追加方式打开 path，打开失败时 NewCache 返回错误；之后每次写入、删除、
+ *           刷新过期时间都追加一条记录，gcLoop 在记录数远多于数据项数量时压缩日志；
+ *           启动时调用 LoadFromLog(path) 恢复数据项，退出前调用 CloseLog
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithWriteAheadLog(path string) Option {
+	return func(thisCache *Cache) {
+		thisCache.walPath = path
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：设置 SetKey 计算分片使用的哈希函数
+ * 输入参数：创建哈希的函数：newHash func() hash.Hash，
+ *           如 func() hash.Hash { return fnv.New32a() }
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：默认为 CRC32(IEEE)；哈希值不足 4 个字节时 SetKey 会 panic；为 nil 时保持默认
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithShardHasher(newHash func() hash.Hash) Option {
+	return func(thisCache *Cache) {
+		if newHash != nil {
+			thisCache.shardHasher = newHash
+		}
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：设置 Put 按内容寻址使用的哈希函数
+ * 输入参数：创建哈希的函数：newHash func() hash.Hash
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：默认为 SHA-256；改用 FNV、xxhash 等非密码学哈希可以更快，但攻击者可以构造
+ *           哈希相同的不同值，使后写入的值覆盖先写入的值(缓存投毒)，
+ *           只在值不受外部控制时使用；为 nil 时保持默认
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithHasher(newHash func() hash.Hash) Option {
+	return func(thisCache *Cache) {
+		if newHash != nil {
+			thisCache.hasher = newHash
+		}
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：设置估算数据项的值大小的函数
+ * 输入参数：估算函数：sizer SizeFunc
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：EstimatedBytes 与 TrimToBytes 优先使用该函数，返回 false 时使用 Sizer 接口或 reflect 估算；
+ *           用于无法为其实现 Sizer 的类型
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithSizer(sizer SizeFunc) Option {
+	return func(thisCache *Cache) {
+		thisCache.sizer = sizer
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：设置数据项即将过期时的刷新函数
+ * 输入参数：提前量：lead time.Duration, 刷新函数：fn PreExpiryFunc
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：gcLoop 每次清理后，对 lead 之内即将过期的数据项调用 fn，fn 返回 true 时以其返回的
+ *           新值与生命周期替换旧值；需要启动 gcLoop，且清理周期应小于 lead
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithPreExpiryHook(lead time.Duration, fn PreExpiryFunc) Option {
+	return func(thisCache *Cache) {
+		thisCache.preExpiryLead = lead
+		thisCache.preExpiry = fn
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：关闭 gcLoop 首次清理的随机延迟
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：默认首次清理在 (0, gcInterval] 内随机延迟，以错开多个缓存的清理时刻；
+ *           关闭后首次清理固定在 gcInterval 之后，便于测试
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithoutGCJitter() Option {
+	return func(thisCache *Cache) {
+		thisCache.gcNoJitter = true
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：推迟到首次写入时才启动 gcLoop
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：未写入过的缓存不创建 goroutine，适用于测试与短生命周期的工具；
+ *           SetGCInterval、ResumeGC 仍会立即启动 gcLoop
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithLazyGC() Option {
+	return func(thisCache *Cache) {
+		thisCache.gcLazy = true
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：关闭回调函数 panic 的恢复
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：默认 onEvicted、loader、加载函数与 PreExpiryFunc 的 panic 被恢复并记录日志；
+ *           关闭后 panic 直接传播给调用者，在 gcLoop 中发生时进程退出，适用于希望尽早暴露错误的场景
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithoutPanicRecovery() Option {
+	return func(thisCache *Cache) {
+		thisCache.noRecover = true
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：设置校验与规范化键名的函数
+ * 输入参数：校验函数：validator KeyValidator
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：Set、Add、Replace、Get 返回 validator 的错误，Delete、Has、Touch、GetAndTouch
+ *           对不合法的键名不做任何操作；Get 与写入使用相同的规范化，查找结果一致
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithKeyValidator(validator KeyValidator) Option {
+	return func(thisCache *Cache) {
+		thisCache.keyValidator = validator
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：启用定时保存
+ * 输入参数：文件路径：file string, 保存周期：interval time.Duration, 失败后的最大周期：max time.Duration
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：每隔 interval 调用 SaveMemToFile(file)；保存失败时周期加倍，不超过 max，
+ *           成功后恢复为 interval；max 小于 interval 时不退避。StopAutoSave 停止定时保存
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithAutoSave(file string, interval, max time.Duration) Option {
+	return func(thisCache *Cache) {
+		if max < interval {
+			max = interval
+		}
+		thisCache.autoSaveFile = file
+		thisCache.autoSaveBase = interval
+		thisCache.autoSaveMax = max
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：设置读取到已过期的数据项时是否立即删除
+ * 输入参数：是否删除：enabled bool
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：默认已过期的数据项只对读取不可见，由 gcLoop 清理；启用后 Get 等经 access 读取的方法
+ *           遇到已过期的数据项时获取写锁删除并以 Expired 调用 onEvicted，适用于内存紧张且 gcInterval 较长的场景
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithDeleteOnExpiredRead(enabled bool) Option {
+	return func(thisCache *Cache) {
+		thisCache.deleteOnRead = enabled
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：设置以编码后的 []byte 存储数据项的值
+ * 输入参数：编解码器：codec Codec，如 GobCodec{}、JSONCodec{}
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：启用后缓存中的值与调用者持有的值完全隔离，内存占用可由 Size 准确统计；
+ *           代价是每次写入编码一次、每次读取解码一次，见 serial.go
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithSerializedValues(codec Codec) Option {
+	return func(thisCache *Cache) {
+		thisCache.valueCodec = codec
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：设置数据项数量上限
+ * 输入参数：数量上限：max int，<= 0 表示不限制
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：写入新数据项使数量超过上限时，在同一写锁内按淘汰策略(默认 LRU，见 WithEvictionPolicy)
+ *           移出数据项，释放锁后调用 onEvicted(Capacity)；每次移出的数量见 WithEvictionBatch。
+ *           LRU 需要记录访问时间，设置上限时自动启用 WithAccessTracking(Get 命中需要写锁)；
+ *           选择移出的数据项需要遍历全部数据项，每次插入超过上限的开销为 O(n)
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithMaxItems(max int) Option {
+	return func(thisCache *Cache) {
+		if max < 0 {
+			max = 0
+		}
+		thisCache.maxItems = max
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：设置超过数据项数量上限时一次移出的数据项数量
+ * 输入参数：批量：n int，默认 1，超过 WithMaxItems 的上限时按上限处理
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：移出时需要遍历全部数据项选出 n 个(O(m log n)，m 为数据项数量)，每次只移出 1 个时
+ *           缓存满后的每次插入都要遍历；批量移出 n 个后数量降至 max-n+1，之后 n-1 次插入不再移出，
+ *           遍历开销被分摊；
+ *           代价是缓存满后实际保存的数据项最少只有 max-n+1 个
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithEvictionBatch(n int) Option {
+	return func(thisCache *Cache) {
+		if n < 1 {
+			n = 1
+		}
+		thisCache.evictBatch = n
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：启用写时复制，使 Keys、Items、ForEach 不获取锁
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：每次修改数据项的写入都要复制全部数据项，写入开销与数据项数量成正比，见 cow.go
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithCopyOnWrite() Option {
+	return func(thisCache *Cache) {
+		thisCache.cow = &cowState{}
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：设置写入方法获取写锁的超时时间
+ * 输入参数：超时时间：d time.Duration，<= 0 表示一直等待(默认)
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：超时返回 ErrLockTimeout 并累加 LockStalls，用于发现回调或大量清理长时间持有写锁；
+ *           启用后写者先取得写者令牌再加写锁，有竞争时每次加锁额外创建一个 timer，见 lockwait.go
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithLockTimeout(d time.Duration) Option {
+	return func(thisCache *Cache) {
+		thisCache.lockWait = d
+		if d > 0 {
+			thisCache.writers = make(chan struct{}, 1)
+		}
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：设置迁移解码失败的数据项的函数
+ * 输入参数：迁移函数：migrator ItemMigrator
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：只在 Load 系列方法、LoadStream、UnmarshalItem 解码单个数据项出错时调用，
+ *           解码成功的数据项不经过迁移函数；迁移函数返回 false 时跳过该数据项，其余数据项照常加载；
+ *           用于值类型跨版本修改字段后加载旧的快照，见 migrate.go
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithItemMigrator(migrator ItemMigrator) Option {
+	return func(thisCache *Cache) {
+		thisCache.migrator = migrator
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：设置观察 Get 命中情况的函数
+ * 输入参数：观察函数：observer AccessObserver
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：每次 Get 调用一次，负缓存命中与经 loader 加载的数据项报告为未命中；
+ *           位于读取的热路径上，observer 必须足够快，见 observe.go
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithAccessObserver(observer AccessObserver) Option {
+	return func(thisCache *Cache) {
+		thisCache.observer = observer
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：设置容量淘汰策略
+ * 输入参数：淘汰策略：policy EvictionPolicy
+ * 输出参数：无
+ * 返 回 值：配置项
+ * 其他说明：用于 WithMaxItems、命名空间配额与 TrimToCount/TrimToBytes，默认 LRU；
+ *           LFU 需要统计命中次数，自动启用 WithAccessTracking，见 policy.go
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WithEvictionPolicy(policy EvictionPolicy) Option {
+	return func(thisCache *Cache) {
+		thisCache.policy = policy
+		if policy == LFU {
+			thisCache.trackAccess.Store(true)
+		}
+	}
+}
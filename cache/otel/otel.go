@@ -0,0 +1,150 @@
+package otel
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：otel.go
+ * 内容摘要：为缓存的加载操作(GetOrCompute、read-through loader)添加 OpenTelemetry span。
+ * 其他说明：OpenTelemetry 依赖只存在于本子包，cache 包本身不依赖 OpenTelemetry。
+ *           span 属性：cache.key_hash 为 key 的 FNV-1a 哈希(避免高基数与泄露 key)，
+ *           cache.hit 表示是否命中，cache.load_duration_ms 为加载耗时。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"context"
+	"go-libcache/cache"
+	"hash/fnv"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+type Cache struct { // 带 tracing 的缓存，未包装的方法直接委托给内嵌的 *cache.Cache
+	*cache.Cache
+	tracer trace.Tracer // 创建 span 使用的 tracer
+}
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：创建一个带 tracing 的缓存
+ * 输入参数：被包装的缓存：cacher *cache.Cache, tracer：tracer trace.Tracer
+ * 输出参数：无
+ * 返 回 值：带 tracing 的缓存
+ * 其他说明：cacher 使用 WithLoader 时，应使用 WrapLoader 包装 loader 以记录 read-through 加载
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func New(cacher *cache.Cache, tracer trace.Tracer) *Cache {
+	return &Cache{Cache: cacher, tracer: tracer}
+}
+
+/***************************************************************************************
+ * 功能描述：带 span 的 GetOrCompute
+ * 输入参数：上下文：ctx context.Context, 数据项键名：key string, 数据项生命周期：dur time.Duration,
+ *           计算函数：fn func() (interface{}, error)
+ * 输出参数：无
+ * 返 回 值：数据项的值，无 error 则为 nil
+ * 其他说明：该函数为 Cache 类方法，整个调用为一个 cache.GetOrCompute span，
+ *           未命中时 fn 的执行为其子 span cache.load
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) GetOrCompute(ctx context.Context, key string, dur time.Duration, fn func() (interface{}, error)) (interface{}, error) {
+	ctx, span := thisCache.tracer.Start(ctx, "cache.GetOrCompute",
+		trace.WithAttributes(attribute.String("cache.key_hash", hashKey(key))))
+	defer span.End()
+
+	hit := true
+	value, err := thisCache.Cache.GetOrCompute(key, dur, func() (interface{}, error) {
+		hit = false
+		return traceLoad(ctx, thisCache.tracer, key, fn)
+	})
+	span.SetAttributes(attribute.Bool("cache.hit", hit))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return value, err
+}
+
+/***************************************************************************************
+ * 功能描述：包装 read-through loader，每次加载创建一个 cache.load span
+ * 输入参数：tracer：tracer trace.Tracer, 加载函数：loader cache.LoaderFunc
+ * 输出参数：无
+ * 返 回 值：包装后的加载函数，用于 cache.WithLoader
+ * 其他说明：loader 只在未命中时调用，因此 span 的 cache.hit 总为 false；
+ *           LoaderFunc 不带上下文，span 为根 span
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func WrapLoader(tracer trace.Tracer, loader cache.LoaderFunc) cache.LoaderFunc {
+	return func(key string) (interface{}, error) {
+		return traceLoad(context.Background(), tracer, key, func() (interface{}, error) {
+			return loader(key)
+		})
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：在 cache.load span 中执行加载函数
+ * 输入参数：上下文：ctx context.Context, tracer：tracer trace.Tracer, 数据项键名：key string,
+ *           加载函数：fn func() (interface{}, error)
+ * 输出参数：无
+ * 返 回 值：加载的值，无 error 则为 nil
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func traceLoad(ctx context.Context, tracer trace.Tracer, key string, fn func() (interface{}, error)) (interface{}, error) {
+	_, span := tracer.Start(ctx, "cache.load", trace.WithAttributes(
+		attribute.String("cache.key_hash", hashKey(key)),
+		attribute.Bool("cache.hit", false),
+	))
+	defer span.End()
+
+	start := time.Now()
+	value, err := fn()
+	span.SetAttributes(attribute.Float64("cache.load_duration_ms", float64(time.Since(start))/float64(time.Millisecond)))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return value, err
+}
+
+/***************************************************************************************
+ * 功能描述：计算 key 的 FNV-1a 哈希，用作 span 属性
+ * 输入参数：数据项键名：key string
+ * 输出参数：无
+ * 返 回 值：16 进制哈希值
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func hashKey(key string) string {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return strconv.FormatUint(h.Sum64(), 16)
+}
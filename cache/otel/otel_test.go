@@ -0,0 +1,173 @@
+package otel
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：otel_test.go
+ * 内容摘要：tracing 包装测试。
+ * 其他说明：使用 tracetest.SpanRecorder 记录结束的 span
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"context"
+	"errors"
+	"go-libcache/cache"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+/***************************************************************************************
+ * 功能描述：创建记录 span 的 tracer provider
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：tracer provider 与 span 记录器
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func newRecorder() (*sdktrace.TracerProvider, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	return sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)), recorder
+}
+
+/***************************************************************************************
+ * 功能描述：读取 span 的属性
+ * 输入参数：span sdktrace.ReadOnlySpan, 属性名：key attribute.Key
+ * 输出参数：无
+ * 返 回 值：属性值，以及是否存在
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func spanAttr(span sdktrace.ReadOnlySpan, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+/***************************************************************************************
+ * 功能描述：测试 GetOrCompute 每次调用一个 span，未命中时加载为其子 span，错误记录到 span 状态
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestGetOrComputeSpans(t *testing.T) {
+	provider, recorder := newRecorder()
+	cacher, _ := cache.NewCache(0, 0)
+	traced := New(cacher, provider.Tracer("test"))
+	ctx := context.Background()
+	compute := func() (interface{}, error) { return 1, nil }
+
+	if _, err := traced.GetOrCompute(ctx, "a", 0, compute); err != nil {
+		t.Fatal(err)
+	}
+	spans := recorder.Ended()
+	if len(spans) != 2 || spans[0].Name() != "cache.load" || spans[1].Name() != "cache.GetOrCompute" {
+		t.Fatalf("miss spans = %v, want [cache.load cache.GetOrCompute]", spans)
+	}
+	if spans[0].Parent().SpanID() != spans[1].SpanContext().SpanID() {
+		t.Error("cache.load is not a child of cache.GetOrCompute")
+	}
+	if hit, ok := spanAttr(spans[1], "cache.hit"); !ok || hit.AsBool() {
+		t.Errorf("miss cache.hit = %v, %v, want false", hit.AsBool(), ok)
+	}
+	if _, ok := spanAttr(spans[0], "cache.load_duration_ms"); !ok {
+		t.Error("cache.load has no cache.load_duration_ms")
+	}
+	if hash, _ := spanAttr(spans[1], "cache.key_hash"); hash.AsString() != hashKey("a") {
+		t.Errorf("cache.key_hash = %q, want %q", hash.AsString(), hashKey("a"))
+	}
+
+	if _, err := traced.GetOrCompute(ctx, "a", 0, compute); err != nil {
+		t.Fatal(err)
+	}
+	spans = recorder.Ended()[2:]
+	if len(spans) != 1 || spans[0].Name() != "cache.GetOrCompute" {
+		t.Fatalf("hit spans = %v, want [cache.GetOrCompute]", spans)
+	}
+	if hit, _ := spanAttr(spans[0], "cache.hit"); !hit.AsBool() {
+		t.Error("hit cache.hit = false, want true")
+	}
+
+	boom := errors.New("boom")
+	if _, err := traced.GetOrCompute(ctx, "b", 0, func() (interface{}, error) { return nil, boom }); !errors.Is(err, boom) {
+		t.Fatalf("err = %v, want boom", err)
+	}
+	spans = recorder.Ended()[3:]
+	if len(spans) != 2 {
+		t.Fatalf("error spans = %d, want 2", len(spans))
+	}
+	for _, span := range spans {
+		if span.Status().Code != codes.Error || span.Status().Description != "boom" {
+			t.Errorf("%s status = %v, want Error boom", span.Name(), span.Status())
+		}
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 WrapLoader 每次 read-through 加载一个 cache.load span，命中时不创建 span
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestWrapLoaderSpans(t *testing.T) {
+	provider, recorder := newRecorder()
+	boom := errors.New("boom")
+	loader := WrapLoader(provider.Tracer("test"), func(key string) (interface{}, error) {
+		if key == "bad" {
+			return nil, boom
+		}
+		return "v:" + key, nil
+	})
+	cacher, _ := cache.NewCache(0, 0, cache.WithLoader(loader))
+
+	for i := 0; i < 2; i++ {
+		if value, found, err := cacher.Get("a"); err != nil || !found || value != "v:a" {
+			t.Fatalf("Get(a) = %v, %v, %v", value, found, err)
+		}
+	}
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Name() != "cache.load" {
+		t.Fatalf("spans = %v, want one cache.load", spans)
+	}
+	if hit, ok := spanAttr(spans[0], "cache.hit"); !ok || hit.AsBool() {
+		t.Errorf("cache.hit = %v, %v, want false", hit.AsBool(), ok)
+	}
+
+	if _, _, err := cacher.Get("bad"); !errors.Is(err, boom) {
+		t.Fatalf("Get(bad) err = %v, want boom", err)
+	}
+	spans = recorder.Ended()[1:]
+	if len(spans) != 1 || spans[0].Status().Code != codes.Error {
+		t.Fatalf("error spans = %v, want one cache.load with Error status", spans)
+	}
+	if len(spans[0].Events()) == 0 {
+		t.Error("error not recorded as a span event")
+	}
+}
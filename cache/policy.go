@@ -0,0 +1,62 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：policy.go
+ * 内容摘要：容量淘汰策略。
+ * 其他说明：WithMaxItems 超过上限、命名空间配额以及 TrimToCount/TrimToBytes 按淘汰策略选择被移出的数据项。
+ *           各策略使用的信息都保存在 Item 中(LastAccess、Created、Hits)，数据项被任何方式移除时
+ *           随之删除，不需要额外维护：
+ *           LRU   按 LastAccess 移出最久未访问的，未启用访问记录时即最久未写入的；
+ *           FIFO  按 Created 移出最早写入的，覆盖写入视为重新写入；
+ *           LFU   按 Hits 移出访问次数最少的，次数相同时按 LastAccess，覆盖写入时 Hits 清零；
+ *           Random 移出任意的数据项，不排序，开销最小。
+ *           LRU 与 LFU 需要在 Get 命中时记录访问，WithEvictionPolicy(LFU) 与 LRU 下的 WithMaxItems
+ *           自动启用 WithAccessTracking；未启用时 LRU 按最近写入时间移出。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+
+/***************************************************************************************/
+// 数据结构与常量
+
+type EvictionPolicy int // 容量淘汰策略
+
+const (
+	LRU    EvictionPolicy = iota // 最久未访问(默认)
+	FIFO                         // 最早写入
+	LFU                          // 访问次数最少
+	Random                       // 任意
+)
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：按淘汰策略判断数据项 a 是否应先于 b 被移出
+ * 输入参数：数据项：a Item, 数据项：b Item
+ * 输出参数：无
+ * 返 回 值：a 应先被移出为 true
+ * 其他说明：该函数为 Cache 类方法，Random 时总为 false
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) evictBefore(a, b Item) bool {
+	switch thisCache.policy {
+	case FIFO:
+		return a.Created < b.Created
+	case LFU:
+		if a.Hits != b.Hits {
+			return a.Hits < b.Hits
+		}
+		return a.LastAccess < b.LastAccess
+	case Random:
+		return false
+	}
+	return a.LastAccess < b.LastAccess
+}
@@ -0,0 +1,73 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：put.go
+ * 内容摘要：按内容寻址存储数据项，键名由数据项的值计算得到。
+ * 其他说明：键名为 "类型名 + JSON 编码" 的哈希摘要(默认 SHA-256，见 WithHasher)。使用 JSON 而不是 gob，
+ *           是因为 JSON 对 map 按键排序，相同的值总是得到相同的编码；未导出字段不参与编码，
+ *           只在未导出字段上不同的两个值会得到相同的键名。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：按内容寻址存储数据项，返回键名
+ * 输入参数：数据项键值：value interface{}, 数据项生命周期：dur time.Duration
+ * 输出参数：无
+ * 返 回 值：key 数据项键名，无 error 则为 nil
+ * 其他说明：该函数为 Cache 类方法，相同的值得到相同的键名，重复存入只刷新过期时间；
+ *           无法 JSON 编码的值(如 chan、func)返回错误；生成的键名同样经过 WithKeyValidator 规范化
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) Put(value interface{}, dur time.Duration) (key string, err error) {
+	if thisCache.closed.Load() {
+		return "", ErrCacheClosed
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	if err = thisCache.checkValueSize(value); err != nil {
+		return "", err
+	}
+	var digest []byte
+	if err = thisCache.protect("hash", "", func() error {
+		sum := thisCache.hasher()
+		fmt.Fprintf(sum, "%T\n", value)
+		sum.Write(data)
+		digest = sum.Sum(nil)
+		return nil
+	}); err != nil {
+		return "", err
+	}
+	if key, err = thisCache.checkKey(hex.EncodeToString(digest)); err != nil {
+		return "", err
+	}
+
+	if err = thisCache.lockWithTimeout(); err != nil {
+		return "", err
+	}
+	evicted, reason := thisCache.overwrite(key, value, dur)
+	onEvicted := thisCache.onEvicted
+	thisCache.unlock()
+
+	fireEvicted(onEvicted, evicted, reason)
+	return key, nil
+}
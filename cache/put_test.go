@@ -0,0 +1,96 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：put_test.go
+ * 内容摘要：按内容寻址存储测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"hash/crc32"
+	"hash/fnv"
+	"testing"
+)
+
+/***************************************************************************************
+ * 功能描述：测试 Put 的键名为类型名与 JSON 编码的哈希，WithHasher 替换哈希函数
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：相同的值得到相同的键名，JSON 相同但类型不同的值得到不同的键名
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestPutHasher(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	key, err := cacher.Put(map[string]int{"b": 2, "a": 1}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte("map[string]int\n" + `{"a":1,"b":2}`))
+	if key != hex.EncodeToString(sum[:]) {
+		t.Errorf("Put key = %s, want the SHA-256 of type and JSON", key)
+	}
+	if again, _ := cacher.Put(map[string]int{"a": 1, "b": 2}, 0); again != key {
+		t.Errorf("same value gave key %s, want %s", again, key)
+	}
+	intKey, _ := cacher.Put(1, 0)
+	floatKey, _ := cacher.Put(1.0, 0)
+	if intKey == floatKey {
+		t.Error("int 1 and float64 1 gave the same key")
+	}
+
+	fast, _ := NewCache(0, 0, WithHasher(func() hash.Hash { return fnv.New64a() }))
+	key, _ = fast.Put("x", 0)
+	h := fnv.New64a()
+	h.Write([]byte("string\n" + `"x"`))
+	if key != hex.EncodeToString(h.Sum(nil)) {
+		t.Errorf("Put key with FNV = %s, want %x", key, h.Sum(nil))
+	}
+	if value, found, _ := fast.Get(key); !found || value != "x" {
+		t.Errorf("Get(%s) = %v, %v, want x", key, value, found)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 SetKey 默认使用 CRC32，WithShardHasher 替换分片哈希函数
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：分片为哈希值前 4 个字节(大端)对 10 取模
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestShardHasher(t *testing.T) {
+	shard := func(sum []byte) string {
+		return string(rune(int(binary.BigEndian.Uint32(sum)) % 10))
+	}
+	cacher, _ := NewCache(0, 0)
+	crc := crc32.NewIEEE()
+	crc.Write([]byte("user:1"))
+	if got, _ := cacher.SetKey("user:1"); got != shard(crc.Sum(nil)) {
+		t.Errorf("SetKey with CRC32 = %q, want %q", got, shard(crc.Sum(nil)))
+	}
+
+	custom, _ := NewCache(0, 0, WithShardHasher(func() hash.Hash { return fnv.New32a() }), WithShardHasher(nil))
+	h := fnv.New32a()
+	h.Write([]byte("user:1"))
+	if got, _ := custom.SetKey("user:1"); got != shard(h.Sum(nil)) {
+		t.Errorf("SetKey with FNV = %q, want %q", got, shard(h.Sum(nil)))
+	}
+}
@@ -0,0 +1,194 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：quota.go
+ * 内容摘要：按键名前缀(命名空间)限制数据项数量。
+ * 其他说明：多个租户共用一个缓存时，为每个租户的前缀设置配额，写入超过配额的命名空间时
+ *           只在该命名空间内按淘汰策略(见 policy.go)移出数据项，不影响其它命名空间。
+ *           每个配额维护属于该命名空间的键名集合，在写入与删除时增量维护；超过配额时只遍历该命名空间的
+ *           键名查找最先应移出的数据项，开销与配额大小成正比，与缓存中的数据项总数无关。
+ *           LRU 策略需要 Get 命中时记录访问时间，设置配额时自动启用 WithAccessTracking。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"strings"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+type nsQuota struct { // 命名空间的配额
+	prefix  string              // 命名空间的键名前缀
+	maxKeys int                 // 数据项数量上限
+	keys    map[string]struct{} // 以该前缀为最长匹配前缀的键名，由 mux 保护
+}
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：设置命名空间的数据项数量配额
+ * 输入参数：键名前缀：prefix string, 数据项数量上限：maxKeys int，<= 0 时取消该命名空间的配额
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，键名匹配多个前缀时使用最长的前缀；
+ *           设置时已超过配额的命名空间立即移出多余的数据项，被移出的数据项在释放锁后调用 onEvicted(Capacity)；
+ *           LRU 策略下设置配额后 Get 命中时记录访问时间，取消配额不会关闭访问记录
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) SetNamespaceQuota(prefix string, maxKeys int) {
+	prefix = thisCache.normalizePrefix(prefix)
+	thisCache.lock()
+	defer thisCache.unlock()
+
+	for i, quota := range thisCache.quotas {
+		if quota.prefix == prefix {
+			thisCache.quotas = append(thisCache.quotas[:i], thisCache.quotas[i+1:]...)
+			break
+		}
+	}
+	if maxKeys > 0 {
+		thisCache.quotas = append(thisCache.quotas, &nsQuota{prefix: prefix, maxKeys: maxKeys})
+		if thisCache.policy == LRU { // 按最久未访问移出
+			thisCache.trackAccess.Store(true)
+		}
+	}
+	thisCache.recountQuotas()
+	for _, quota := range thisCache.quotas {
+		for len(quota.keys) > quota.maxKeys {
+			thisCache.evictNamespace(quota, "")
+		}
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：查找键名所属的命名空间配额，调用时须持有锁
+ * 输入参数：数据项键名：key string
+ * 输出参数：无
+ * 返 回 值：匹配的最长前缀的配额，不属于任何命名空间时为 nil
+ * 其他说明：该函数为 Cache 类方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) quotaFor(key string) *nsQuota {
+	var matched *nsQuota
+	for _, quota := range thisCache.quotas {
+		if strings.HasPrefix(key, quota.prefix) && (matched == nil || len(quota.prefix) > len(matched.prefix)) {
+			matched = quota
+		}
+	}
+	return matched
+}
+
+/***************************************************************************************
+ * 功能描述：新增数据项时增加所属命名空间的数量，超过配额时在命名空间内移出数据项，调用时须持有写锁
+ * 输入参数：数据项键名：key string
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，由 putItem 调用，刚写入的数据项不会被移出
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) addQuota(key string) {
+	quota := thisCache.quotaFor(key)
+	if quota == nil {
+		return
+	}
+	quota.keys[key] = struct{}{}
+	if len(quota.keys) > quota.maxKeys {
+		thisCache.evictNamespace(quota, key)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：删除数据项时减少所属命名空间的数量，调用时须持有写锁
+ * 输入参数：数据项键名：key string
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) removeQuota(key string) {
+	if quota := thisCache.quotaFor(key); quota != nil {
+		delete(quota.keys, key)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：重新建立各命名空间的键名集合，调用时须持有写锁
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，用于设置配额以及 Flush、Restore 等整体替换 items 之后
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) recountQuotas() {
+	if len(thisCache.quotas) == 0 {
+		return
+	}
+	for _, quota := range thisCache.quotas {
+		quota.keys = make(map[string]struct{}, quota.maxKeys+1)
+	}
+	for key := range thisCache.items {
+		if quota := thisCache.quotaFor(key); quota != nil {
+			quota.keys[key] = struct{}{}
+		}
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：按淘汰策略移出命名空间内的一个数据项，调用时须持有写锁
+ * 输入参数：配额：quota *nsQuota, 不移出的键名：keep string
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，只遍历该配额的键名集合；
+ *           被移出的数据项记入 overflow，由 unlock 调用 onEvicted(Capacity)
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) evictNamespace(quota *nsQuota, keep string) {
+	var oldest trimEntry
+	found := false
+	for key := range quota.keys {
+		item, ok := thisCache.items[key]
+		if !ok {
+			delete(quota.keys, key)
+			continue
+		}
+		if key == keep {
+			continue
+		}
+		if !found || thisCache.evictBefore(item, oldest.item) {
+			oldest = trimEntry{key: key, item: item}
+			found = true
+		}
+	}
+	if !found {
+		return
+	}
+	evicted := thisCache.trim([]trimEntry{oldest})
+	if thisCache.onEvicted != nil {
+		thisCache.overflow = append(thisCache.overflow, evicted...)
+	}
+}
@@ -0,0 +1,90 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：quota_test.go
+ * 内容摘要：命名空间配额测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：测试命名空间配额
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：超过配额时只移出该命名空间内的数据项
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestNamespaceQuota(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	cacher.SetNamespaceQuota("user:", 2)
+	cacher.Set("order:1", 0, 0)
+	for _, key := range []string{"user:1", "user:2", "user:3"} {
+		cacher.Set(key, 0, 0)
+		time.Sleep(time.Millisecond)
+	}
+	users := 0
+	for _, key := range cacher.Keys() {
+		if strings.HasPrefix(key, "user:") {
+			users++
+		}
+	}
+	if users != 2 {
+		t.Errorf("user keys = %d, want 2", users)
+	}
+	if _, found, _ := cacher.Get("order:1"); !found {
+		t.Error("quota evicted a key outside the namespace")
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 LRU 配额按 Get 记录的访问时间移出
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：设置配额后自动记录访问时间，最近读取过的数据项保留；嵌套前缀各自计数
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestNamespaceQuotaLRU(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	cacher.SetNamespaceQuota("user:", 2)
+	cacher.SetNamespaceQuota("user:vip:", 1)
+	cacher.Set("user:1", 1, 0)
+	time.Sleep(time.Millisecond)
+	cacher.Set("user:2", 2, 0)
+	time.Sleep(time.Millisecond)
+	cacher.Get("user:1") // user:1 成为最近访问的
+	time.Sleep(time.Millisecond)
+	cacher.Set("user:vip:1", 1, 0)
+	cacher.Set("user:3", 3, 0)
+	if cacher.Has("user:2") {
+		t.Error("user:2 kept, want the least recently used key evicted")
+	}
+	for _, key := range []string{"user:1", "user:3", "user:vip:1"} {
+		if !cacher.Has(key) {
+			t.Errorf("%s evicted", key)
+		}
+	}
+	cacher.Set("user:vip:2", 2, 0)
+	if cacher.Has("user:vip:1") || !cacher.Has("user:vip:2") || !cacher.Has("user:1") {
+		t.Error("user:vip: quota evicted the wrong key")
+	}
+}
@@ -0,0 +1,87 @@
+package ratelimit
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：ratelimit.go
+ * 内容摘要：基于缓存的固定窗口限流器。
+ * 其他说明：每个 key 对应一个计数器数据项，首次访问时以窗口时长为生命周期创建，
+ *           窗口内计数超过 limit 后拒绝请求，计数器过期后进入下一个窗口。
+ *           固定窗口存在突发问题：在窗口交界处前后各放行 limit 次，
+ *           短时间内最多可能放行 2*limit 次请求。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"errors"
+	"go-libcache/cache"
+	"time"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+const maxRetries = 3 // 计数器在 Add 与 Increment 之间过期时的最大重试次数
+
+type Limiter struct { // 限流器结构
+	cacher *cache.Cache // 存储计数器的缓存
+}
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：创建一个限流器
+ * 输入参数：存储计数器的缓存：cacher *cache.Cache
+ * 输出参数：无
+ * 返 回 值：一个新的限流器
+ * 其他说明：计数器数据项与缓存中的其它数据项共用键空间，建议为 key 加上前缀
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func NewLimiter(cacher *cache.Cache) *Limiter {
+	return &Limiter{cacher: cacher}
+}
+
+/***************************************************************************************
+ * 功能描述：判断 key 在当前窗口内是否允许通过
+ * 输入参数：限流键：key string, 窗口内允许的次数：limit int, 窗口时长：window time.Duration
+ * 输出参数：无
+ * 返 回 值：允许通过为 true
+ * 其他说明：该函数为 Limiter 类方法，计数器的过期时间只在窗口首次访问时设置，
+ *           后续自增保留原过期时间，因此为固定窗口而非滑动窗口；
+ *           缓存已关闭、键名被 WithKeyValidator 拒绝等无法计数的错误均返回 false，
+ *           计数器在 Add 与 Increment 之间过期时最多重试 maxRetries 次
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisLimiter *Limiter) Allow(key string, limit int, window time.Duration) bool {
+	if len(key) == 0 || limit <= 0 || window <= 0 {
+		return false
+	}
+	for retry := 0; retry < maxRetries; retry++ {
+		err := thisLimiter.cacher.Add(key, int64(1), window)
+		if err == nil {
+			return true
+		}
+		if !errors.Is(err, cache.ErrKeyExists) {
+			return false // 缓存已关闭、键名被拒绝等，无法计数
+		}
+		count, err := thisLimiter.cacher.Increment(key, 1)
+		if err == nil {
+			return count <= int64(limit)
+		}
+		if !errors.Is(err, cache.ErrKeyNotFound) {
+			return false // 计数器不是整数类型或其它错误，拒绝
+		}
+		// 计数器在 Add 与 Increment 之间过期，重新开始一个窗口
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package ratelimit
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：ratelimit_test.go
+ * 内容摘要：固定窗口限流器测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"errors"
+	"go-libcache/cache"
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：测试窗口内超过 limit 的请求被拒绝
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：分别测试普通缓存与启用 WithSerializedValues 的缓存
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestAllow(t *testing.T) {
+	for _, opts := range [][]cache.Option{nil, {cache.WithSerializedValues(cache.GobCodec{})}} {
+		cacher, err := cache.NewCache(0, 0, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		limiter := NewLimiter(cacher)
+		for i := 0; i < 3; i++ {
+			if !limiter.Allow("ip", 3, time.Minute) {
+				t.Fatalf("request %d rejected, want allowed (options %d)", i+1, len(opts))
+			}
+		}
+		if limiter.Allow("ip", 3, time.Minute) {
+			t.Fatalf("request 4 allowed, want rejected (options %d)", len(opts))
+		}
+		if !limiter.Allow("other", 3, time.Minute) {
+			t.Fatalf("other key rejected (options %d)", len(opts))
+		}
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试缓存已关闭或键名被拒绝时 Allow 返回 false 而不是一直重试
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：在 goroutine 中调用，超时视为挂起
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestAllowUnusable(t *testing.T) {
+	closed, _ := cache.NewCache(0, 0)
+	closed.Close()
+	rejecting, _ := cache.NewCache(0, 0, cache.WithKeyValidator(func(key string) (string, error) {
+		return "", errors.New("rejected")
+	}))
+	for name, cacher := range map[string]*cache.Cache{"closed": closed, "rejected key": rejecting} {
+		done := make(chan bool, 1)
+		go func() { done <- NewLimiter(cacher).Allow("ip", 3, time.Minute) }()
+		select {
+		case allowed := <-done:
+			if allowed {
+				t.Errorf("%s: Allow = true, want false", name)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%s: Allow did not return", name)
+		}
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试计数器不是整数类型时拒绝请求
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestAllowTypeMismatch(t *testing.T) {
+	cacher, _ := cache.NewCache(0, 0)
+	cacher.Set("ip", "not a counter", 0)
+	if NewLimiter(cacher).Allow("ip", 3, time.Minute) {
+		t.Error("Allow = true on a non-integer counter, want false")
+	}
+}
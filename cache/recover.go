@@ -0,0 +1,83 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：recover.go
+ * 内容摘要：调用者提供的回调函数 panic 时的恢复。
+ * 其他说明：onEvicted、loader、GetOrCompute/GetOrLoad/GetOrLoadMulti 的加载函数、PreExpiryFunc、
+ *           索引提取函数、SaveFunc 的过滤条件、WithSizer、WithKeyValidator 与 WithHash 设置的函数均在恢复边界内调用：panic 被记录到日志并计数，不会终止 gcLoop 或调用者；
+ *           加载函数 panic 时调用者收到 ErrCallbackPanic。WithoutPanicRecovery 关闭恢复。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"errors"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+var ErrCallbackPanic = errors.New("callback panicked.") // 加载函数 panic 时返回的错误
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：在恢复边界内调用回调函数
+ * 输入参数：回调的类别：kind string, 数据项键名：key string, 回调函数：fn func() error
+ * 输出参数：无
+ * 返 回 值：fn 返回的错误，fn panic 时为 ErrCallbackPanic
+ * 其他说明：该函数为 Cache 类方法，关闭恢复时 panic 继续向上传播；在持有锁时调用的回调
+ *           (索引提取函数、SaveFunc 的过滤条件、WithSizer 设置的函数)不得调用本缓存的方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) protect(kind, key string, fn func() error) (err error) {
+	defer thisCache.recoverCallback(kind, key, &err)
+	return fn()
+}
+
+/***************************************************************************************
+ * 功能描述：恢复回调函数的 panic，必须直接以 defer 调用
+ * 输入参数：回调的类别：kind string, 数据项键名：key string, 回调的返回错误：err *error，可为 nil
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，恢复后记录日志、累加 panic 次数，并将 *err 设为 ErrCallbackPanic
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) recoverCallback(kind, key string, err *error) {
+	if thisCache.noRecover {
+		return
+	}
+	if r := recover(); r != nil {
+		thisCache.panics.Add(1)
+		thisCache.logger.Errorf("cache %s %s panicked: %v", kind, key, r)
+		if err != nil {
+			*err = ErrCallbackPanic
+		}
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：统计回调函数 panic 的次数
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：int64 自缓存创建以来被恢复的 panic 次数
+ * 其他说明：该函数为 Cache 类方法，不加锁；关闭恢复时总为 0
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) CallbackPanics() int64 {
+	return thisCache.panics.Load()
+}
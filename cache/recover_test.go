@@ -0,0 +1,134 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：recover_test.go
+ * 内容摘要：回调函数 panic 的恢复测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：测试索引提取函数、SaveFunc 过滤条件与键名校验函数 panic 后缓存仍可写入
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：panic 被恢复并计数，锁被释放
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestCallbackPanics(t *testing.T) {
+	cacher, _ := NewCache(0, 0, WithKeyValidator(func(key string) (string, error) {
+		if key == "boom" {
+			panic("validator")
+		}
+		return key, nil
+	}))
+	cacher.AddIndex("kind", func(value interface{}) (string, bool) {
+		if value == "bad" {
+			panic("extractor")
+		}
+		return "all", true
+	})
+
+	if err := cacher.Set("a", "bad", 0); err != nil {
+		t.Fatalf("Set with panicking extractor: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := cacher.SaveFunc(&buf, func(key string, item Item) bool {
+		panic("predicate")
+	}); err != nil {
+		t.Fatalf("SaveFunc with panicking predicate: %v", err)
+	}
+	if err := cacher.Set("boom", 1, 0); !errors.Is(err, ErrCallbackPanic) {
+		t.Errorf("Set with panicking validator: %v, want ErrCallbackPanic", err)
+	}
+	if got := cacher.CallbackPanics(); got != 3 {
+		t.Errorf("CallbackPanics = %d, want 3", got)
+	}
+
+	if err := cacher.Set("b", "good", 0); err != nil {
+		t.Fatalf("Set after panics: %v", err)
+	}
+	if value, found, _ := cacher.Get("b"); !found || value != "good" {
+		t.Errorf("b = %v, %v, want good", value, found)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试关闭恢复时 panic 传播给调用者，写锁仍被释放
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestCallbackPanicWithoutRecovery(t *testing.T) {
+	cacher, _ := NewCache(0, 0, WithoutPanicRecovery())
+	cacher.AddIndex("kind", func(value interface{}) (string, bool) {
+		if value == "bad" {
+			panic("extractor")
+		}
+		return "all", true
+	})
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("panic was not propagated")
+			}
+		}()
+		cacher.Set("a", "bad", 0)
+	}()
+
+	done := make(chan error, 1)
+	go func() { done <- cacher.Set("b", "good", 0) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Set after panic: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Set blocked: write lock left held by the panic")
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 WithSizer 设置的函数 panic 时回退到默认估算
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestSizerPanic(t *testing.T) {
+	cacher, _ := NewCache(0, 0, WithSizer(func(value interface{}) (int64, bool) {
+		panic("sizer")
+	}))
+	cacher.Set("a", "value", 0)
+	if got := cacher.EstimatedBytes(); got <= 0 {
+		t.Errorf("EstimatedBytes = %d, want default estimate", got)
+	}
+	if err := cacher.Set("b", "value", 0); err != nil {
+		t.Fatalf("Set after sizer panic: %v", err)
+	}
+}
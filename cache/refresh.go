@@ -0,0 +1,96 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：refresh.go
+ * 内容摘要：在数据项即将过期之前刷新其值(pre-expiry refresh)。
+ * 其他说明：gcLoop 每次清理后找出 lead 之内即将过期的数据项，逐个调用刷新函数，
+ *           刷新函数返回的新值与生命周期原地替换旧值，使热点数据项不会因过期而未命中。
+ *           刷新函数在 gcLoop 中同步调用，不持有锁；gcLoop 的周期应小于 lead，否则数据项可能
+ *           在两次清理之间过期而错过刷新。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"time"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+// 刷新即将过期的数据项，返回新值、新的生命周期，false 表示不刷新(数据项按原过期时间过期)
+type PreExpiryFunc func(key string, value interface{}) (interface{}, time.Duration, bool)
+
+type refreshEntry struct { // 待刷新的数据项
+	key     string      // 数据项键名
+	value   interface{} // 数据项的值
+	version uint64      // 收集时的版本号，刷新期间被改写的数据项不再替换
+}
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：刷新 lead 之内即将过期的数据项
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：int 被刷新的数据项数量
+ * 其他说明：该函数为 Cache 类方法，由 gcLoop 在每次清理后调用，未设置刷新函数时不做任何事；
+ *           每个数据项在一次调用中只刷新一次；刷新期间数据项被改写或删除时丢弃刷新结果；
+ *           原地替换不调用 onEvicted
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) refreshExpiring() int {
+	if thisCache.preExpiry == nil {
+		return 0
+	}
+	now := nanotime()
+	deadline := now + int64(thisCache.preExpiryLead)
+	var entries []refreshEntry
+	thisCache.mux.RLock()
+	for key, val := range thisCache.items {
+		if val.Expiration > 0 && val.Expiration <= deadline && !thisCache.expired(val, now) && !val.negative() {
+			entries = append(entries, refreshEntry{key, val.Object, val.Version})
+		}
+	}
+	thisCache.mux.RUnlock()
+
+	refreshed := 0
+	for _, entry := range entries {
+		var value interface{}
+		var dur time.Duration
+		var ok bool
+		old, err := thisCache.decodeValue(entry.value)
+		if err != nil {
+			continue
+		}
+		if thisCache.protect("preExpiry", entry.key, func() error {
+			value, dur, ok = thisCache.preExpiry(entry.key, old)
+			return nil
+		}) != nil {
+			continue
+		}
+		if !ok || thisCache.checkValueSize(value) != nil {
+			continue
+		}
+		if thisCache.lockWithTimeout() != nil { // 与 gcLoop 一样跳过本轮
+			break
+		}
+		if item, found := thisCache.items[entry.key]; found && item.Version == entry.version {
+			thisCache.set(entry.key, value, dur)
+			refreshed++
+		}
+		thisCache.unlock()
+	}
+	if refreshed > 0 {
+		thisCache.logger.Debugf("cache refresh: refreshed %d of %d expiring items", refreshed, len(entries))
+	}
+	return refreshed
+}
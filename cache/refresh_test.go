@@ -0,0 +1,70 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：refresh_test.go
+ * 内容摘要：过期前刷新测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：测试 refreshExpiring 只对 lead 之内即将过期的数据项调用刷新函数，并以返回的新值与生命周期替换
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：刷新函数返回 false 或刷新期间数据项被改写时不替换；直接调用 refreshExpiring 代替 gcLoop
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestPreExpiryHook(t *testing.T) {
+	var cacher *Cache
+	var called []string
+	cacher, _ = NewCache(0, 0, WithPreExpiryHook(time.Minute, func(key string, value interface{}) (interface{}, time.Duration, bool) {
+		called = append(called, key)
+		switch key {
+		case "cold":
+			return nil, 0, false
+		case "raced":
+			cacher.Set("raced", "written", time.Hour) // 刷新期间被改写
+		}
+		return value.(int) + 1, time.Hour, true
+	}))
+	cacher.Set("hot", 1, 30*time.Second)
+	cacher.Set("cold", 1, 30*time.Second)
+	cacher.Set("raced", 1, 30*time.Second)
+	cacher.Set("far", 1, time.Hour)
+	cacher.Set("never", 1, NoExpiration)
+
+	if n := cacher.refreshExpiring(); n != 1 {
+		t.Errorf("refreshExpiring = %d, want 1", n)
+	}
+	sort.Strings(called)
+	if fmt.Sprint(called) != "[cold hot raced]" {
+		t.Errorf("hook called for %v, want [cold hot raced]", called)
+	}
+	ttls := cacher.Expirations()
+	if value, _, _ := cacher.Get("hot"); value != 2 || ttls["hot"] <= 59*time.Minute {
+		t.Errorf("hot = %v with ttl %v, want 2 with about 1h", value, ttls["hot"])
+	}
+	if value, _, _ := cacher.Get("cold"); value != 1 || ttls["cold"] > 30*time.Second {
+		t.Errorf("cold = %v with ttl %v, want unchanged", value, ttls["cold"])
+	}
+	if value, _, _ := cacher.Get("raced"); value != "written" {
+		t.Errorf("raced = %v, want the concurrent write", value)
+	}
+}
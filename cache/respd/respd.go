@@ -0,0 +1,527 @@
+package respd
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：respd.go
+ * 内容摘要：最小化的 RESP2(Redis 协议)服务端，使 Redis 客户端可以访问缓存。
+ * 其他说明：支持 GET、SET(可选 EX/PX)、DEL、EXPIRE、TTL、FLUSHALL，以及客户端常用的 PING。
+ *           支持 multibulk 与 inline 两种命令格式。值以 []byte 存储；
+ *           与 Redis 一致，不带 EX/PX 的 SET 永不过期(NoExpiration)。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"go-libcache/cache"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+type Server struct { // RESP 服务端结构
+	cacher   *cache.Cache          // 被访问的缓存
+	mux      sync.Mutex            // 保护 listener、conns、closed
+	listener net.Listener          // 正在监听的 listener
+	conns    map[net.Conn]struct{} // 当前的客户端连接
+	closed   bool                  // 是否已关闭
+}
+
+const (
+	maxBulkLen  = 512 * 1024 * 1024 // 单个参数的最大长度，与 Redis 的 proto-max-bulk-len 默认值相同
+	maxArgCount = 1024 * 1024       // 单个命令的最大参数个数
+)
+
+var (
+	ErrServerClosed = errors.New("resp server closed.")
+	errProtocol     = errors.New("Protocol error")
+)
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：创建一个 RESP 服务端
+ * 输入参数：被访问的缓存：cacher *cache.Cache
+ * 输出参数：无
+ * 返 回 值：一个新的 RESP 服务端
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func NewServer(cacher *cache.Cache) *Server {
+	return &Server{
+		cacher: cacher,
+		conns:  map[net.Conn]struct{}{},
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：监听 addr 并处理客户端连接
+ * 输入参数：监听地址：addr string，如 "127.0.0.1:6379"
+ * 输出参数：无
+ * 返 回 值：Close 后返回 ErrServerClosed
+ * 其他说明：该函数为 Server 类方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisServer *Server) ListenAndServe(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return thisServer.Serve(listener)
+}
+
+/***************************************************************************************
+ * 功能描述：在 listener 上接受并处理客户端连接
+ * 输入参数：listener net.Listener
+ * 输出参数：无
+ * 返 回 值：Close 后返回 ErrServerClosed
+ * 其他说明：该函数为 Server 类方法，每个连接一个 goroutine
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisServer *Server) Serve(listener net.Listener) error {
+	thisServer.mux.Lock()
+	if thisServer.closed {
+		thisServer.mux.Unlock()
+		listener.Close()
+		return ErrServerClosed
+	}
+	thisServer.listener = listener
+	thisServer.mux.Unlock()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			thisServer.mux.Lock()
+			closed := thisServer.closed
+			thisServer.mux.Unlock()
+			if closed {
+				return ErrServerClosed
+			}
+			return err
+		}
+		thisServer.mux.Lock()
+		if thisServer.closed {
+			thisServer.mux.Unlock()
+			conn.Close()
+			return ErrServerClosed
+		}
+		thisServer.conns[conn] = struct{}{}
+		thisServer.mux.Unlock()
+		go thisServer.serveConn(conn)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：关闭服务端，停止监听并断开所有客户端连接
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：无 error， 则为 nil
+ * 其他说明：该函数为 Server 类方法，不会关闭缓存本身
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisServer *Server) Close() error {
+	thisServer.mux.Lock()
+	defer thisServer.mux.Unlock()
+	if thisServer.closed {
+		return nil
+	}
+	thisServer.closed = true
+	var err error
+	if thisServer.listener != nil {
+		err = thisServer.listener.Close()
+	}
+	for conn := range thisServer.conns {
+		conn.Close()
+	}
+	return err
+}
+
+/***************************************************************************************
+ * 功能描述：处理一个客户端连接上的命令，直到连接断开
+ * 输入参数：客户端连接：conn net.Conn
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Server 类方法，读缓冲区中没有后续命令时才 flush，支持 pipeline；
+ *           处理过程中发生 panic 时断开该连接
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisServer *Server) serveConn(conn net.Conn) {
+	defer func() {
+		recover() // 单个连接上的 panic 只断开该连接，不影响服务端与其它连接
+		thisServer.mux.Lock()
+		delete(thisServer.conns, conn)
+		thisServer.mux.Unlock()
+		conn.Close()
+	}()
+
+	rd := bufio.NewReader(conn)
+	wrt := bufio.NewWriter(conn)
+	for {
+		args, err := readCommand(rd)
+		if err != nil {
+			if errors.Is(err, errProtocol) {
+				writeError(wrt, "ERR "+err.Error())
+				wrt.Flush()
+			}
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+		thisServer.execute(wrt, args)
+		if rd.Buffered() == 0 {
+			if err = wrt.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：执行一条命令并写入回复
+ * 输入参数：回复写入：wrt *bufio.Writer, 命令与参数：args [][]byte
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Server 类方法，命令名不区分大小写
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisServer *Server) execute(wrt *bufio.Writer, args [][]byte) {
+	cmd := strings.ToUpper(string(args[0]))
+	switch cmd {
+	case "PING":
+		if len(args) > 2 {
+			writeArityError(wrt, cmd)
+		} else if len(args) == 2 {
+			writeBulk(wrt, args[1])
+		} else {
+			wrt.WriteString("+PONG\r\n")
+		}
+	case "GET":
+		if len(args) != 2 {
+			writeArityError(wrt, cmd)
+			return
+		}
+		value, found, err := thisServer.cacher.Get(string(args[1]))
+		if err != nil {
+			writeError(wrt, "ERR "+err.Error())
+			return
+		}
+		if !found {
+			wrt.WriteString("$-1\r\n")
+			return
+		}
+		switch v := value.(type) {
+		case []byte:
+			writeBulk(wrt, v)
+		case string:
+			writeBulk(wrt, []byte(v))
+		default:
+			writeError(wrt, "WRONGTYPE Operation against a key holding the wrong kind of value")
+		}
+	case "SET":
+		thisServer.set(wrt, args)
+	case "DEL":
+		if len(args) < 2 {
+			writeArityError(wrt, cmd)
+			return
+		}
+		deleted := 0
+		for _, key := range args[1:] {
+			existed := thisServer.cacher.Has(string(key))
+			if err := thisServer.cacher.Delete(string(key)); err != nil {
+				writeError(wrt, "ERR "+err.Error())
+				return
+			}
+			if existed {
+				deleted++
+			}
+		}
+		writeInteger(wrt, int64(deleted))
+	case "EXPIRE":
+		if len(args) != 3 {
+			writeArityError(wrt, cmd)
+			return
+		}
+		seconds, err := strconv.ParseInt(string(args[2]), 10, 64)
+		if err != nil {
+			writeError(wrt, "ERR value is not an integer or out of range")
+			return
+		}
+		key := string(args[1])
+		if seconds <= 0 { // 与 Redis 一致，非正数的过期时间立即删除
+			existed := thisServer.cacher.Has(key)
+			if err := thisServer.cacher.Delete(key); err != nil {
+				writeError(wrt, "ERR "+err.Error())
+				return
+			}
+			writeBool(wrt, existed)
+			return
+		}
+		writeBool(wrt, thisServer.cacher.Touch(key, time.Duration(seconds)*time.Second))
+	case "TTL":
+		if len(args) != 2 {
+			writeArityError(wrt, cmd)
+			return
+		}
+		values := thisServer.cacher.GetMultiWithExpiration([]string{string(args[1])})
+		value, found := values[string(args[1])]
+		switch {
+		case !found:
+			writeInteger(wrt, -2)
+		case value.Expiration.IsZero():
+			writeInteger(wrt, -1)
+		default:
+			remaining := time.Until(value.Expiration) + time.Second/2 // 四舍五入到秒
+			writeInteger(wrt, int64(remaining/time.Second))
+		}
+	case "FLUSHALL":
+		thisServer.cacher.Flush()
+		wrt.WriteString("+OK\r\n")
+	default:
+		writeError(wrt, fmt.Sprintf("ERR unknown command '%s'", args[0]))
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：执行 SET key value [EX seconds|PX milliseconds]
+ * 输入参数：回复写入：wrt *bufio.Writer, 命令与参数：args [][]byte
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Server 类方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisServer *Server) set(wrt *bufio.Writer, args [][]byte) {
+	if len(args) != 3 && len(args) != 5 {
+		if len(args) < 3 {
+			writeArityError(wrt, "SET")
+		} else {
+			writeError(wrt, "ERR syntax error")
+		}
+		return
+	}
+	dur := cache.NoExpiration
+	if len(args) == 5 {
+		n, err := strconv.ParseInt(string(args[4]), 10, 64)
+		if err != nil {
+			writeError(wrt, "ERR value is not an integer or out of range")
+			return
+		}
+		if n <= 0 {
+			writeError(wrt, "ERR invalid expire time in 'set' command")
+			return
+		}
+		switch strings.ToUpper(string(args[3])) {
+		case "EX":
+			dur = time.Duration(n) * time.Second
+		case "PX":
+			dur = time.Duration(n) * time.Millisecond
+		default:
+			writeError(wrt, "ERR syntax error")
+			return
+		}
+	}
+	value := append([]byte(nil), args[2]...)
+	if err := thisServer.cacher.Set(string(args[1]), value, dur); err != nil {
+		writeError(wrt, "ERR "+err.Error())
+		return
+	}
+	wrt.WriteString("+OK\r\n")
+}
+
+/***************************************************************************************
+ * 功能描述：读取一条命令，支持 multibulk 与 inline 格式
+ * 输入参数：rd *bufio.Reader
+ * 输出参数：无
+ * 返 回 值：命令与参数，以及 error
+ * 其他说明：格式错误(包括 multibulk 长度为 -1 以外的负数)时返回 errProtocol；
+ *           multibulk 长度为 -1(空数组)时返回空命令
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func readCommand(rd *bufio.Reader) ([][]byte, error) {
+	line, err := readLine(rd)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 || line[0] != '*' { // inline 命令
+		fields := strings.Fields(string(line))
+		args := make([][]byte, len(fields))
+		for i, field := range fields {
+			args[i] = []byte(field)
+		}
+		return args, nil
+	}
+
+	count, err := strconv.Atoi(string(line[1:]))
+	if err != nil || count < -1 || count > maxArgCount {
+		return nil, fmt.Errorf("%w: invalid multibulk length", errProtocol)
+	}
+	if count == -1 { // RESP 空数组，按空命令处理
+		return nil, nil
+	}
+	args := make([][]byte, 0, count)
+	for i := 0; i < count; i++ {
+		line, err = readLine(rd)
+		if err != nil {
+			return nil, err
+		}
+		if len(line) == 0 || line[0] != '$' {
+			return nil, fmt.Errorf("%w: expected '$', got '%s'", errProtocol, line)
+		}
+		n, err := strconv.Atoi(string(line[1:]))
+		if err != nil || n < 0 || n > maxBulkLen {
+			return nil, fmt.Errorf("%w: invalid bulk length", errProtocol)
+		}
+		arg := make([]byte, n+2)
+		if _, err = io.ReadFull(rd, arg); err != nil {
+			return nil, err
+		}
+		if arg[n] != '\r' || arg[n+1] != '\n' {
+			return nil, fmt.Errorf("%w: bulk not terminated by CRLF", errProtocol)
+		}
+		args = append(args, arg[:n])
+	}
+	return args, nil
+}
+
+/***************************************************************************************
+ * 功能描述：读取一行，去掉行尾的 \r\n 或 \n
+ * 输入参数：rd *bufio.Reader
+ * 输出参数：无
+ * 返 回 值：行内容，以及 error
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func readLine(rd *bufio.Reader) ([]byte, error) {
+	line, err := rd.ReadSlice('\n')
+	if err == bufio.ErrBufferFull {
+		return nil, fmt.Errorf("%w: too big inline request", errProtocol)
+	}
+	if err != nil {
+		return nil, err
+	}
+	line = line[:len(line)-1]
+	if len(line) > 0 && line[len(line)-1] == '\r' {
+		line = line[:len(line)-1]
+	}
+	return line, nil
+}
+
+/***************************************************************************************
+ * 功能描述：写入错误回复
+ * 输入参数：回复写入：wrt *bufio.Writer, 错误信息：msg string
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：写入错误在 flush 时处理
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func writeError(wrt *bufio.Writer, msg string) {
+	wrt.WriteString("-" + msg + "\r\n")
+}
+
+/***************************************************************************************
+ * 功能描述：写入参数个数错误回复
+ * 输入参数：回复写入：wrt *bufio.Writer, 命令名：cmd string
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：写入错误在 flush 时处理
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func writeArityError(wrt *bufio.Writer, cmd string) {
+	writeError(wrt, fmt.Sprintf("ERR wrong number of arguments for '%s' command", strings.ToLower(cmd)))
+}
+
+/***************************************************************************************
+ * 功能描述：写入整数回复
+ * 输入参数：回复写入：wrt *bufio.Writer, 整数：n int64
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：写入错误在 flush 时处理
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func writeInteger(wrt *bufio.Writer, n int64) {
+	wrt.WriteString(":" + strconv.FormatInt(n, 10) + "\r\n")
+}
+
+/***************************************************************************************
+ * 功能描述：写入 0/1 整数回复
+ * 输入参数：回复写入：wrt *bufio.Writer, 布尔值：b bool
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：写入错误在 flush 时处理
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func writeBool(wrt *bufio.Writer, b bool) {
+	if b {
+		writeInteger(wrt, 1)
+	} else {
+		writeInteger(wrt, 0)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：写入 bulk string 回复
+ * 输入参数：回复写入：wrt *bufio.Writer, 数据：data []byte
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：写入错误在 flush 时处理
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func writeBulk(wrt *bufio.Writer, data []byte) {
+	wrt.WriteString("$" + strconv.Itoa(len(data)) + "\r\n")
+	wrt.Write(data)
+	wrt.WriteString("\r\n")
+}
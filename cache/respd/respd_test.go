@@ -0,0 +1,122 @@
+package respd
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：respd_test.go
+ * 内容摘要：RESP 服务端测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"bufio"
+	"errors"
+	"go-libcache/cache"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：启动一个处理 net.Pipe 连接的服务端
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：客户端连接与读取客户端回复的 reader
+ * 其他说明：测试结束时关闭连接
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func startPipe(t *testing.T) (net.Conn, *bufio.Reader) {
+	cacher, err := cache.NewCache(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := NewServer(cacher)
+	client, conn := net.Pipe()
+	server.conns[conn] = struct{}{}
+	go server.serveConn(conn)
+	t.Cleanup(func() { client.Close() })
+	client.SetDeadline(time.Now().Add(5 * time.Second))
+	return client, bufio.NewReader(client)
+}
+
+/***************************************************************************************
+ * 功能描述：测试 multibulk 长度为负数的命令
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：-1 为空命令，其它负数为协议错误，均不得 panic
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestReadCommandNegativeCount(t *testing.T) {
+	args, err := readCommand(bufio.NewReader(strings.NewReader("*-5\r\n")))
+	if !errors.Is(err, errProtocol) || args != nil {
+		t.Fatalf("*-5: args %v, err %v, want errProtocol", args, err)
+	}
+	args, err = readCommand(bufio.NewReader(strings.NewReader("*-1\r\n")))
+	if err != nil || len(args) != 0 {
+		t.Fatalf("*-1: args %v, err %v, want empty command", args, err)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试客户端发送负数 multibulk 长度时服务端返回协议错误而不是崩溃
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestServeConnNegativeCount(t *testing.T) {
+	client, rd := startPipe(t)
+	if _, err := client.Write([]byte("*-1\r\nPING\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	if line, err := rd.ReadString('\n'); err != nil || line != "+PONG\r\n" {
+		t.Fatalf("PING after *-1: %q, %v", line, err)
+	}
+	if _, err := client.Write([]byte("*-5\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	line, err := rd.ReadString('\n')
+	if err != nil || !strings.HasPrefix(line, "-ERR Protocol error") {
+		t.Fatalf("*-5: %q, %v, want protocol error", line, err)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 SET 与 GET
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestServeConnSetGet(t *testing.T) {
+	client, rd := startPipe(t)
+	if _, err := client.Write([]byte("*3\r\n$3\r\nSET\r\n$1\r\nk\r\n$2\r\nv1\r\nGET k\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"+OK\r\n", "$2\r\n", "v1\r\n"} {
+		if line, err := rd.ReadString('\n'); err != nil || line != want {
+			t.Fatalf("got %q, %v, want %q", line, err, want)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：rotate_test.go
+ * 内容摘要：轮转保存快照的测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+/***************************************************************************************
+ * 功能描述：读取快照中 version 的值
+ * 输入参数：t *testing.T, 文件名：file string
+ * 输出参数：无
+ * 返 回 值：version 的值
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func snapshotVersion(t *testing.T, file string) interface{} {
+	t.Helper()
+	cacher, _ := NewCache(0, 0)
+	if err := cacher.LoadFileToMem(file); err != nil {
+		t.Fatalf("load %s: %v", file, err)
+	}
+	value, _, _ := cacher.Get("version")
+	return value
+}
+
+/***************************************************************************************
+ * 功能描述：列出目录中的文件名
+ * 输入参数：t *testing.T, 目录：dir string
+ * 输出参数：无
+ * 返 回 值：排序后的文件名
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func listDir(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+/***************************************************************************************
+ * 功能描述：测试多次保存后只保留 keep 份历史快照，且顺序正确
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestSaveMemToFileRotating(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "cache.dat")
+	cacher, _ := NewCache(0, 0)
+	for i := 1; i <= 5; i++ {
+		cacher.Set("version", i, 0)
+		if err := cacher.SaveMemToFileRotating(base, 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []string{"cache.dat", "cache.dat.1", "cache.dat.2"}
+	if got := listDir(t, dir); len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+	for i, file := range want {
+		if got := snapshotVersion(t, filepath.Join(dir, file)); got != 5-i {
+			t.Errorf("%s version = %v, want %d", file, got, 5-i)
+		}
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试最后的重命名失败时恢复 basePath 与历史快照
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：替换 renameFile 使重命名为 basePath 失败
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestSaveMemToFileRotatingRestore(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "cache.dat")
+	cacher, _ := NewCache(0, 0)
+	for i := 1; i <= 3; i++ {
+		cacher.Set("version", i, 0)
+		if err := cacher.SaveMemToFileRotating(base, 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	errRename := errors.New("rename failed")
+	renameFile = func(from, to string) error {
+		if to == base && filepath.Ext(from) != ".1" {
+			return errRename
+		}
+		return os.Rename(from, to)
+	}
+	defer func() { renameFile = os.Rename }()
+	cacher.Set("version", 4, 0)
+	if err := cacher.SaveMemToFileRotating(base, 2); !errors.Is(err, errRename) {
+		t.Fatalf("SaveMemToFileRotating: %v, want rename failure", err)
+	}
+
+	want := []string{"cache.dat", "cache.dat.1", "cache.dat.2"}
+	if got := listDir(t, dir); len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+	for i, file := range want {
+		if got := snapshotVersion(t, filepath.Join(dir, file)); got != 3-i {
+			t.Errorf("%s version = %v, want %d", file, got, 3-i)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：scan.go
+ * 内容摘要：以游标分页遍历键名(类似 Redis 的 SCAN)。
+ * 其他说明：键名按 FNV-1a 哈希值排序，游标为下一页起始的哈希值，0 表示从头开始，返回 0 表示遍历结束。
+ *           Scan(0, ...) 时获取一次键名快照并按哈希排序，之后的分页在快照上进行，每页只短暂持有读锁
+ *           检查数据项是否仍然存在。遍历期间一直存在的键名恰好返回一次；遍历开始后写入的键名可能
+ *           不被返回，期间删除或过期的键名不返回。多个遍历共用最近一次的快照。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"hash/fnv"
+	"math"
+	"sort"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+type scanEntry struct { // 键名快照中的一项
+	hash uint64 // 键名的 FNV-1a 哈希值
+	key  string // 数据项键名
+}
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：从游标开始返回最多 count 个键名
+ * 输入参数：游标：cursor uint64，0 表示从头开始, 每页数量：count int
+ * 输出参数：无
+ * 返 回 值：keys 本页的键名，next 下一页的游标，为 0 表示遍历结束
+ * 其他说明：该函数为 Cache 类方法，count 小于 1 时为 10；哈希值相同的键名总在同一页返回，
+ *           因此一页可能略多于 count 个；返回的键名不包含已过期的数据项与墓碑数据项
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) Scan(cursor uint64, count int) (keys []string, next uint64) {
+	if count < 1 {
+		count = 10
+	}
+	snap := thisCache.scanSnapshot(cursor == 0)
+	start := sort.Search(len(snap), func(i int) bool { return snap[i].hash >= cursor })
+
+	now := nanotime()
+	end := start
+	thisCache.mux.RLock()
+	for ; end < len(snap); end++ {
+		if len(keys) >= count && snap[end].hash != snap[end-1].hash {
+			break
+		}
+		item, found := thisCache.items[snap[end].key]
+		if found && !thisCache.expired(item, now) && !item.negative() {
+			keys = append(keys, snap[end].key)
+		}
+	}
+	thisCache.mux.RUnlock()
+
+	if end == len(snap) || snap[end-1].hash == math.MaxUint64 {
+		return keys, 0
+	}
+	return keys, snap[end-1].hash + 1
+}
+
+/***************************************************************************************
+ * 功能描述：获取按哈希排序的键名快照
+ * 输入参数：是否重新获取：fresh bool
+ * 输出参数：无
+ * 返 回 值：[]scanEntry 键名快照
+ * 其他说明：该函数为 Cache 类方法，fresh 为 true 或尚无快照时在读锁内复制全部键名，在锁外计算哈希与排序
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) scanSnapshot(fresh bool) []scanEntry {
+	thisCache.scanMux.Lock()
+	defer thisCache.scanMux.Unlock()
+	if !fresh && thisCache.scanSnap != nil {
+		return thisCache.scanSnap
+	}
+
+	thisCache.mux.RLock()
+	snap := make([]scanEntry, 0, len(thisCache.items))
+	for key := range thisCache.items {
+		snap = append(snap, scanEntry{key: key})
+	}
+	thisCache.mux.RUnlock()
+
+	h := fnv.New64a()
+	for i := range snap {
+		h.Reset()
+		h.Write([]byte(snap[i].key))
+		snap[i].hash = h.Sum64()
+	}
+	sort.Slice(snap, func(i, j int) bool { return snap[i].hash < snap[j].hash })
+	thisCache.scanSnap = snap
+	return snap
+}
@@ -0,0 +1,73 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：scan_test.go
+ * 内容摘要：游标分页遍历测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"fmt"
+	"testing"
+)
+
+/***************************************************************************************
+ * 功能描述：测试 Scan：遍历期间一直存在的键名恰好返回一次，期间删除的键名不返回，返回游标 0 表示结束
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestScan(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	for i := 0; i < 100; i++ {
+		cacher.Set(fmt.Sprintf("k%d", i), i, NoExpiration)
+	}
+
+	seen := map[string]int{}
+	deleted := ""
+	var cursor uint64
+	for page := 0; ; page++ {
+		keys, next := cacher.Scan(cursor, 7)
+		if len(keys) > 7 {
+			t.Fatalf("page %d has %d keys, want at most 7", page, len(keys))
+		}
+		for _, key := range keys {
+			seen[key]++
+		}
+		if page == 0 {
+			for i := 0; len(deleted) == 0; i++ {
+				if key := fmt.Sprintf("k%d", i); seen[key] == 0 {
+					deleted = key
+				}
+			}
+			cacher.Delete(deleted)
+		}
+		if next == 0 {
+			break
+		}
+		if page > 100 {
+			t.Fatal("Scan did not finish")
+		}
+		cursor = next
+	}
+	if seen[deleted] != 0 {
+		t.Errorf("%s deleted during the scan was returned", deleted)
+	}
+	for i := 0; i < 100; i++ {
+		if key := fmt.Sprintf("k%d", i); key != deleted && seen[key] != 1 {
+			t.Errorf("%s returned %d times, want 1", key, seen[key])
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：serial.go
+ * 内容摘要：以编码后的 []byte 存储数据项的值。
+ * 其他说明：通过 WithSerializedValues 启用后，缓存中保存的 Item.Object 总是 codec 编码后的 []byte，
+ *           调用者修改传入或返回的值都不会影响缓存，且 Size/TrimToBytes 按编码后的长度计算。
+ *           开销：每次写入在写锁内编码一次，每次读取(Get、GetMulti、onEvicted、订阅事件等)解码一次，
+ *           与 WithCopyOnGet 的 gob 复制相当；解码得到的已是副本，因此不再调用 WithCopyOnGet 的复制函数。
+ *           JSONCodec 解码后不保留原类型(结构体变为 map[string]interface{}，整数变为 float64)。
+ *           Increment 在写锁内解码、自增后重新编码；索引的提取函数收到解码后的值；
+ *           Snapshot、Items、SortedItems、Drain 返回解码后的值，Restore 与 LoadMerge 的 resolve 返回值存入时重新编码；
+ *           Save 保存的是编码后的值，Load 时须使用相同的 codec 配置。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"bytes"
+)
+
+/***************************************************************************************
+ * 功能描述：按 WithSerializedValues 配置编码将要存入的值
+ * 输入参数：值：value interface{}
+ * 输出参数：无
+ * 返 回 值：编码后的 []byte，未启用时返回原值；无 error 则为 nil
+ * 其他说明：该函数为 Cache 类方法，墓碑数据项的值不编码
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) encodeValue(value interface{}) (interface{}, error) {
+	if thisCache.valueCodec == nil || (Item{Object: value}).negative() {
+		return value, nil
+	}
+	var buf bytes.Buffer
+	if err := thisCache.valueCodec.NewEncoder(&buf).Encode(&Item{Object: value}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+/***************************************************************************************
+ * 功能描述：按 WithSerializedValues 配置解码缓存中保存的值
+ * 输入参数：缓存中保存的值：value interface{}
+ * 输出参数：无
+ * 返 回 值：解码后的值，未启用时返回原值；无 error 则为 nil
+ * 其他说明：该函数为 Cache 类方法，只读取 valueCodec，持有锁与否均可调用；
+ *           读取路径应在释放锁后调用，避免在锁内解码
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) decodeValue(value interface{}) (interface{}, error) {
+	data, ok := value.([]byte)
+	if thisCache.valueCodec == nil || !ok {
+		return value, nil
+	}
+	var item Item
+	if err := thisCache.valueCodec.NewDecoder(bytes.NewReader(data)).Decode(&item); err != nil {
+		thisCache.logger.Errorf("cache decode value: %v", err)
+		return nil, err
+	}
+	return item.Object, nil
+}
+
+/***************************************************************************************
+ * 功能描述：将缓存中保存的值转换为返回给调用者的值
+ * 输入参数：缓存中保存的值：value interface{}
+ * 输出参数：无
+ * 返 回 值：解码或复制后的值，无 error 则为 nil
+ * 其他说明：该函数为 Cache 类方法，不持有锁时调用；启用 WithSerializedValues 时解码，否则按 WithCopyOnGet 复制
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) readValue(value interface{}) (interface{}, error) {
+	if thisCache.valueCodec != nil {
+		return thisCache.decodeValue(value)
+	}
+	return thisCache.copyValue(value)
+}
+
+/***************************************************************************************
+ * 功能描述：将缓存中保存的数据项转换为值已解码的数据项
+ * 输入参数：缓存中保存的数据项：item Item
+ * 输出参数：无
+ * 返 回 值：值已解码的数据项，无 error 则为 nil
+ * 其他说明：该函数为 Cache 类方法，未启用 WithSerializedValues 时原样返回；
+ *           用于 Snapshot、Items 等返回 Item 的方法，以及需要原始值的 Increment、索引
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) plainItem(item Item) (Item, error) {
+	if thisCache.valueCodec == nil || item.negative() {
+		return item, nil
+	}
+	value, err := thisCache.decodeValue(item.Object)
+	if err != nil {
+		return item, err
+	}
+	item.Object = value
+	return item, nil
+}
+
+/***************************************************************************************
+ * 功能描述：将值未编码的数据项转换为缓存中保存的数据项
+ * 输入参数：值未编码的数据项：item Item
+ * 输出参数：无
+ * 返 回 值：值已编码的数据项，无 error 则为 nil
+ * 其他说明：该函数为 Cache 类方法，未启用 WithSerializedValues 时原样返回；
+ *           与 plainItem 相对，用于 Restore、LoadMerge 等存入调用者提供的 Item 的方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) storedItem(item Item) (Item, error) {
+	if thisCache.valueCodec == nil {
+		return item, nil
+	}
+	value, err := thisCache.encodeValue(item.Object)
+	if err != nil {
+		return item, err
+	}
+	item.Object = value
+	return item, nil
+}
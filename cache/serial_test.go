@@ -0,0 +1,214 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：serial_test.go
+ * 内容摘要：WithSerializedValues 测试。
+ * 其他说明：启用后缓存中保存的是编码后的 []byte，返回值、Increment、索引等都应看到原始的值。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+type serialUser struct { // 测试用的值类型
+	Name string
+	Team string
+}
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：创建启用 WithSerializedValues(GobCodec) 的缓存
+ * 输入参数：t *testing.T, 其它配置项：opts ...Option
+ * 输出参数：无
+ * 返 回 值：缓存
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func newSerialCache(t *testing.T, opts ...Option) *Cache {
+	cacher, err := NewCache(0, 0, append([]Option{WithSerializedValues(GobCodec{})}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cacher
+}
+
+/***************************************************************************************
+ * 功能描述：测试启用后值与调用者隔离，且保存的是 []byte
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestSerializedGetSet(t *testing.T) {
+	cacher := newSerialCache(t)
+	value := []int{1, 2, 3}
+	cacher.Set("k", value, 0)
+	value[0] = 100
+	if _, ok := cacher.items["k"].Object.([]byte); !ok {
+		t.Fatalf("stored %T, want []byte", cacher.items["k"].Object)
+	}
+	got, found, err := cacher.Get("k")
+	if err != nil || !found || !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Fatalf("Get = %v, %v, %v", got, found, err)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试启用后 Increment 与 IncrementWithExpiration 可多次自增
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestSerializedIncrement(t *testing.T) {
+	cacher := newSerialCache(t)
+	cacher.Set("n", 1, 0)
+	for want := int64(2); want <= 4; want++ {
+		got, err := cacher.Increment("n", 1)
+		if err != nil || got != want {
+			t.Fatalf("Increment = %d, %v, want %d", got, err, want)
+		}
+	}
+	if value, _, _ := cacher.Get("n"); value != 4 {
+		t.Fatalf("Get = %v (%T), want int 4", value, value)
+	}
+	for want := int64(1); want <= 3; want++ {
+		got, err := cacher.IncrementWithExpiration("w", 1, NoExpiration)
+		if err != nil || got != want {
+			t.Fatalf("IncrementWithExpiration = %d, %v, want %d", got, err, want)
+		}
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试启用后索引的提取函数收到解码后的值
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：分别测试 AddIndex 之前与之后写入的数据项
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestSerializedIndex(t *testing.T) {
+	cacher := newSerialCache(t)
+	cacher.Set("u1", serialUser{"a", "red"}, 0)
+	cacher.AddIndex("team", func(value interface{}) (string, bool) {
+		user, ok := value.(serialUser)
+		return user.Team, ok
+	})
+	cacher.Set("u2", serialUser{"b", "red"}, 0)
+	cacher.Set("u3", serialUser{"c", "blue"}, 0)
+	if got := cacher.GetByIndex("team", "red"); len(got) != 2 {
+		t.Fatalf("GetByIndex(red) = %v, want 2 users", got)
+	}
+	cacher.Set("u2", serialUser{"b", "blue"}, 0)
+	if got := cacher.GetByIndex("team", "blue"); len(got) != 2 {
+		t.Fatalf("GetByIndex(blue) = %v, want 2 users", got)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试启用后 Items、SortedItems、Snapshot、Drain 返回解码后的值，Restore 重新编码
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestSerializedItems(t *testing.T) {
+	cacher := newSerialCache(t)
+	cacher.Set("a", "x", 0)
+	cacher.Set("b", 2, 0)
+	want := map[string]interface{}{"a": "x", "b": 2}
+	check := func(name string, items map[string]Item) {
+		t.Helper()
+		if len(items) != len(want) {
+			t.Fatalf("%s: %d items, want %d", name, len(items), len(want))
+		}
+		for key, item := range items {
+			if item.Object != want[key] {
+				t.Fatalf("%s: %s = %v (%T), want %v", name, key, item.Object, item.Object, want[key])
+			}
+		}
+	}
+	check("Items", cacher.Items())
+	sorted := map[string]Item{}
+	for _, item := range cacher.SortedItems() {
+		sorted[item.Key] = item.Item
+	}
+	check("SortedItems", sorted)
+	snap := cacher.Snapshot()
+	check("Snapshot", snap)
+
+	cacher.Flush()
+	cacher.Restore(snap, false)
+	if _, ok := cacher.items["a"].Object.([]byte); !ok {
+		t.Fatalf("Restore stored %T, want []byte", cacher.items["a"].Object)
+	}
+	if value, _, _ := cacher.Get("a"); value != "x" {
+		t.Fatalf("Get after Restore = %v", value)
+	}
+	check("Drain", cacher.Drain())
+}
+
+/***************************************************************************************
+ * 功能描述：测试启用后 LoadMerge 的 resolve 收到解码后的值，返回值重新编码后存入
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestSerializedLoadMerge(t *testing.T) {
+	source := newSerialCache(t)
+	source.Set("k", 10, 0)
+	var buf bytes.Buffer
+	if err := source.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	cacher := newSerialCache(t)
+	cacher.Set("k", 5, 0)
+	err := cacher.LoadMerge(&buf, func(key string, existing, incoming Item) Item {
+		incoming.Object = existing.Object.(int) + incoming.Object.(int)
+		return incoming
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, _, _ := cacher.Get("k"); value != 15 {
+		t.Fatalf("Get after LoadMerge = %v, want 15", value)
+	}
+}
@@ -0,0 +1,173 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：size.go
+ * 内容摘要：估算缓存数据项占用的内存。
+ * 其他说明：估算值只是近似值：按 reflect 遍历值的结构，累加各部分的大小，不计算 map 的 bucket、
+ *           内存对齐与分配器的额外开销，共享的底层数组会被重复计算，超过 sizeMaxDepth 层的嵌套
+ *           只计算指针本身。需要精确大小的类型可以实现 Sizer，或通过 WithSizer 提供估算函数。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"reflect"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+type Sizer interface { // 能够报告自身大小的值
+	Size() int64 // 值占用的字节数
+}
+
+type SizeFunc func(value interface{}) (int64, bool) // 估算值的字节数，false 表示不处理该值
+
+const sizeMaxDepth = 8 // reflect 估算的最大嵌套层数，同时避免循环引用
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：估算缓存中未过期数据项占用的内存
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：int64 估算的字节数，为每个数据项键名长度与值的估算大小之和
+ * 其他说明：该函数为 Cache 类方法，在读锁内遍历全部数据项，数据项较多时开销较大；
+ *           结果是近似值，可用于决定是否调用 TrimToBytes
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) EstimatedBytes() int64 {
+	now := nanotime()
+	thisCache.mux.RLock()
+	defer thisCache.mux.RUnlock()
+
+	var total int64
+	for key, val := range thisCache.items {
+		if !thisCache.expired(val, now) && !val.negative() {
+			total += int64(len(key)) + thisCache.estimateSize(val.Object)
+		}
+	}
+	return total
+}
+
+/***************************************************************************************
+ * 功能描述：估算一个值的字节数
+ * 输入参数：值：value interface{}
+ * 输出参数：无
+ * 返 回 值：int64 估算的字节数
+ * 其他说明：该函数为 Cache 类方法，依次使用 WithSizer 设置的函数、Sizer 接口与 reflect 估算；
+ *           WithSizer 设置的函数在恢复边界内调用，panic 时视为返回 false
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) estimateSize(value interface{}) int64 {
+	if thisCache.sizer != nil {
+		var size int64
+		var ok bool
+		thisCache.protect("sizer", "", func() error {
+			size, ok = thisCache.sizer(value)
+			return nil
+		})
+		if ok {
+			return size
+		}
+	}
+	if sizer, ok := value.(Sizer); ok {
+		return sizer.Size()
+	}
+	switch v := value.(type) {
+	case []byte:
+		return int64(len(v))
+	case string:
+		return int64(len(v))
+	}
+	return reflectSize(reflect.ValueOf(value), 0)
+}
+
+/***************************************************************************************
+ * 功能描述：通过 reflect 估算值的字节数
+ * 输入参数：值：v reflect.Value, 当前嵌套层数：depth int
+ * 输出参数：无
+ * 返 回 值：int64 估算的字节数
+ * 其他说明：slice、string 计算头部与底层数组，map 计算每个键与值，指针与 interface 计算指向的值
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func reflectSize(v reflect.Value, depth int) int64 {
+	if !v.IsValid() {
+		return 0
+	}
+	size := int64(v.Type().Size())
+	if depth >= sizeMaxDepth {
+		return size
+	}
+	switch v.Kind() {
+	case reflect.String:
+		size += int64(v.Len())
+	case reflect.Slice:
+		elem := v.Type().Elem()
+		if fixedSize(elem.Kind()) {
+			size += int64(v.Cap()) * int64(elem.Size())
+			break
+		}
+		for i := 0; i < v.Len(); i++ {
+			size += reflectSize(v.Index(i), depth+1)
+		}
+	case reflect.Array:
+		if fixedSize(v.Type().Elem().Kind()) {
+			break
+		}
+		size = 0
+		for i := 0; i < v.Len(); i++ {
+			size += reflectSize(v.Index(i), depth+1)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			size += reflectSize(iter.Key(), depth+1) + reflectSize(iter.Value(), depth+1)
+		}
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			size += reflectSize(v.Elem(), depth+1)
+		}
+	case reflect.Struct:
+		size = 0
+		for i := 0; i < v.NumField(); i++ {
+			size += reflectSize(v.Field(i), depth+1)
+		}
+	}
+	return size
+}
+
+/***************************************************************************************
+ * 功能描述：判断该类型的值大小是否固定，不引用其它内存
+ * 输入参数：类型：kind reflect.Kind
+ * 输出参数：无
+ * 返 回 值：大小固定为true
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func fixedSize(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：size_test.go
+ * 内容摘要：内存估算测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：测试单个值的大小估算
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：依次使用 WithSizer、Sizer 接口与 reflect；循环引用在 sizeMaxDepth 层后停止
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestEstimateSize(t *testing.T) {
+	type record struct {
+		ID   int64
+		Name string
+	}
+	type node struct {
+		Next *node
+	}
+	loop := &node{}
+	loop.Next = loop
+	tests := []struct {
+		name  string
+		value interface{}
+		want  int64
+	}{
+		{"bytes", make([]byte, 10), 10},
+		{"string", "abc", 3},
+		{"int64", int64(1), 8},
+		{"slice", make([]int64, 2, 4), 24 + 32},
+		{"struct", record{1, "xy"}, 8 + 16 + 2},
+		{"map", map[string]int64{"ab": 1}, 8 + 16 + 2 + 8},
+		{"sizer", sizedValue(1000), 1000},
+		{"cycle", loop, 8 * (sizeMaxDepth/2 + 1)}, // 指针与结构体各占一层
+	}
+	cacher, _ := NewCache(0, 0)
+	for _, test := range tests {
+		if got := cacher.estimateSize(test.value); got != test.want {
+			t.Errorf("%s: estimateSize = %d, want %d", test.name, got, test.want)
+		}
+	}
+
+	custom, _ := NewCache(0, 0, WithSizer(func(value interface{}) (int64, bool) {
+		switch value.(type) {
+		case string:
+			return 1, true
+		case int:
+			panic("sizer failed")
+		}
+		return 0, false
+	}))
+	if got := custom.estimateSize("abc"); got != 1 {
+		t.Errorf("WithSizer string = %d, want 1", got)
+	}
+	if got := custom.estimateSize(sizedValue(1000)); got != 1000 {
+		t.Errorf("WithSizer declined: %d, want the Sizer's 1000", got)
+	}
+	if got := custom.estimateSize(7); got != 8 {
+		t.Errorf("WithSizer panicked: %d, want the reflect estimate 8", got)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 EstimatedBytes 为未过期数据项键名长度与值大小之和
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：已过期的数据项不计入
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestEstimatedBytes(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	cacher.Set("ab", make([]byte, 100), 0)
+	cacher.Set("cd", "xyz", 0)
+	cacher.Set("gone", make([]byte, 1000), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if got := cacher.EstimatedBytes(); got != 2+100+2+3 {
+		t.Errorf("EstimatedBytes = %d, want 107", got)
+	}
+}
+
+type sizedValue int64 // 通过 Sizer 报告自身大小的值
+
+func (thisValue sizedValue) Size() int64 {
+	return int64(thisValue)
+}
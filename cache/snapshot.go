@@ -0,0 +1,195 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：snapshot.go
+ * 内容摘要：Save/Load 使用的快照格式。
+ * 其他说明：快照以 snapshotMagic 开头，之后是与 SaveStream 相同的记录：
+ *           uvarint(键名长度) 键名 uvarint(数据项长度) 数据项，数据项使用 WithCodec 设置的编解码器单独编码。
+ *           逐项编码使 Load 可以对单个解码失败的数据项调用 WithItemMigrator 设置的迁移函数，
+ *           也使 Save 可以跳过无法编码的数据项(如包含 chan、func 的类型)，不因单个数据项丢失整个快照。
+ *           不以 snapshotMagic 开头的输入按旧格式(整个 map[string]Item 一次编码)读取，
+ *           旧格式无法定位到单个数据项，不调用迁移函数。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+// 快照格式的标识，编解码器的输出不会以 0 字节开头
+var snapshotMagic = []byte("\x00libcache\x01")
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：将数据项逐个编码写入到io.Writer中
+ * 输入参数：wrt io.Writer, 过滤条件：pred func(key string, item Item) bool，为 nil 时保存全部
+ * 输出参数：无
+ * 返 回 值：failed 无法编码而未写出的键名；无 error 则为 nil，写入失败时返回该错误
+ * 其他说明：该函数为 Cache 类方法，在读锁内复制数据项后释放锁再编码，结果是某一时刻的一致快照；
+ *           墓碑数据项不保存；编码失败或 panic 的数据项被跳过并记录日志，其余数据项照常写出
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) saveItems(wrt io.Writer, pred func(key string, item Item) bool) (failed []string, err error) {
+	defer func() {
+		if err != nil {
+			thisCache.logger.Errorf("cache save: %v", err)
+		}
+	}()
+
+	items := thisCache.selectItems(pred)
+
+	bufWrt := bufio.NewWriter(wrt)
+	if _, err = bufWrt.Write(snapshotMagic); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	for key, val := range items {
+		buf.Reset()
+		if err := thisCache.encodeItem(&buf, val); err != nil {
+			thisCache.logger.Errorf("cache save %s: %v", key, err)
+			failed = append(failed, key)
+			continue
+		}
+		if err = writeRecord(bufWrt, []byte(key)); err != nil {
+			return failed, err
+		}
+		if err = writeRecord(bufWrt, buf.Bytes()); err != nil {
+			return failed, err
+		}
+	}
+	return failed, bufWrt.Flush()
+}
+
+/***************************************************************************************
+ * 功能描述：在读锁内复制满足条件的数据项
+ * 输入参数：过滤条件：pred func(key string, item Item) bool，为 nil 时复制全部
+ * 输出参数：无
+ * 返 回 值：数据项的副本
+ * 其他说明：该函数为 Cache 类方法，墓碑数据项不复制；pred 在恢复边界内调用，panic 时视为返回 false，
+ *           读锁通过 defer 释放
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) selectItems(pred func(key string, item Item) bool) map[string]Item {
+	thisCache.mux.RLock()
+	defer thisCache.mux.RUnlock()
+
+	items := make(map[string]Item, len(thisCache.items))
+	for key, val := range thisCache.items {
+		if val.negative() { // 墓碑数据项不持久化
+			continue
+		}
+		if pred != nil {
+			var keep bool
+			thisCache.protect("predicate", key, func() error {
+				keep = pred(key, val)
+				return nil
+			})
+			if !keep {
+				continue
+			}
+		}
+		items[key] = val
+	}
+	return items
+}
+
+/***************************************************************************************
+ * 功能描述：读取 Save 写出的快照
+ * 输入参数：rd io.Reader
+ * 输出参数：无
+ * 返 回 值：读取的数据项，无 error 则为 nil
+ * 其他说明：该函数为 Cache 类方法，不持有锁时调用；同时支持旧格式的快照；
+ *           解码失败的数据项交给迁移函数，迁移函数返回 false 时跳过该数据项，
+ *           未设置迁移函数时返回解码错误
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) readSnapshot(rd io.Reader) (map[string]Item, error) {
+	bufRd := bufio.NewReader(rd)
+	items := map[string]Item{}
+	if head, err := bufRd.Peek(len(snapshotMagic)); err != nil || !bytes.Equal(head, snapshotMagic) {
+		if err := thisCache.codec.NewDecoder(bufRd).Decode(&items); err != nil { // 旧格式
+			return nil, err
+		}
+		return items, nil
+	}
+	bufRd.Discard(len(snapshotMagic))
+	for {
+		key, data, err := readItemRecord(bufRd)
+		if err == io.EOF {
+			return items, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		item, ok, err := thisCache.decodeItem(key, data)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			items[key] = item
+		}
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：读取一对键名与数据项记录
+ * 输入参数：rd *bufio.Reader
+ * 输出参数：无
+ * 返 回 值：键名，编码后的数据项；流在记录开始处结束时返回 io.EOF
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func readItemRecord(rd *bufio.Reader) (string, []byte, error) {
+	key, err := readRecord(rd)
+	if err != nil {
+		return "", nil, err
+	}
+	data, err := readRecord(rd)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return string(key), data, err
+}
+
+/***************************************************************************************
+ * 功能描述：解码一个数据项记录，失败时交给迁移函数
+ * 输入参数：数据项键名：key string, 编码后的数据项：data []byte
+ * 输出参数：无
+ * 返 回 值：数据项，是否保留(bool)，无 error 则为 nil
+ * 其他说明：该函数为 Cache 类方法，迁移函数返回 false 时不保留，未设置迁移函数时返回解码错误
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) decodeItem(key string, data []byte) (Item, bool, error) {
+	var item Item
+	if err := thisCache.codec.NewDecoder(bytes.NewReader(data)).Decode(&item); err != nil {
+		return thisCache.migrateItem(key, data, err)
+	}
+	return item, true, nil
+}
@@ -0,0 +1,134 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：snapshot_test.go
+ * 内容摘要：快照保存测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：测试无法编码的数据项被跳过并报告，其余数据项照常保存
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：chan 与 func 类型无法用 gob 编码
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestSavePartial(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	cacher.Set("a", 1, 0)
+	cacher.Set("b", "two", 0)
+	cacher.Set("ch", make(chan int), 0)
+	cacher.Set("fn", func() {}, 0)
+
+	var buf bytes.Buffer
+	failed, err := cacher.SavePartial(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bad := map[string]bool{}
+	for _, key := range failed {
+		bad[key] = true
+	}
+	if len(failed) != 2 || !bad["ch"] || !bad["fn"] {
+		t.Errorf("failed = %v, want [ch fn]", failed)
+	}
+	loaded, _ := NewCache(0, 0)
+	if err = loaded.Load(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.Count(); got != 2 {
+		t.Errorf("Count = %d, want 2", got)
+	}
+	if value, found, _ := loaded.Get("b"); !found || value != "two" {
+		t.Errorf("b = %v, %v, want two", value, found)
+	}
+
+	buf.Reset()
+	if err = cacher.Save(&buf); err != nil {
+		t.Errorf("Save: %v, want nil", err)
+	}
+	file := filepath.Join(t.TempDir(), "cache.dat")
+	if err = cacher.SaveMemToFile(file); err != nil {
+		t.Fatal(err)
+	}
+	loaded, _ = NewCache(0, 0)
+	if err = loaded.LoadFileToMem(file); err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.Count(); got != 2 {
+		t.Errorf("file Count = %d, want 2", got)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试按前缀保存部分数据项并加载到新的缓存
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：已过期的数据项不保存；Load 覆盖同名的未过期数据项并跳过已过期的数据项
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestSaveFuncPrefix(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	cacher.Set("user:1", "ann", 0)
+	cacher.Set("user:2", "bob", 0)
+	cacher.Set("user:old", "gone", time.Nanosecond)
+	cacher.Set("team:1", "core", 0)
+	time.Sleep(time.Millisecond)
+
+	var buf bytes.Buffer
+	err := cacher.SaveFunc(&buf, func(key string, item Item) bool {
+		return strings.HasPrefix(key, "user:")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, _ := NewCache(0, 0)
+	loaded.Set("user:1", "stale", 0)
+	if err = loaded.Load(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.Count(); got != 2 {
+		t.Fatalf("Count = %d, want 2", got)
+	}
+	for key, want := range map[string]string{"user:1": "ann", "user:2": "bob"} {
+		if value, found, _ := loaded.Get(key); !found || value != want {
+			t.Errorf("%s = %v, %v, want %s", key, value, found, want)
+		}
+	}
+	if loaded.Has("team:1") || loaded.Has("user:old") {
+		t.Error("loaded an item outside the prefix or an expired item")
+	}
+
+	buf.Reset()
+	cacher.Save(&buf) // Save 保留已过期的数据项，由 Load 跳过
+	loaded, _ = NewCache(0, 0)
+	if err = loaded.Load(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.Count(); got != 3 {
+		t.Fatalf("Count after Load of a full snapshot = %d, want 3", got)
+	}
+}
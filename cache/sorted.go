@@ -0,0 +1,94 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：sorted.go
+ * 内容摘要：按键名字典序列出数据项。
+ * 其他说明：map 的遍历顺序不确定，调试输出与测试比对需要稳定的顺序；
+ *           排序的复杂度为 O(n log n)，排序在释放读锁之后进行。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"sort"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+type KeyedItem struct { // 带键名的数据项
+	Key  string // 数据项键名
+	Item Item   // 数据项
+}
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：按字典序列出未过期的数据项键名
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：[]string 排序后的键名
+ * 其他说明：该函数为 Cache 类方法，在读锁内复制键名，不包含已过期与墓碑数据项
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) SortedKeys() []string {
+	now := nanotime()
+	thisCache.mux.RLock()
+	keys := make([]string, 0, len(thisCache.items))
+	for key, val := range thisCache.items {
+		if !thisCache.expired(val, now) && !val.negative() {
+			keys = append(keys, key)
+		}
+	}
+	thisCache.mux.RUnlock()
+
+	sort.Strings(keys)
+	return keys
+}
+
+/***************************************************************************************
+ * 功能描述：按键名字典序列出未过期的数据项
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：[]KeyedItem 排序后的数据项
+ * 其他说明：该函数为 Cache 类方法，在读锁内复制数据项，不包含已过期与墓碑数据项；
+ *           数据项的值与缓存共享，不受 WithCopyOnGet 影响；启用 WithSerializedValues 时在释放锁后解码，
+ *           解码失败的数据项被跳过
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) SortedItems() []KeyedItem {
+	now := nanotime()
+	thisCache.mux.RLock()
+	items := make([]KeyedItem, 0, len(thisCache.items))
+	for key, val := range thisCache.items {
+		if !thisCache.expired(val, now) && !val.negative() {
+			items = append(items, KeyedItem{Key: key, Item: val})
+		}
+	}
+	thisCache.mux.RUnlock()
+
+	if thisCache.valueCodec != nil {
+		decoded := items[:0]
+		for _, item := range items {
+			if val, err := thisCache.plainItem(item.Item); err == nil {
+				decoded = append(decoded, KeyedItem{Key: item.Key, Item: val})
+			}
+		}
+		items = decoded
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Key < items[j].Key
+	})
+	return items
+}
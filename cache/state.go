@@ -0,0 +1,61 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：state.go
+ * 内容摘要：获取数据项时区分命中、已过期与不存在。
+ * 其他说明：已过期的数据项在被 DeleteExpired(gcLoop) 清理之前仍保留在缓存中，
+ *           GetState 可以返回这段时间内的旧值，供调用者在后端不可用时降级使用；
+ *           清理之后只能得到 StateMissing。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+
+/***************************************************************************************/
+// 数据结构与常量
+
+type State int // 数据项的状态
+
+const (
+	StateMissing State = iota // 不存在，或已被清理
+	StateHit                  // 命中未过期的数据项
+	StateExpired              // 已过期但尚未被清理，返回旧值(Expired 已用于 Reason)
+)
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：获取数据项及其状态
+ * 输入参数：数据项键名：key string
+ * 输出参数：无
+ * 返 回 值：具体数据项的值，以及状态 StateHit、StateExpired 或 StateMissing
+ * 其他说明：该函数为 Cache 类方法，不调用 loader；StateExpired 时返回的是过期前的旧值，
+ *           只在 gcLoop 清理之前可用，启用 WithDeleteOnExpiredRead 时读取后即被删除；墓碑数据项视为 StateMissing；
+ *           启用 WithCopyOnGet 时返回值的副本，复制失败时返回 StateMissing
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) GetState(key string) (value interface{}, state State) {
+	key, err := thisCache.checkKey(key)
+	if err != nil {
+		return nil, StateMissing
+	}
+	item, state := thisCache.accessState(key) // 只读取一次，命中与过期的判断来自同一个数据项
+	if state == StateMissing {
+		return nil, StateMissing
+	}
+	if item.negative() {
+		return nil, StateMissing
+	}
+	value, err = thisCache.readValue(item.Object)
+	if err != nil {
+		return nil, StateMissing
+	}
+	return value, state
+}
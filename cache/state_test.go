@@ -0,0 +1,58 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：state_test.go
+ * 内容摘要：GetState 测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：测试命中、已过期与不存在三种状态
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：已过期的数据项在清理之前返回旧值，启用 WithDeleteOnExpiredRead 时读取后删除
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestGetState(t *testing.T) {
+	for _, deleteOnRead := range []bool{false, true} {
+		cacher, _ := NewCache(0, 0, WithAccessTracking(), WithDeleteOnExpiredRead(deleteOnRead))
+		cacher.Set("live", 1, 0)
+		cacher.Set("old", 2, time.Nanosecond)
+		time.Sleep(time.Millisecond)
+		if value, state := cacher.GetState("live"); state != StateHit || value != 1 {
+			t.Errorf("live = %v, %v, want 1, StateHit", value, state)
+		}
+		if hits := cacher.Snapshot()["live"].Hits; hits != 1 {
+			t.Errorf("live Hits = %d, want 1", hits)
+		}
+		if value, state := cacher.GetState("old"); state != StateExpired || value != 2 {
+			t.Errorf("old = %v, %v, want 2, StateExpired", value, state)
+		}
+		if _, state := cacher.GetState("none"); state != StateMissing {
+			t.Errorf("none = %v, want StateMissing", state)
+		}
+		want := StateExpired
+		if deleteOnRead {
+			want = StateMissing
+		}
+		if _, state := cacher.GetState("old"); state != want {
+			t.Errorf("deleteOnRead=%v: second read of old = %v, want %v", deleteOnRead, state, want)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：store.go
+ * 内容摘要：缓存核心操作的接口。
+ * 其他说明：调用者可以面向 Store 编程，在 *Cache 与其它实现(如 Redis、测试用的 map)之间替换。
+ *           接口只包含核心的读写操作，Save/Load、Stats 等 *Cache 特有的方法不在接口中，
+ *           需要时对接口值做类型断言。各方法的语义见 *Cache 的同名方法。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"time"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+type Store interface { // 缓存核心操作
+	Get(key string) (interface{}, bool, error)                      // 获取数据项
+	Set(key string, value interface{}, dur time.Duration) error     // 存入数据项，存在时覆盖
+	Delete(key string) error                                        // 删除数据项
+	Add(key string, value interface{}, dur time.Duration) error     // 数据项不存在时存入
+	Replace(key string, value interface{}, dur time.Duration) error // 数据项存在时覆盖
+	Flush() int                                                     // 删除全部数据项，返回删除的数量
+	Count() int                                                     // 数据项数量
+}
+
+var _ Store = (*Cache)(nil) // 编译时检查 *Cache 实现了 Store
+
+/***************************************************************************************/
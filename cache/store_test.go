@@ -0,0 +1,57 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：store_test.go
+ * 内容摘要：Store 接口测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：测试通过 Store 接口使用 *Cache
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestStore(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	var store Store = cacher
+	if err := store.Set("a", 1, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Add("a", 2, 0); err == nil {
+		t.Error("Add of an existing key succeeded")
+	}
+	if err := store.Replace("a", 3, 0); err != nil {
+		t.Errorf("Replace: %v", err)
+	}
+	if value, found, err := store.Get("a"); !found || err != nil || value != 3 {
+		t.Errorf("Get = %v, %v, %v, want 3", value, found, err)
+	}
+	if err := store.Delete("a"); err != nil {
+		t.Errorf("Delete: %v", err)
+	}
+	store.Set("b", 1, 0)
+	if n := store.Count(); n != 1 {
+		t.Errorf("Count = %d, want 1", n)
+	}
+	if n := store.Flush(); n != 1 || store.Count() != 0 {
+		t.Errorf("Flush = %d, Count %d, want 1 and 0", n, store.Count())
+	}
+}
@@ -0,0 +1,250 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：stream.go
+ * 内容摘要：逐个数据项流式保存与加载缓存。
+ * 其他说明：Save 在读锁内复制全部数据项得到一致的快照，需要与数据项数量相当的额外内存；
+ *           SaveStream 只在复制键名时短暂持有读锁，之后每个数据项单独加锁
+ *           读取、在锁外编码并写出，锁持有时间与额外内存都与单个数据项相当。
+ *           流格式为连续的记录：uvarint(键名长度) 键名 uvarint(数据项长度) 数据项，
+ *           数据项使用 WithCodec 设置的编解码器单独编码。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+const maxStreamRecord = 1 << 30 // 单个记录长度的上限，防止损坏的流导致分配过大的内存
+
+var errStreamRecord = errors.New("stream record invalid.") // 记录长度超过上限
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：将缓存数据项逐个写入 io.Writer
+ * 输入参数：wrt io.Writer
+ * 输出参数：无
+ * 返 回 值：无 error， 则为 nil
+ * 其他说明：该函数为 Cache 类方法，先在读锁内复制键名，再逐个读取数据项，每次只短暂持有读锁；
+ *           结果不是某一时刻的一致快照，保存期间被删除的数据项不写出；
+ *           不写出已过期与墓碑数据项；写出的数据由 LoadStream 读取
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) SaveStream(wrt io.Writer) error {
+	return thisCache.SaveContext(context.Background(), wrt)
+}
+
+/***************************************************************************************
+ * 功能描述：将缓存数据项逐个写入 io.Writer，可通过 ctx 取消
+ * 输入参数：上下文：ctx context.Context, wrt io.Writer
+ * 输出参数：无
+ * 返 回 值：无 error， 则为 nil；被取消时返回 ctx.Err()
+ * 其他说明：该函数为 Cache 类方法，格式与 SaveStream 相同，每写出一个数据项检查一次 ctx；
+ *           被取消时已写出的记录都是完整的，LoadStream 可以读取这部分数据项
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) SaveContext(ctx context.Context, wrt io.Writer) error {
+	return thisCache.saveStream(ctx, wrt)
+}
+
+/***************************************************************************************
+ * 功能描述：将缓存数据项逐个写入 io.Writer
+ * 输入参数：上下文：ctx context.Context, wrt io.Writer
+ * 输出参数：无
+ * 返 回 值：无 error， 则为 nil
+ * 其他说明：该函数为 Cache 类方法，遇到无法编码的数据项返回错误
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) saveStream(ctx context.Context, wrt io.Writer) (err error) {
+	defer func() {
+		if err != nil {
+			thisCache.logger.Errorf("cache save stream: %v", err)
+		}
+	}()
+
+	thisCache.mux.RLock()
+	keys := make([]string, 0, len(thisCache.items))
+	for key := range thisCache.items {
+		keys = append(keys, key)
+	}
+	thisCache.mux.RUnlock()
+
+	bufWrt := bufio.NewWriter(wrt)
+	var buf bytes.Buffer
+	for _, key := range keys {
+		select {
+		case <-ctx.Done():
+			if err = bufWrt.Flush(); err != nil {
+				return err
+			}
+			return ctx.Err()
+		default:
+		}
+		now := nanotime()
+		thisCache.mux.RLock()
+		item, found := thisCache.items[key]
+		thisCache.mux.RUnlock()
+		if !found || thisCache.expired(item, now) || item.negative() {
+			continue
+		}
+
+		buf.Reset()
+		if err = thisCache.encodeItem(&buf, item); err != nil {
+			return err
+		}
+		if err = writeRecord(bufWrt, []byte(key)); err != nil {
+			return err
+		}
+		if err = writeRecord(bufWrt, buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return bufWrt.Flush()
+}
+
+/***************************************************************************************
+ * 功能描述：编码一个数据项
+ * 输入参数：wrt io.Writer, 数据项：item Item
+ * 输出参数：无
+ * 返 回 值：无 error， 则为 nil；编码器 panic(如 gob 注册类型失败)时返回错误
+ * 其他说明：该函数为 Cache 类方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) encodeItem(wrt io.Writer, item Item) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("Error registring item type with Gob lib.")
+		}
+	}()
+	return thisCache.codec.NewEncoder(wrt).Encode(&item)
+}
+
+/***************************************************************************************
+ * 功能描述：从 io.Reader 中逐个读取 SaveStream 写出的数据项
+ * 输入参数：rd io.Reader
+ * 输出参数：无
+ * 返 回 值：无 error， 则为 nil
+ * 其他说明：该函数为 Cache 类方法，每个数据项单独加写锁存入；与 Load 相同，
+ *           只加载缓存中不存在或已过期的数据项，已过期的记录被跳过；
+ *           出错时已读取的数据项保留在缓存中；解码失败的数据项交给 WithItemMigrator 设置的迁移函数，
+ *           迁移函数返回 false 时跳过该数据项继续读取；也可以读取 Save 写出的快照
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) LoadStream(rd io.Reader) (err error) {
+	defer func() {
+		if err != nil {
+			thisCache.logger.Errorf("cache load stream: %v", err)
+		}
+	}()
+
+	bufRd := bufio.NewReader(rd)
+	if head, err := bufRd.Peek(len(snapshotMagic)); err == nil && bytes.Equal(head, snapshotMagic) {
+		bufRd.Discard(len(snapshotMagic))
+	}
+	for {
+		key, data, err := readItemRecord(bufRd)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		item, ok, err := thisCache.decodeItem(key, data)
+		if err != nil {
+			return err
+		}
+		now := nanotime()
+		if !ok || thisCache.expired(item, now) {
+			continue
+		}
+		if err = thisCache.lockWithTimeout(); err != nil {
+			return err
+		}
+		if existing, found := thisCache.items[key]; !found || thisCache.expired(existing, now) {
+			item.Version = thisCache.nextVersion()
+			thisCache.putItem(key, item)
+		}
+		thisCache.unlock()
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：写出一个长度前缀的记录
+ * 输入参数：wrt io.Writer, 记录内容：data []byte
+ * 输出参数：无
+ * 返 回 值：无 error， 则为 nil
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func writeRecord(wrt io.Writer, data []byte) error {
+	var head [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(head[:], uint64(len(data)))
+	if _, err := wrt.Write(head[:n]); err != nil {
+		return err
+	}
+	_, err := wrt.Write(data)
+	return err
+}
+
+/***************************************************************************************
+ * 功能描述：读取一个长度前缀的记录
+ * 输入参数：rd *bufio.Reader
+ * 输出参数：无
+ * 返 回 值：记录内容，流在记录开始处结束时返回 io.EOF
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func readRecord(rd *bufio.Reader) ([]byte, error) {
+	size, err := binary.ReadUvarint(rd)
+	if err != nil {
+		return nil, err
+	}
+	if size > maxStreamRecord {
+		return nil, errStreamRecord
+	}
+	data := make([]byte, size)
+	if _, err = io.ReadFull(rd, data); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return data, nil
+}
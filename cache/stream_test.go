@@ -0,0 +1,153 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：stream_test.go
+ * 内容摘要：流式保存与加载测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：测试 SaveStream/LoadStream 往返保留值与过期时间
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：已过期的数据项不写出
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestSaveLoadStream(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	cacher.Set("a", "one", time.Hour)
+	cacher.Set("b", 2, NoExpiration)
+	cacher.Set("gone", 3, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	var buf bytes.Buffer
+	if err := cacher.SaveStream(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, _ := NewCache(0, 0)
+	if err := loaded.LoadStream(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.Count(); got != 2 {
+		t.Errorf("Count = %d, want 2", got)
+	}
+	if value, _, _ := loaded.Get("a"); value != "one" {
+		t.Errorf("a = %v, want one", value)
+	}
+	ttls := loaded.Expirations()
+	if ttls["a"] <= 59*time.Minute || ttls["b"] != NoExpiration {
+		t.Errorf("ttls = %v, want a about 1h and b NoExpiration", ttls)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 LoadStream 读取截断的流时返回 io.ErrUnexpectedEOF，已读取的数据项保留
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：在每个字节处截断，截断在记录边界时正常结束
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestLoadStreamTruncated(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	cacher.Set("a", 1, 0)
+	cacher.Set("b", 2, 0)
+	var buf bytes.Buffer
+	if err := cacher.SaveStream(&buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	loaded, _ := NewCache(0, 0)
+	if err := loaded.LoadStream(bytes.NewReader(data[:len(data)-1])); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("LoadStream of a truncated stream = %v, want io.ErrUnexpectedEOF", err)
+	}
+	if got := loaded.Count(); got != 1 {
+		t.Errorf("Count after a truncated stream = %d, want 1", got)
+	}
+	for cut := 1; cut < len(data); cut++ {
+		loaded, _ = NewCache(0, 0)
+		err := loaded.LoadStream(bytes.NewReader(data[:cut]))
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("cut at %d: err = %v, want nil or io.ErrUnexpectedEOF", cut, err)
+		}
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 SaveContext 被取消时返回 ctx.Err()，已写出的记录完整，LoadStream 可以读取
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：值大于 bufio 缓冲区，每条记录直接写到 wrt，写出第 3 条记录时取消
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestSaveContextCancel(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	for i := 0; i < 10; i++ {
+		cacher.Set("k"+strconv.Itoa(i), bytes.Repeat([]byte{byte(i)}, 8192), 0)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var buf bytes.Buffer
+	if err := cacher.SaveContext(ctx, &buf); !errors.Is(err, context.Canceled) || buf.Len() != 0 {
+		t.Fatalf("SaveContext with a cancelled ctx = %v, wrote %d bytes", err, buf.Len())
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	wrt := &cancelWriter{cancel: cancel, after: 3}
+	if err := cacher.SaveContext(ctx, wrt); !errors.Is(err, context.Canceled) {
+		t.Fatalf("SaveContext = %v, want context.Canceled", err)
+	}
+	loaded, _ := NewCache(0, 0)
+	if err := loaded.LoadStream(&wrt.buf); err != nil {
+		t.Fatalf("LoadStream of a cancelled save: %v", err)
+	}
+	if got := loaded.Count(); got != 3 {
+		t.Errorf("loaded %d items, want the 3 written before cancel", got)
+	}
+}
+
+type cancelWriter struct { // 写出 after 个大于 4KB 的块后调用 cancel 的 Writer
+	buf    bytes.Buffer
+	cancel context.CancelFunc
+	after  int
+}
+
+func (thisWriter *cancelWriter) Write(data []byte) (int, error) {
+	if len(data) > 4096 {
+		if thisWriter.after--; thisWriter.after == 0 {
+			thisWriter.cancel()
+		}
+	}
+	return thisWriter.buf.Write(data)
+}
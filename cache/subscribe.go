@@ -0,0 +1,254 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：subscribe.go
+ * 内容摘要：数据项变更通知。
+ * 其他说明：写入与删除数据项时在写锁内记录事件，释放写锁之后(unlock)再发送给订阅者，
+ *           因此 Block 策略的订阅者缓冲区满时，写入者在不持有锁的情况下等待。
+ *           每个订阅者有独立的缓冲区与溢出策略，并统计丢弃的事件数量。
+ *           墓碑数据项与 Touch 刷新过期时间不产生事件，Increment 与 Counter 的自增产生 EventSet 事件；
+ *           Flush、Drain、Restore 产生一个 EventFlushed 事件，订阅者应据此重新同步。
+ *           多个写入者并发时，不同写入者的事件之间不保证顺序。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"sync"
+	"sync/atomic"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+type EventKind int // 变更事件的类型
+
+const (
+	EventSet     EventKind = iota // 写入数据项
+	EventDeleted                  // 删除数据项，包括过期清理与淘汰
+	EventFlushed                  // 清空缓存
+)
+
+type Event struct { // 数据项变更事件
+	Kind  EventKind   // 事件类型
+	Key   string      // 数据项键名，EventFlushed 时为空
+	Value interface{} // 写入的值或被删除的值，EventFlushed 时为 nil
+}
+
+type OverflowPolicy int // 订阅者缓冲区满时的处理策略
+
+const (
+	DropNewest OverflowPolicy = iota // 丢弃新事件
+	DropOldest                       // 丢弃缓冲区中最早的事件
+	Block                            // 等待订阅者读取，写入者被阻塞(不持有缓存的锁)
+)
+
+type Subscription struct { // 订阅者
+	cacher  *Cache         // 订阅的缓存
+	events  chan Event     // 事件缓冲区
+	policy  OverflowPolicy // 缓冲区满时的处理策略
+	dropped atomic.Int64   // 丢弃的事件数量
+	done    chan struct{}  // 取消订阅时关闭，唤醒阻塞的写入者
+	mux     sync.Mutex     // 保护 closed 与 events 的发送、关闭
+	closed  bool           // 是否已取消订阅
+	once    sync.Once      // 保证只取消订阅一次
+}
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：订阅数据项变更事件
+ * 输入参数：缓冲区大小：bufSize int, 缓冲区满时的处理策略：policy OverflowPolicy
+ * 输出参数：无
+ * 返 回 值：订阅者
+ * 其他说明：该函数为 Cache 类方法，bufSize 小于 1 时为 1；
+ *           Block 策略的订阅者读取事件的 goroutine 不得写入本缓存，否则缓冲区满时发生死锁
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) Subscribe(bufSize int, policy OverflowPolicy) *Subscription {
+	if bufSize < 1 {
+		bufSize = 1
+	}
+	sub := &Subscription{
+		cacher: thisCache,
+		events: make(chan Event, bufSize),
+		policy: policy,
+		done:   make(chan struct{}),
+	}
+	thisCache.lock()
+	defer thisCache.unlock()
+	subscribers := make([]*Subscription, 0, len(thisCache.subscribers)+1) // 复制后修改，unlock 可在锁外遍历旧的切片
+	subscribers = append(subscribers, thisCache.subscribers...)
+	thisCache.subscribers = append(subscribers, sub)
+	return sub
+}
+
+/***************************************************************************************
+ * 功能描述：记录一个变更事件，调用时须持有写锁
+ * 输入参数：事件类型：kind EventKind, 数据项键名：key string, 数据项的值：value interface{}
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，没有订阅者时不记录
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) notify(kind EventKind, key string, value interface{}) {
+	if len(thisCache.subscribers) == 0 {
+		return
+	}
+	thisCache.pending = append(thisCache.pending, Event{Kind: kind, Key: key, Value: value})
+}
+
+/***************************************************************************************
+ * 功能描述：释放写锁，并将写锁期间记录的事件发送给订阅者
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，所有获取 mux 写锁的方法都通过该函数释放写锁；
+ *           写锁期间因超过数量上限被移出的数据项也在释放锁后调用 onEvicted(Capacity)
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) unlock() {
+	thisCache.publishItems()
+	events := thisCache.pending
+	overflow := thisCache.overflow
+	if len(events) == 0 && len(overflow) == 0 {
+		thisCache.release()
+		return
+	}
+	subscribers := thisCache.subscribers
+	onEvicted := thisCache.onEvicted
+	thisCache.pending = nil
+	thisCache.overflow = nil
+	thisCache.release()
+
+	for _, event := range events {
+		if decoded, err := thisCache.decodeValue(event.Value); err == nil {
+			event.Value = decoded
+		}
+		for _, sub := range subscribers {
+			sub.publish(event)
+		}
+	}
+	fireEvicted(onEvicted, overflow, Capacity)
+}
+
+/***************************************************************************************
+ * 功能描述：按溢出策略向订阅者发送一个事件
+ * 输入参数：事件：event Event
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Subscription 类方法，已取消订阅时直接返回
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisSub *Subscription) publish(event Event) {
+	thisSub.mux.Lock()
+	defer thisSub.mux.Unlock()
+	if thisSub.closed {
+		return
+	}
+	switch thisSub.policy {
+	case Block:
+		select {
+		case thisSub.events <- event:
+		case <-thisSub.done:
+		}
+	case DropOldest:
+		for {
+			select {
+			case thisSub.events <- event:
+				return
+			default:
+			}
+			select {
+			case <-thisSub.events:
+				thisSub.dropped.Add(1)
+			default: // 订阅者刚刚读走了事件，重试发送
+			}
+		}
+	default:
+		select {
+		case thisSub.events <- event:
+		default:
+			thisSub.dropped.Add(1)
+		}
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：获取事件通道
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：只读的事件通道，取消订阅后被关闭
+ * 其他说明：该函数为 Subscription 类方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisSub *Subscription) Events() <-chan Event {
+	return thisSub.events
+}
+
+/***************************************************************************************
+ * 功能描述：统计因缓冲区满而丢弃的事件数量
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：int64 丢弃的事件数量，Block 策略总为 0
+ * 其他说明：该函数为 Subscription 类方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisSub *Subscription) Dropped() int64 {
+	return thisSub.dropped.Load()
+}
+
+/***************************************************************************************
+ * 功能描述：取消订阅
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Subscription 类方法，唤醒阻塞在该订阅者上的写入者并关闭事件通道；可重复调用
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisSub *Subscription) Unsubscribe() {
+	thisSub.once.Do(func() {
+		thisCache := thisSub.cacher
+		thisCache.lock()
+		subscribers := make([]*Subscription, 0, len(thisCache.subscribers))
+		for _, sub := range thisCache.subscribers {
+			if sub != thisSub {
+				subscribers = append(subscribers, sub)
+			}
+		}
+		thisCache.subscribers = subscribers
+		thisCache.unlock()
+
+		close(thisSub.done)
+		thisSub.mux.Lock()
+		thisSub.closed = true
+		close(thisSub.events)
+		thisSub.mux.Unlock()
+	})
+}
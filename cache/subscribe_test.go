@@ -0,0 +1,111 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：subscribe_test.go
+ * 内容摘要：变更订阅测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：测试各溢出策略在慢订阅者缓冲区满时保留与丢弃的事件
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：缓冲区为 2，订阅者在 5 次写入后才开始读取
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestSubscribeOverflow(t *testing.T) {
+	tests := []struct {
+		policy  OverflowPolicy
+		kept    string
+		dropped int64
+	}{
+		{DropNewest, "[a b]", 3},
+		{DropOldest, "[d e]", 3},
+	}
+	for _, test := range tests {
+		cacher, _ := NewCache(0, 0)
+		sub := cacher.Subscribe(2, test.policy)
+		for _, key := range []string{"a", "b", "c", "d", "e"} {
+			cacher.Set(key, key, 0)
+		}
+		sub.Unsubscribe()
+		var kept []string
+		for event := range sub.Events() {
+			kept = append(kept, event.Key)
+		}
+		if fmt.Sprint(kept) != test.kept || sub.Dropped() != test.dropped {
+			t.Errorf("policy %d: kept %v, dropped %d, want %s, %d", test.policy, kept, sub.Dropped(), test.kept, test.dropped)
+		}
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 Block 策略阻塞写入者直到订阅者读取，且阻塞期间不持有缓存的锁
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestSubscribeBlock(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	sub := cacher.Subscribe(1, Block)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, key := range []string{"a", "b", "c"} {
+			cacher.Set(key, key, 0)
+		}
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	select {
+	case <-done:
+		t.Fatal("writer did not block on a full subscriber")
+	default:
+	}
+	if _, found, _ := cacher.Get("b"); !found {
+		t.Error("Get(b) missed while the writer was blocked")
+	}
+	locked := make(chan struct{})
+	go func() {
+		cacher.lock()
+		cacher.release()
+		close(locked)
+	}()
+	select {
+	case <-locked:
+	case <-time.After(time.Second):
+		t.Fatal("write lock held while the writer was blocked")
+	}
+
+	var keys []string
+	for len(keys) < 3 {
+		keys = append(keys, (<-sub.Events()).Key)
+	}
+	<-done
+	if fmt.Sprint(keys) != "[a b c]" || sub.Dropped() != 0 {
+		t.Errorf("events %v, dropped %d, want [a b c], 0", keys, sub.Dropped())
+	}
+	sub.Unsubscribe()
+}
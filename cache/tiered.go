@@ -0,0 +1,211 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：tiered.go
+ * 内容摘要：两级(L1/L2)组合缓存，以及在多个缓存中按顺序查找(GetFirst)。
+ * 其他说明：L1 为容量小、速度快的进程内缓存，L2 为容量大、生命周期长的缓存。
+ *           过期时间处理：Set 按同一个 dur 写入两级，DefaultExpiration 分别使用各级自己的
+ *           默认过期时间；Get 在 L2 命中后以 L1 的默认过期时间提升到 L1，但不会超过该数据项
+ *           在 L2 中剩余的生命周期，避免 L1 返回 L2 已经过期的值。
+ *           L1 不调用其 loader，L2 未命中时会调用 L2 的 loader。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"time"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+type TieredCache struct { // 两级缓存结构
+	l1 *Cache // 一级缓存
+	l2 *Cache // 二级缓存
+}
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：创建一个两级缓存
+ * 输入参数：一级缓存：l1 *Cache, 二级缓存：l2 *Cache
+ * 输出参数：无
+ * 返 回 值：一个新的两级缓存
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func NewTieredCache(l1, l2 *Cache) *TieredCache {
+	return &TieredCache{l1: l1, l2: l2}
+}
+
+/***************************************************************************************
+ * 功能描述：获取数据项，先查 L1，未命中再查 L2，L2 命中时提升到 L1
+ * 输入参数：数据项键名：key string
+ * 输出参数：无
+ * 返 回 值：具体数据项的值，是否找到(bool)，以及 error
+ * 其他说明：该函数为 TieredCache 类方法，L1 命中时与 Cache.Get 一样记录访问、解码或复制返回值；
+ *           提升经由 L1 的 Set 写入，L1 已关闭时不提升，覆盖的旧值与容量淘汰照常调用 onEvicted
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisTiered *TieredCache) Get(key string) (interface{}, bool, error) {
+	l1, l2 := thisTiered.l1, thisTiered.l2
+	l1Key, err := l1.checkKey(key)
+	if err != nil {
+		return nil, false, err
+	}
+	l2Key, err := l2.checkKey(key)
+	if err != nil {
+		return nil, false, err
+	}
+	item, found := l1.access(l1Key)
+	if l1.observer != nil {
+		l1.observe(l1Key, found && !item.negative())
+	}
+	if found && !item.negative() {
+		if value, err := l1.readValue(item.Object); err == nil {
+			return value, true, nil
+		}
+	}
+
+	value, found, err := l2.Get(l2Key)
+	if err != nil || !found {
+		return nil, false, err
+	}
+	l2.mux.RLock()
+	item, found = l2.items[l2Key]
+	l2.mux.RUnlock()
+	if !found || l2.expired(item, nanotime()) { // 在 L2 中已被删除或刚好过期，不提升
+		return value, true, nil
+	}
+
+	dur := DefaultExpiration
+	if item.Expiration > 0 {
+		expir := l1.expiration(DefaultExpiration)
+		if expir == 0 || item.Expiration < expir { // 不超过 L2 中剩余的生命周期
+			dur = time.Duration(item.Expiration - nanotime())
+			if dur <= 0 {
+				return value, true, nil
+			}
+		}
+	}
+	l1.Set(key, value, dur) // L1 已关闭或拒绝写入时只返回 L2 的值
+	return value, true, nil
+}
+
+/***************************************************************************************
+ * 功能描述：设置数据项，同时写入 L1 与 L2
+ * 输入参数：数据项键名：key string, 数据项键值：value interface{}, 数据项生命周期：dur time.Duration
+ * 输出参数：无
+ * 返 回 值：无 error， 则为 nil
+ * 其他说明：该函数为 TieredCache 类方法，先写 L2 再写 L1
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisTiered *TieredCache) Set(key string, value interface{}, dur time.Duration) error {
+	if err := thisTiered.l2.Set(key, value, dur); err != nil {
+		return err
+	}
+	return thisTiered.l1.Set(key, value, dur)
+}
+
+/***************************************************************************************
+ * 功能描述：删除数据项，同时从 L1 与 L2 中删除
+ * 输入参数：数据项键名：key string
+ * 输出参数：无
+ * 返 回 值：无 error， 则为 nil
+ * 其他说明：该函数为 TieredCache 类方法，先删 L2 再删 L1，避免 L1 未命中时重新从 L2 提升；
+ *           L2 删除失败时不删除 L1
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisTiered *TieredCache) Delete(key string) error {
+	if err := thisTiered.l2.Delete(key); err != nil {
+		return err
+	}
+	return thisTiered.l1.Delete(key)
+}
+
+/***************************************************************************************
+ * 功能描述：按顺序在多个缓存中查找数据项，返回第一个命中的值
+ * 输入参数：数据项键名：key string, 缓存：caches ...*Cache
+ * 输出参数：无
+ * 返 回 值：具体数据项的值以及是否找到(bool)
+ * 其他说明：不调用各缓存的 loader，墓碑数据项视为未命中；适用于临时的多级查找，
+ *           需要固定的两级缓存时使用 TieredCache
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func GetFirst(key string, caches ...*Cache) (interface{}, bool) {
+	return getFirst(key, caches, false)
+}
+
+/***************************************************************************************
+ * 功能描述：按顺序在多个缓存中查找数据项，并将命中的值写入之前未命中的缓存
+ * 输入参数：数据项键名：key string, 缓存：caches ...*Cache
+ * 输出参数：无
+ * 返 回 值：具体数据项的值以及是否找到(bool)
+ * 其他说明：写入时使用该数据项在命中缓存中剩余的生命周期，永不过期的数据项写入后同样永不过期
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func GetFirstAndPromote(key string, caches ...*Cache) (interface{}, bool) {
+	return getFirst(key, caches, true)
+}
+
+/***************************************************************************************
+ * 功能描述：按顺序在多个缓存中查找数据项
+ * 输入参数：数据项键名：key string, 缓存：caches []*Cache, 是否提升：promote bool
+ * 输出参数：无
+ * 返 回 值：具体数据项的值以及是否找到(bool)
+ * 其他说明：命中缓存启用 WithCopyOnGet 时返回值的副本，提升写入的是原值
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func getFirst(key string, caches []*Cache, promote bool) (interface{}, bool) {
+	for i, cacher := range caches {
+		normalized, err := cacher.checkKey(key) // 各缓存可能使用不同的 WithKeyValidator
+		if err != nil {
+			continue
+		}
+		item, found := cacher.access(normalized)
+		if !found || item.negative() {
+			continue
+		}
+		if promote && i > 0 {
+			dur := NoExpiration
+			if item.Expiration > 0 {
+				dur = time.Duration(item.Expiration - nanotime())
+			}
+			original, err := cacher.decodeValue(item.Object) // 启用 WithSerializedValues 时提升解码后的值
+			if err == nil && (dur > 0 || dur == NoExpiration) {
+				for _, earlier := range caches[:i] {
+					earlier.Set(key, original, dur)
+				}
+			}
+		}
+		value, err := cacher.readValue(item.Object)
+		return value, err == nil
+	}
+	return nil, false
+}
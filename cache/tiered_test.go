@@ -0,0 +1,155 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：tiered_test.go
+ * 内容摘要：两级缓存测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：测试 L1 启用值序列化时命中返回解码后的值并记录访问
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：L1 命中与 Cache.Get 走同一条读取路径
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestTieredSerializedL1(t *testing.T) {
+	l1 := newSerialCache(t, WithAccessTracking())
+	l2, _ := NewCache(0, 0)
+	tiered := NewTieredCache(l1, l2)
+	user := serialUser{Name: "ann", Team: "core"}
+	if err := tiered.Set("user", user, 0); err != nil {
+		t.Fatal(err)
+	}
+	value, found, err := tiered.Get("user")
+	if err != nil || !found {
+		t.Fatalf("Get = %v, %v", found, err)
+	}
+	if got, ok := value.(serialUser); !ok || got != user {
+		t.Fatalf("Get = %#v, want %#v", value, user)
+	}
+	if hits := l1.Snapshot()["user"].Hits; hits != 1 {
+		t.Fatalf("L1 Hits = %d, want 1", hits)
+	}
+
+	l2.Set("remote", user, 0) // 只在 L2 中，提升后 L1 中保存的仍是编码后的值
+	if value, found, _ := tiered.Get("remote"); !found || value != user {
+		t.Fatalf("Get remote = %#v, %v", value, found)
+	}
+	if value, found, _ := l1.Get("remote"); !found || value != user {
+		t.Fatalf("L1 Get remote = %#v, %v", value, found)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试提升经由 L1 的 Set 写入
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：容量淘汰调用 L1 的 onEvicted，L1 关闭后不再提升
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestTieredPromote(t *testing.T) {
+	l1, _ := NewCache(0, 0, WithMaxItems(1))
+	l2, _ := NewCache(0, 0)
+	var evicted []string
+	l1.OnEvictedReason(func(key string, value interface{}, reason Reason) {
+		if reason == Capacity {
+			evicted = append(evicted, key)
+		}
+	})
+	tiered := NewTieredCache(l1, l2)
+	l1.Set("old", 1, 0)
+	l2.Set("new", 2, time.Minute)
+	if value, found, _ := tiered.Get("new"); !found || value != 2 {
+		t.Fatalf("Get = %v, %v", value, found)
+	}
+	if len(evicted) != 1 || evicted[0] != "old" {
+		t.Fatalf("evicted = %v, want [old]", evicted)
+	}
+	if ttl := l1.Expirations()["new"]; ttl <= 0 || ttl > time.Minute {
+		t.Fatalf("L1 TTL = %v, want at most the L2 lifetime", ttl)
+	}
+
+	l1.Close()
+	l2.Set("late", 3, 0)
+	if value, found, _ := tiered.Get("late"); !found || value != 3 {
+		t.Fatalf("Get after L1 Close = %v, %v", value, found)
+	}
+	if l1.Has("late") {
+		t.Fatal("promoted into a closed L1")
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 GetFirst 按顺序返回第一个命中的值，GetFirstAndPromote 将其写入之前未命中的缓存
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：墓碑数据项视为未命中，不调用 loader；提升使用剩余的生命周期
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestGetFirst(t *testing.T) {
+	first, _ := NewCache(0, 0, WithLoader(func(key string) (interface{}, error) {
+		t.Errorf("GetFirst called the loader for %s", key)
+		return nil, ErrKeyNotFound
+	}))
+	second, _ := NewCache(0, 0)
+	third, _ := NewCache(0, 0)
+	second.Set("k", "second", time.Minute)
+	third.Set("k", "third", 0)
+	third.Set("only", "third", 0)
+
+	if value, found := GetFirst("k", first, second, third); !found || value != "second" {
+		t.Errorf("GetFirst(k) = %v, %v, want second", value, found)
+	}
+	if value, found := GetFirst("only", first, second, third); !found || value != "third" {
+		t.Errorf("GetFirst(only) = %v, %v, want third", value, found)
+	}
+	if _, found := GetFirst("missing", first, second, third); found {
+		t.Error("GetFirst(missing) found")
+	}
+	if first.Has("k") {
+		t.Error("GetFirst promoted k")
+	}
+	negative, _ := NewCache(0, 0, WithNegativeTTL(time.Hour), WithLoader(func(key string) (interface{}, error) {
+		return nil, ErrKeyNotFound
+	}))
+	negative.Get("k") // 存入墓碑数据项
+	if value, _ := GetFirst("k", negative, second); value != "second" {
+		t.Errorf("GetFirst past a tombstone = %v, want second", value)
+	}
+
+	if value, found := GetFirstAndPromote("k", first, second, third); !found || value != "second" {
+		t.Fatalf("GetFirstAndPromote(k) = %v, %v, want second", value, found)
+	}
+	if ttl := first.Expirations()["k"]; ttl <= 59*time.Second || ttl > time.Minute {
+		t.Errorf("promoted ttl = %v, want about 1m", ttl)
+	}
+	GetFirstAndPromote("only", first, second, third)
+	if second.Expirations()["only"] != NoExpiration {
+		t.Error("promoted item without expiration got a ttl")
+	}
+}
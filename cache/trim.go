@@ -0,0 +1,298 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：trim.go
+ * 内容摘要：按数量或字节数裁剪缓存，按淘汰策略(默认 LRU，见 policy.go)选择移出的数据项。
+ * 其他说明：缓存本身不感知内存压力，调用者可以结合 runtime.ReadMemStats 在内存紧张时
+ *           调用 TrimToCount/TrimToBytes 主动释放数据项。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"container/heap"
+	"sort"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+type trimEntry struct { // 待裁剪的数据项
+	key  string // 数据项键名
+	item Item   // 数据项
+	size int64  // 数据项的估算字节数，仅 TrimToBytes 使用
+}
+
+type victimHeap struct { // 选择待移出数据项的堆，堆顶为已选出的数据项中最应保留的
+	entries []trimEntry          // 已选出的数据项
+	before  func(a, b Item) bool // 淘汰策略，a 应先于 b 被移出时为 true
+}
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：裁剪缓存，使数据项数量不超过 n
+ * 输入参数：目标数量：n int
+ * 输出参数：无
+ * 返 回 值：int 被移出的数据项数量
+ * 其他说明：该函数为 Cache 类方法，按淘汰策略移出数据项(见 WithEvictionPolicy)，
+ *           释放锁后对被移出的数据项调用 onEvicted(Capacity)；n < 0 时按 0 处理
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) TrimToCount(n int) int {
+	if n < 0 {
+		n = 0
+	}
+	if thisCache.lockWithTimeout() != nil {
+		return 0
+	}
+	if len(thisCache.items) <= n {
+		thisCache.unlock()
+		return 0
+	}
+	entries := thisCache.trimEntries(false)
+	evicted := thisCache.trim(entries[:len(entries)-n])
+	onEvicted := thisCache.onEvicted
+	thisCache.unlock()
+
+	fireEvicted(onEvicted, evicted, Capacity)
+	return len(entries) - n
+}
+
+/***************************************************************************************
+ * 功能描述：裁剪缓存，使数据项的估算总字节数不超过 n
+ * 输入参数：目标字节数：n int64
+ * 输出参数：无
+ * 返 回 值：int 被移出的数据项数量
+ * 其他说明：该函数为 Cache 类方法，按淘汰策略移出数据项(见 WithEvictionPolicy)，
+ *           释放锁后对被移出的数据项调用 onEvicted(Capacity)；
+ *           数据项的大小与 EstimatedBytes 的计算方式相同，为键名长度与值的估算大小之和；
+ *           需要在写锁内估算每个数据项的大小，数据项较多时开销较大，不宜频繁调用
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) TrimToBytes(n int64) int {
+	if thisCache.lockWithTimeout() != nil {
+		return 0
+	}
+	entries := thisCache.trimEntries(true)
+	var total int64
+	for _, entry := range entries {
+		total += entry.size
+	}
+	count := 0
+	for ; count < len(entries) && total > n; count++ {
+		total -= entries[count].size
+	}
+	evicted := thisCache.trim(entries[:count])
+	onEvicted := thisCache.onEvicted
+	thisCache.unlock()
+
+	fireEvicted(onEvicted, evicted, Capacity)
+	return count
+}
+
+/***************************************************************************************
+ * 功能描述：数据项数量超过 WithMaxItems 的上限时按淘汰策略批量移出数据项，调用者需持有写锁
+ * 输入参数：刚写入的键名：keep string
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，由 putItem 调用；一次移出 evictBatch 个数据项，
+ *           数量降至 maxItems-evictBatch+1，之后的 evictBatch-1 次插入不再触发移出；
+ *           每次移出都要遍历全部数据项(见 victims)，批量越大单次插入的平均开销越小；
+ *           刚写入的数据项 keep 不会被移出。被移出的数据项记入 overflow，由 unlock 调用 onEvicted
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) evictOverflow(keep string) {
+	count := len(thisCache.items) - thisCache.maxItems + thisCache.evictBatch - 1
+	evicted := thisCache.trim(thisCache.victims(count, keep))
+	if thisCache.onEvicted != nil {
+		thisCache.overflow = append(thisCache.overflow, evicted...)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：按淘汰策略选出最先应移出的 count 个数据项，调用者需持有写锁
+ * 输入参数：数量：count int, 不移出的键名：keep string
+ * 输出参数：无
+ * 返 回 值：选出的数据项，顺序不确定；数据项不足时为除 keep 外的全部数据项
+ * 其他说明：该函数为 Cache 类方法，遍历一次全部数据项并维护大小为 count 的堆，
+ *           开销为 O(n log count)，count 为 1 时即线性查找；Random 策略取遍历到的前 count 个
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) victims(count int, keep string) []trimEntry {
+	if count <= 0 {
+		return nil
+	}
+	selected := &victimHeap{before: thisCache.evictBefore}
+	for key, item := range thisCache.items {
+		if key == keep {
+			continue
+		}
+		if len(selected.entries) < count {
+			heap.Push(selected, trimEntry{key: key, item: item})
+			continue
+		}
+		if thisCache.policy == Random {
+			break
+		}
+		if thisCache.evictBefore(item, selected.entries[0].item) {
+			selected.entries[0] = trimEntry{key: key, item: item}
+			heap.Fix(selected, 0)
+		}
+	}
+	return selected.entries
+}
+
+/***************************************************************************************
+ * 功能描述：收集所有数据项并按淘汰策略排序，最先应移出的在前，调用者需持有写锁
+ * 输入参数：是否计算字节数：sized bool
+ * 输出参数：无
+ * 返 回 值：排序后的数据项
+ * 其他说明：该函数为 Cache 类方法，Random 策略不排序
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) trimEntries(sized bool) []trimEntry {
+	entries := make([]trimEntry, 0, len(thisCache.items))
+	for key, item := range thisCache.items {
+		entry := trimEntry{key: key, item: item}
+		if sized && !item.negative() {
+			entry.size = int64(len(key)) + thisCache.estimateSize(item.Object)
+		}
+		entries = append(entries, entry)
+	}
+	if thisCache.policy != Random {
+		sort.Slice(entries, func(i, j int) bool {
+			return thisCache.evictBefore(entries[i].item, entries[j].item)
+		})
+	}
+	return entries
+}
+
+/***************************************************************************************
+ * 功能描述：移出一组数据项，调用者需持有写锁
+ * 输入参数：待移出的数据项：entries []trimEntry
+ * 输出参数：无
+ * 返 回 值：需要调用 onEvicted 的数据项，墓碑数据项不包含在内
+ * 其他说明：该函数为 Cache 类方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) trim(entries []trimEntry) []keyAndValue {
+	evicted := make([]keyAndValue, 0, len(entries))
+	for _, entry := range entries {
+		delete(thisCache.items, entry.key)
+		thisCache.markDirty()
+		thisCache.takeExpireHook(entry.key)
+		thisCache.count.Add(-1)
+		thisCache.unindexItem(entry.key)
+		thisCache.removeQuota(entry.key)
+		thisCache.logDelete(entry.key)
+		if !entry.item.negative() {
+			thisCache.notify(EventDeleted, entry.key, entry.item.Object)
+			evicted = append(evicted, keyAndValue{entry.key, entry.item.Object})
+		}
+	}
+	if len(evicted) > 0 {
+		thisCache.logger.Debugf("cache trim: evicted %d items", len(evicted))
+	}
+	return evicted
+}
+
+/***************************************************************************************
+ * 功能描述：获取堆中数据项的数量
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：数量
+ * 其他说明：该函数为 victimHeap 类方法，实现 heap.Interface
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisHeap *victimHeap) Len() int {
+	return len(thisHeap.entries)
+}
+
+/***************************************************************************************
+ * 功能描述：比较堆中的两个数据项
+ * 输入参数：下标：i int, 下标：j int
+ * 输出参数：无
+ * 返 回 值：i 应晚于 j 被移出时为 true，使堆顶为最应保留的数据项
+ * 其他说明：该函数为 victimHeap 类方法，实现 heap.Interface
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisHeap *victimHeap) Less(i, j int) bool {
+	return thisHeap.before(thisHeap.entries[j].item, thisHeap.entries[i].item)
+}
+
+/***************************************************************************************
+ * 功能描述：交换堆中的两个数据项
+ * 输入参数：下标：i int, 下标：j int
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 victimHeap 类方法，实现 heap.Interface
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisHeap *victimHeap) Swap(i, j int) {
+	thisHeap.entries[i], thisHeap.entries[j] = thisHeap.entries[j], thisHeap.entries[i]
+}
+
+/***************************************************************************************
+ * 功能描述：向堆中加入数据项
+ * 输入参数：数据项：x interface{}，类型为 trimEntry
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 victimHeap 类方法，实现 heap.Interface，由 heap.Push 调用
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisHeap *victimHeap) Push(x interface{}) {
+	thisHeap.entries = append(thisHeap.entries, x.(trimEntry))
+}
+
+/***************************************************************************************
+ * 功能描述：移出堆中最后一个数据项
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：被移出的数据项，类型为 trimEntry
+ * 其他说明：该函数为 victimHeap 类方法，实现 heap.Interface，由 heap.Pop 调用
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisHeap *victimHeap) Pop() interface{} {
+	last := thisHeap.entries[len(thisHeap.entries)-1]
+	thisHeap.entries = thisHeap.entries[:len(thisHeap.entries)-1]
+	return last
+}
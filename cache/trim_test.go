@@ -0,0 +1,219 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：trim_test.go
+ * 内容摘要：数量上限、淘汰策略与裁剪测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+/***************************************************************************************
+ * 功能描述：测试 WithMaxItems 默认按 LRU 移出，且不需要显式启用 WithAccessTracking
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：刚读取过的最早写入的数据项应被保留
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestMaxItemsLRU(t *testing.T) {
+	cacher, _ := NewCache(0, 0, WithMaxItems(3))
+	cacher.Set("a", 1, 0)
+	cacher.Set("b", 2, 0)
+	cacher.Set("c", 3, 0)
+	cacher.Get("a")
+	cacher.Set("d", 4, 0)
+
+	if !cacher.Has("a") {
+		t.Fatal("recently read key a was evicted")
+	}
+	if cacher.Has("b") {
+		t.Fatal("least recently used key b was kept")
+	}
+	if got := cacher.Count(); got != 3 {
+		t.Fatalf("Count = %d, want 3", got)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试各淘汰策略移出的数据项
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：a 最早写入且被读取 2 次，b 被读取 1 次，c 未被读取
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestEvictionPolicies(t *testing.T) {
+	tests := []struct {
+		policy     EvictionPolicy
+		candidates string // 可能被移出的键名，Random 可以移出任意一个旧数据项
+	}{
+		{LRU, "c"},
+		{FIFO, "a"},
+		{LFU, "c"},
+		{Random, "abc"},
+	}
+	for _, test := range tests {
+		cacher, _ := NewCache(0, 0, WithMaxItems(3), WithEvictionPolicy(test.policy))
+		cacher.Set("a", 1, 0)
+		cacher.Set("b", 2, 0)
+		cacher.Set("c", 3, 0)
+		cacher.Get("a")
+		cacher.Get("a")
+		cacher.Get("b")
+		cacher.Set("d", 4, 0)
+		var evicted []string
+		for _, key := range []string{"a", "b", "c", "d"} {
+			if !cacher.Has(key) {
+				evicted = append(evicted, key)
+			}
+		}
+		if len(evicted) != 1 || !strings.Contains(test.candidates, evicted[0]) {
+			t.Fatalf("policy %d: evicted %v, want one of %q", test.policy, evicted, test.candidates)
+		}
+	}
+
+	cacher, _ := NewCache(0, 0, WithMaxItems(3), WithEvictionPolicy(Random))
+	for i := 0; i < 10; i++ {
+		key := fmt.Sprint(i)
+		cacher.Set(key, i, 0)
+		if !cacher.Has(key) || cacher.Count() > 3 {
+			t.Fatalf("Random: after Set(%s) keys %v", key, cacher.SortedKeys())
+		}
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 WithEvictionBatch 一次移出多个最先应移出的数据项
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestEvictionBatch(t *testing.T) {
+	var evicted []string
+	cacher, _ := NewCache(0, 0, WithMaxItems(10), WithEvictionBatch(4), WithEvictionPolicy(FIFO))
+	cacher.OnEvicted(func(key string, value interface{}) {
+		evicted = append(evicted, key)
+	})
+	for i := 0; i < 11; i++ {
+		cacher.Set(fmt.Sprintf("k%02d", i), i, 0)
+	}
+	sort.Strings(evicted)
+	if fmt.Sprint(evicted) != "[k00 k01 k02 k03]" {
+		t.Fatalf("evicted %v, want the 4 oldest keys", evicted)
+	}
+	if got := cacher.Count(); got != 7 {
+		t.Fatalf("Count = %d, want 7", got)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 victims 选出的数据项与完整排序的前 count 个相同
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestVictims(t *testing.T) {
+	cacher, _ := NewCache(0, 0)
+	for i := 0; i < 100; i++ {
+		cacher.Set(fmt.Sprintf("k%03d", (i*37)%100), i, 0)
+	}
+	entries := cacher.trimEntries(false)
+	for _, count := range []int{1, 5, 99, 150} {
+		selected := cacher.victims(count, "")
+		keys := make([]string, 0, len(selected))
+		for _, entry := range selected {
+			keys = append(keys, entry.key)
+		}
+		want := make([]string, 0, count)
+		for i := 0; i < count && i < len(entries); i++ {
+			want = append(want, entries[i].key)
+		}
+		sort.Strings(keys)
+		sort.Strings(want)
+		if fmt.Sprint(keys) != fmt.Sprint(want) {
+			t.Fatalf("victims(%d) = %v, want %v", count, keys, want)
+		}
+	}
+	if selected := cacher.victims(100, "k000"); len(selected) != 99 {
+		t.Fatalf("victims excluding keep returned %d entries, want 99", len(selected))
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 TrimToCount 与 TrimToBytes
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestTrim(t *testing.T) {
+	cacher, _ := NewCache(0, 0, WithEvictionPolicy(FIFO))
+	for i := 0; i < 10; i++ {
+		cacher.Set(fmt.Sprint(i), i, 0)
+	}
+	if removed := cacher.TrimToCount(4); removed != 6 || cacher.Count() != 4 {
+		t.Fatalf("TrimToCount(4) removed %d, Count %d", removed, cacher.Count())
+	}
+	if !cacher.Has("9") || cacher.Has("0") {
+		t.Fatalf("TrimToCount kept %v, want the newest keys", cacher.SortedKeys())
+	}
+	if removed := cacher.TrimToBytes(0); removed != 4 || cacher.Count() != 0 {
+		t.Fatalf("TrimToBytes(0) removed %d, Count %d", removed, cacher.Count())
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：比较不同移出批量下达到数量上限后每次写入的开销
+ * 输入参数：b *testing.B
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func BenchmarkEvictionBatch(b *testing.B) {
+	for _, batch := range []int{1, 64} {
+		b.Run(fmt.Sprintf("batch=%d", batch), func(b *testing.B) {
+			cacher, _ := NewCache(0, 0, WithMaxItems(1000), WithEvictionBatch(batch))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				cacher.Set(fmt.Sprintf("key%d", i), i, 0)
+			}
+		})
+	}
+}
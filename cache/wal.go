@@ -0,0 +1,381 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：wal.go
+ * 内容摘要：增量持久化日志(write-ahead log)，每次写入、删除都追加一条记录到日志文件。
+ * 其他说明：记录格式为 操作类型(1 字节) uvarint(键名长度) 键名 [uvarint(数据项长度) 数据项]，
+ *           数据项使用 WithCodec 设置的编解码器编码；写日志在持有写锁时进行，以保证记录的顺序
+ *           与内存中的修改顺序一致，因此每次写入都多了一次编码与一次 write 系统调用。
+ *           日志只记录数据项的值与过期时间的变化，访问时间的变化不记录；墓碑数据项记录为删除。
+ *           日志写入失败只记录错误日志，不影响内存中的修改。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+const (
+	walSet    byte = 's' // 写入数据项
+	walDelete byte = 'd' // 删除数据项
+	walFlush  byte = 'f' // 清空缓存
+)
+
+const walCompactMin = 1024 // 日志记录数不超过该数量时 gcLoop 不自动压缩
+
+type writeLog struct { // 增量持久化日志，所有方法须在持有缓存写锁时调用
+	path    string       // 日志文件路径
+	fp      *os.File     // 以追加方式打开的日志文件
+	codec   Codec        // 数据项的编解码器
+	records int          // 自上次压缩以来写入的记录数
+	buf     bytes.Buffer // 记录的编码缓冲区
+}
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：打开增量持久化日志
+ * 输入参数：日志文件路径：path string, 编解码器：codec Codec
+ * 输出参数：无
+ * 返 回 值：日志，无 error 则为 nil
+ * 其他说明：文件不存在时创建，已存在时在末尾追加
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func openWriteLog(path string, codec Codec) (*writeLog, error) {
+	fp, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &writeLog{path: path, fp: fp, codec: codec}, nil
+}
+
+/***************************************************************************************
+ * 功能描述：追加一条记录
+ * 输入参数：操作类型：op byte, 数据项键名：key string, 数据项：item *Item，删除与清空时为 nil
+ * 输出参数：无
+ * 返 回 值：无 error， 则为 nil
+ * 其他说明：该函数为 writeLog 类方法，整条记录一次写入
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisLog *writeLog) append(op byte, key string, item *Item) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("Error registring item type with Gob lib.")
+		}
+	}()
+	thisLog.buf.Reset()
+	thisLog.buf.WriteByte(op)
+	if err = writeRecord(&thisLog.buf, []byte(key)); err != nil {
+		return err
+	}
+	if item != nil {
+		var data bytes.Buffer
+		if err = thisLog.codec.NewEncoder(&data).Encode(item); err != nil {
+			return err
+		}
+		if err = writeRecord(&thisLog.buf, data.Bytes()); err != nil {
+			return err
+		}
+	}
+	if _, err = thisLog.fp.Write(thisLog.buf.Bytes()); err != nil {
+		return err
+	}
+	thisLog.records++
+	return nil
+}
+
+/***************************************************************************************
+ * 功能描述：记录数据项的写入，调用时须持有写锁
+ * 输入参数：数据项键名：key string, 数据项：item Item
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，未启用日志时不做任何事；墓碑数据项记录为删除
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) logSet(key string, item Item) {
+	if thisCache.wal == nil {
+		return
+	}
+	if item.negative() {
+		thisCache.logDelete(key)
+		return
+	}
+	entry := item // 只在启用日志时分配，避免每次写入都把参数逃逸到堆上
+	if err := thisCache.wal.append(walSet, key, &entry); err != nil {
+		thisCache.logger.Errorf("cache wal set %s: %v", key, err)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：记录数据项的删除，调用时须持有写锁
+ * 输入参数：数据项键名：key string
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，未启用日志时不做任何事
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) logDelete(key string) {
+	if thisCache.wal == nil {
+		return
+	}
+	if err := thisCache.wal.append(walDelete, key, nil); err != nil {
+		thisCache.logger.Errorf("cache wal delete %s: %v", key, err)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：记录缓存被清空，调用时须持有写锁
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，未启用日志时不做任何事
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) logFlush() {
+	if thisCache.wal == nil {
+		return
+	}
+	if err := thisCache.wal.append(walFlush, "", nil); err != nil {
+		thisCache.logger.Errorf("cache wal flush: %v", err)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：重放增量持久化日志，恢复缓存数据项
+ * 输入参数：日志文件路径：path string
+ * 输出参数：无
+ * 返 回 值：无 error， 则为 nil
+ * 其他说明：该函数为 Cache 类方法，先在内存中按顺序重放全部记录，再在一次写锁内存入
+ *           重放结果中未过期的数据项，覆盖缓存中的同名数据项；重放本身不再写入日志；
+ *           日志末尾不完整的记录(写入时进程退出)被忽略
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) LoadFromLog(path string) error {
+	if len(path) == 0 {
+		err := ErrFileInvalid
+		return err
+	}
+	fp, err := os.Open(path)
+	if err != nil {
+		thisCache.logger.Errorf("cache load log %s: %v", path, err)
+		return err
+	}
+	defer fp.Close()
+
+	items, err := replayLog(bufio.NewReader(fp), thisCache.codec)
+	if err != nil {
+		thisCache.logger.Errorf("cache load log %s: %v", path, err)
+		return err
+	}
+
+	now := nanotime()
+	if err = thisCache.lockWithTimeout(); err != nil {
+		return err
+	}
+	defer thisCache.unlock()
+	wal := thisCache.wal
+	thisCache.wal = nil // 重放的数据项已在日志中
+	for key, item := range items {
+		if thisCache.expired(item, now) {
+			continue
+		}
+		item.LastAccess = now
+		item.Version = thisCache.nextVersion()
+		thisCache.putItem(key, item)
+	}
+	thisCache.wal = wal
+	return nil
+}
+
+/***************************************************************************************
+ * 功能描述：按顺序重放日志记录
+ * 输入参数：rd *bufio.Reader, 编解码器：codec Codec
+ * 输出参数：无
+ * 返 回 值：重放后的数据项，无 error 则为 nil
+ * 其他说明：日志末尾不完整的记录被忽略
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func replayLog(rd *bufio.Reader, codec Codec) (map[string]Item, error) {
+	items := map[string]Item{}
+	for {
+		op, err := rd.ReadByte()
+		if err == io.EOF {
+			return items, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		key, err := readRecord(rd)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return items, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch op {
+		case walSet:
+			data, err := readRecord(rd)
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return items, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			var item Item
+			if err = codec.NewDecoder(bytes.NewReader(data)).Decode(&item); err != nil {
+				return nil, err
+			}
+			items[string(key)] = item
+		case walDelete:
+			delete(items, string(key))
+		case walFlush:
+			items = map[string]Item{}
+		default:
+			return nil, errStreamRecord
+		}
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：压缩增量持久化日志，将日志重写为当前缓存的快照
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：无 error， 则为 nil
+ * 其他说明：该函数为 Cache 类方法，先写入临时文件再重命名替换原日志，失败时原日志不变；
+ *           整个过程持有写锁，期间其它写入被阻塞；未启用日志时直接返回
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) CompactLog() (err error) {
+	if err = thisCache.lockWithTimeout(); err != nil {
+		return err
+	}
+	defer thisCache.unlock()
+	if thisCache.wal == nil {
+		return nil
+	}
+	defer func() {
+		if err != nil {
+			thisCache.logger.Errorf("cache compact log: %v", err)
+		}
+	}()
+
+	path := thisCache.wal.path
+	fresh, err := openWriteLog(path+".tmp", thisCache.codec)
+	if err != nil {
+		return err
+	}
+	if err = fresh.fp.Truncate(0); err != nil {
+		fresh.fp.Close()
+		return err
+	}
+	now := nanotime()
+	for key, item := range thisCache.items {
+		if thisCache.expired(item, now) || item.negative() {
+			continue
+		}
+		if err = fresh.append(walSet, key, &item); err != nil {
+			fresh.fp.Close()
+			os.Remove(fresh.path)
+			return err
+		}
+	}
+	if err = fresh.fp.Sync(); err != nil {
+		fresh.fp.Close()
+		os.Remove(fresh.path)
+		return err
+	}
+	if err = os.Rename(fresh.path, path); err != nil {
+		fresh.fp.Close()
+		os.Remove(fresh.path)
+		return err
+	}
+	thisCache.wal.fp.Close()
+	fresh.path = path
+	fresh.records = 0
+	thisCache.wal = fresh
+	return nil
+}
+
+/***************************************************************************************
+ * 功能描述：日志记录数远多于数据项数量时压缩日志
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，由 gcLoop 在每次清理后调用，
+ *           记录数超过 walCompactMin 且超过数据项数量的 2 倍时调用 CompactLog
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) maybeCompactLog() {
+	thisCache.mux.RLock()
+	records := 0
+	if thisCache.wal != nil {
+		records = thisCache.wal.records
+	}
+	thisCache.mux.RUnlock()
+	if records > walCompactMin && int64(records) > 2*thisCache.count.Load() {
+		thisCache.CompactLog()
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：关闭增量持久化日志
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：无 error， 则为 nil
+ * 其他说明：该函数为 Cache 类方法，关闭后的写入不再记录；未启用日志时直接返回
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) CloseLog() error {
+	thisCache.lock()
+	defer thisCache.unlock()
+	if thisCache.wal == nil {
+		return nil
+	}
+	err := thisCache.wal.fp.Close()
+	thisCache.wal = nil
+	return err
+}
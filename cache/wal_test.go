@@ -0,0 +1,124 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：wal_test.go
+ * 内容摘要：增量持久化日志测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+/***************************************************************************************
+ * 功能描述：测试重放日志时删除与清空记录生效
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：删除记录移除之前写入的数据项，清空记录丢弃之前的全部数据项
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestWriteAheadLogReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.wal")
+	cacher, err := NewCache(0, 0, WithWriteAheadLog(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cacher.Set("a", 1, 0)
+	cacher.Set("b", 2, 0)
+	cacher.Set("c", 3, 0)
+	cacher.Delete("b")
+	cacher.Set("c", 30, 0)
+	if err = cacher.CloseLog(); err != nil {
+		t.Fatal(err)
+	}
+
+	replayed, _ := NewCache(0, 0)
+	if err = replayed.LoadFromLog(path); err != nil {
+		t.Fatal(err)
+	}
+	if got := replayed.Count(); got != 2 {
+		t.Errorf("Count = %d, want 2", got)
+	}
+	if _, found, _ := replayed.Get("b"); found {
+		t.Error("deleted b replayed")
+	}
+	if value, _, _ := replayed.Get("c"); value != 30 {
+		t.Errorf("c = %v, want 30", value)
+	}
+
+	flushed, _ := NewCache(0, 0, WithWriteAheadLog(path))
+	flushed.Flush()
+	flushed.Set("d", 4, 0)
+	flushed.CloseLog()
+	replayed, _ = NewCache(0, 0)
+	if err = replayed.LoadFromLog(path); err != nil {
+		t.Fatal(err)
+	}
+	if got := replayed.Count(); got != 1 {
+		t.Errorf("Count after flush record = %d, want 1", got)
+	}
+}
+
+/***************************************************************************************
+ * 功能描述：测试 CompactLog 将日志重写为当前数据项后重放结果不变，且之后的写入追加到新日志
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：无
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestCompactLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.wal")
+	cacher, err := NewCache(0, 0, WithWriteAheadLog(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		cacher.Set("a", i, 0)
+		cacher.Set("b", i, 0)
+	}
+	cacher.Delete("b")
+	before, _ := os.Stat(path)
+	if err = cacher.CompactLog(); err != nil {
+		t.Fatal(err)
+	}
+	after, _ := os.Stat(path)
+	if after.Size() >= before.Size() {
+		t.Errorf("log size %d after compaction, want less than %d", after.Size(), before.Size())
+	}
+	if _, err = os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary log left behind: %v", err)
+	}
+	cacher.Set("c", 3, 0)
+	cacher.CloseLog()
+
+	replayed, _ := NewCache(0, 0)
+	if err = replayed.LoadFromLog(path); err != nil {
+		t.Fatal(err)
+	}
+	if got := replayed.Count(); got != 2 {
+		t.Errorf("Count = %d, want 2", got)
+	}
+	if value, _, _ := replayed.Get("a"); value != 99 {
+		t.Errorf("a = %v, want 99", value)
+	}
+	if value, _, _ := replayed.Get("c"); value != 3 {
+		t.Errorf("c = %v, want 3", value)
+	}
+}
@@ -0,0 +1,113 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：warm.go
+ * 内容摘要：在后台从快照文件预热缓存。
+ * 其他说明：启动时同步调用 LoadFileToMem 会在加载完成前无法提供服务，WarmFromFile 在后台 goroutine
+ *           中解码并分批写入，每批只短暂持有写锁，期间读写照常进行；预热期间由前台写入(或删除后
+ *           重新写入)的数据项优先，快照中的值不会覆盖它们。
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"os"
+)
+
+/***************************************************************************************/
+// 数据结构与常量
+
+const warmBatch = 256 // 预热时每次获取写锁写入的数据项数量
+
+/***************************************************************************************/
+
+/***************************************************************************************
+ * 功能描述：在后台从 SaveMemToFile 保存的文件预热缓存
+ * 输入参数：文件名：file string
+ * 输出参数：无
+ * 返 回 值：无 error， 则为 nil；文件名为空或无法打开时返回错误，不启动预热
+ * 其他说明：该函数为 Cache 类方法，打开文件后立即返回，预热完成前 Warming 返回 true；
+ *           只写入缓存中不存在或已过期的键名，快照中已过期的数据项被丢弃；解码失败时记录日志并结束预热
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) WarmFromFile(file string) error {
+	if len(file) == 0 {
+		err := ErrFileInvalid
+		return err
+	}
+	fp, err := os.Open(file)
+	if err != nil {
+		thisCache.logger.Errorf("cache warm from %s: %v", file, err)
+		return err
+	}
+	thisCache.warming.Store(true)
+	go func() {
+		defer thisCache.warming.Store(false)
+		defer fp.Close()
+		items, err := thisCache.readSnapshot(fp)
+		if err != nil {
+			thisCache.logger.Errorf("cache warm from %s: %v", file, err)
+			return
+		}
+		thisCache.warm(items)
+		thisCache.logger.Debugf("cache warm from %s: loaded %d items", file, len(items))
+	}()
+	return nil
+}
+
+/***************************************************************************************
+ * 功能描述：分批写入预热的数据项
+ * 输入参数：数据项：items map[string]Item
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：该函数为 Cache 类方法，每 warmBatch 个数据项释放一次写锁，让等待的读写先执行
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) warm(items map[string]Item) {
+	batch := 0
+	thisCache.lock()
+	for key, val := range items {
+		if batch == warmBatch {
+			thisCache.unlock()
+			batch = 0
+			thisCache.lock()
+		}
+		batch++
+		now := nanotime()
+		if thisCache.expired(val, now) {
+			continue
+		}
+		if existing, found := thisCache.items[key]; found && !thisCache.expired(existing, now) {
+			continue // 前台写入的数据项优先
+		}
+		val.Version = thisCache.nextVersion()
+		thisCache.putItem(key, val)
+	}
+	thisCache.unlock()
+}
+
+/***************************************************************************************
+ * 功能描述：判断 WarmFromFile 启动的预热是否仍在进行
+ * 输入参数：无
+ * 输出参数：无
+ * 返 回 值：预热进行中为 true
+ * 其他说明：该函数为 Cache 类方法
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func (thisCache *Cache) Warming() bool {
+	return thisCache.warming.Load()
+}
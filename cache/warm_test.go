@@ -0,0 +1,69 @@
+package cache
+
+/*****************************************************************************************
+ * Golang 实现 缓存组件
+ *
+ * 系统环境：Linux x64/GO 1.21
+ * 文件名称：warm_test.go
+ * 内容摘要：后台预热测试。
+ * 其他说明：无
+ * 当前版本：1.0
+ * 作    者：xj
+ * 完成时期：2026.10.16
+ *
+ ****************************************************************************************/
+// 包
+import (
+	"errors"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+/***************************************************************************************
+ * 功能描述：测试从保存的文件在后台预热
+ * 输入参数：t *testing.T
+ * 输出参数：无
+ * 返 回 值：无
+ * 其他说明：前台写入的数据项优先；文件名为空或文件不存在时返回错误
+ *
+ * 修改日期      版本号      修改人      修改内容
+ * ------------------------------------------------------------------------------------
+ * 20261016      v1.0        xj      创建
+ * ************************************************************************************/
+func TestWarmFromFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "cache.dat")
+	source, _ := NewCache(0, 0)
+	for i := 0; i < 1000; i++ {
+		source.Set("key"+strconv.Itoa(i), i, 0)
+	}
+	source.Set("a", "saved", 0)
+	if err := source.SaveMemToFile(file); err != nil {
+		t.Fatal(err)
+	}
+
+	cacher, _ := NewCache(0, 0)
+	if err := cacher.WarmFromFile(""); !errors.Is(err, ErrFileInvalid) {
+		t.Errorf("WarmFromFile(\"\") = %v, want ErrFileInvalid", err)
+	}
+	if err := cacher.WarmFromFile(file + ".missing"); err == nil || cacher.Warming() {
+		t.Errorf("WarmFromFile of a missing file = %v, warming %v, want an error", err, cacher.Warming())
+	}
+	cacher.Set("a", "fresh", 0)
+	if err := cacher.WarmFromFile(file); err != nil {
+		t.Fatal(err)
+	}
+	for deadline := time.Now().Add(time.Second); cacher.Warming(); {
+		if time.Now().After(deadline) {
+			t.Fatal("warming did not finish")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if got, want := cacher.Count(), source.Count(); got != want {
+		t.Errorf("Count = %d, want %d", got, want)
+	}
+	if value, _, _ := cacher.Get("a"); value != "fresh" {
+		t.Errorf("a = %v, want fresh", value)
+	}
+}